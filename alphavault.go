@@ -0,0 +1,217 @@
+package meteora
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dammv2"
+)
+
+// AlphaVaultMode is the deposit mode of an alpha vault.
+type AlphaVaultMode string
+
+const (
+	// AlphaVaultProrata distributes tokens to depositors in proportion to
+	// their deposit, up to a buying cap.
+	AlphaVaultProrata AlphaVaultMode = "prorata"
+
+	// AlphaVaultFCFS accepts deposits first come, first served until the
+	// depositing cap is reached.
+	AlphaVaultFCFS AlphaVaultMode = "fcfs"
+)
+
+// Alpha vault pool types, as reported by dammv1.AlphaVault.PoolType. DLMM
+// vaults have pool type 0.
+const (
+	alphaVaultPoolDAMMv1 = 1
+	alphaVaultPoolDAMMv2 = 2
+)
+
+// AlphaVaultDetail is an alpha vault joined with its configuration and pool.
+type AlphaVaultDetail struct {
+	// Vault is the alpha vault.
+	Vault dammv1.AlphaVault `json:"vault"`
+
+	// Mode is the deposit mode of the vault's configuration, or empty if the
+	// configuration was not found.
+	Mode AlphaVaultMode `json:"mode"`
+
+	// Prorata is the vault's configuration when Mode is AlphaVaultProrata.
+	Prorata *dammv1.ProrataConfig `json:"prorata,omitempty"`
+
+	// FCFS is the vault's configuration when Mode is AlphaVaultFCFS.
+	FCFS *dammv1.FCFSConfig `json:"fcfs,omitempty"`
+
+	// Cap is the maximum total deposit in native token units: MaxBuyingCap
+	// for pro-rata vaults and MaxDepositingCap for FCFS vaults.
+	Cap int64 `json:"cap"`
+
+	// IndividualCap is the maximum deposit per wallet in native token units.
+	// It is zero for pro-rata vaults, which have no per-wallet cap.
+	IndividualCap int64 `json:"individual_cap"`
+
+	// EscrowFee is the fee for creating an escrow account, in lamports.
+	EscrowFee int `json:"escrow_fee"`
+
+	// DAMMv1Pool is the vault's pool for vaults launching on DAMM v1.
+	DAMMv1Pool *dammv1.Pool `json:"dammv1_pool,omitempty"`
+
+	// DAMMv2Pool is the vault's pool for vaults launching on DAMM v2.
+	DAMMv2Pool *dammv2.Pool `json:"dammv2_pool,omitempty"`
+
+	// Vesting is the vesting timeline. It is set when the configuration is
+	// known and the pool activates at a timestamp, which only DAMM v2 pools
+	// report. Use Timeline to compute it from an activation time obtained
+	// elsewhere.
+	Vesting *VestingTimeline `json:"vesting,omitempty"`
+
+	// Err is the error returned when fetching the vault's pool, if any.
+	Err error `json:"-"`
+}
+
+// VestingTimeline holds the key times of an alpha vault launch.
+type VestingTimeline struct {
+	// Activation is when the pool activates.
+	Activation time.Time `json:"activation"`
+
+	// LastJoin is when FCFS deposits close. It is zero for pro-rata vaults.
+	LastJoin time.Time `json:"last_join"`
+
+	// VestingStart is when purchased tokens start vesting.
+	VestingStart time.Time `json:"vesting_start"`
+
+	// VestingEnd is when purchased tokens are fully vested.
+	VestingEnd time.Time `json:"vesting_end"`
+}
+
+// Vested returns the fraction of purchased tokens vested at t, between 0 and
+// 1. Tokens vest linearly from VestingStart to VestingEnd.
+func (v *VestingTimeline) Vested(t time.Time) float64 {
+	switch {
+	case t.Before(v.VestingStart):
+		return 0
+	case !t.Before(v.VestingEnd):
+		return 1
+	default:
+		return float64(t.Sub(v.VestingStart)) / float64(v.VestingEnd.Sub(v.VestingStart))
+	}
+}
+
+// Timeline returns the vesting timeline of the vault for a pool activating at
+// activation, or nil if the vault's configuration is unknown.
+func (d *AlphaVaultDetail) Timeline(activation time.Time) *VestingTimeline {
+	var start, end, lastJoin int64
+	switch {
+	case d.Prorata != nil:
+		start, end = d.Prorata.StartVestingDuration, d.Prorata.EndVestingDuration
+	case d.FCFS != nil:
+		start, end = d.FCFS.StartVestingDuration, d.FCFS.EndVestingDuration
+		lastJoin = d.FCFS.DepositingDurationUntilLastJoinPoint
+	default:
+		return nil
+	}
+
+	t := &VestingTimeline{
+		Activation:   activation,
+		VestingStart: activation.Add(time.Duration(start) * time.Second),
+		VestingEnd:   activation.Add(time.Duration(end) * time.Second),
+	}
+	if d.FCFS != nil {
+		t.LastJoin = activation.Add(time.Duration(lastJoin) * time.Second)
+	}
+	return t
+}
+
+// resolveConfig attaches the configuration whose address is the vault's base
+// key. Alpha vaults created from a shared configuration are derived from the
+// configuration address; vaults created with a custom base are left
+// unresolved.
+func (d *AlphaVaultDetail) resolveConfig(configs *dammv1.AlphaVaultConfigs) {
+	for i, c := range configs.ProrataConfigs {
+		if c.Address == d.Vault.Base {
+			d.Mode, d.Prorata = AlphaVaultProrata, &configs.ProrataConfigs[i]
+			d.Cap, d.EscrowFee = c.MaxBuyingCap, c.EscrowFee
+			return
+		}
+	}
+	for i, c := range configs.FCFSConfigs {
+		if c.Address == d.Vault.Base {
+			d.Mode, d.FCFS = AlphaVaultFCFS, &configs.FCFSConfigs[i]
+			d.Cap, d.IndividualCap, d.EscrowFee = c.MaxDepositingCap, c.IndividualDepositingCap, c.EscrowFee
+			return
+		}
+	}
+}
+
+// ListAlphaVaultDetails lists alpha vaults with DAMMv1.ListAlphaVaults and
+// joins each with its configuration and pool. params may be nil.
+//
+// Configurations are matched on the vault's Base key. DAMM v1 pools are
+// fetched with DAMMv1.GetPools and DAMM v2 pools with DAMMv2.GetPools; a DAMM
+// v2 pool whose dammv2.Pool.AlphaVault names a different vault is reported
+// as an error. DLMM vaults are returned without a pool. A pool that fails to
+// load sets Err on its vaults instead of failing the list.
+func (c *Client) ListAlphaVaultDetails(ctx context.Context, params *dammv1.AlphaVaultParams) ([]AlphaVaultDetail, error) {
+	vaults, err := c.DAMMv1.ListAlphaVaults(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("meteora.ListAlphaVaultDetails: %w", err)
+	}
+	configs, err := c.DAMMv1.ListAlphaVaultConfigs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("meteora.ListAlphaVaultDetails: %w", err)
+	}
+
+	var v1Addresses, v2Addresses []string
+	for _, v := range vaults {
+		switch v.PoolType {
+		case alphaVaultPoolDAMMv1:
+			v1Addresses = append(v1Addresses, v.PoolAddress)
+		case alphaVaultPoolDAMMv2:
+			v2Addresses = append(v2Addresses, v.PoolAddress)
+		}
+	}
+	v1Pools := map[string]dammv1.PoolResult{}
+	if len(v1Addresses) > 0 {
+		for _, r := range c.DAMMv1.GetPools(ctx, v1Addresses, nil) {
+			v1Pools[r.Address] = r
+		}
+	}
+	v2Pools := map[string]dammv2.PoolResult{}
+	if len(v2Addresses) > 0 {
+		for _, r := range c.DAMMv2.GetPools(ctx, v2Addresses, nil) {
+			v2Pools[r.Address] = r
+		}
+	}
+
+	details := make([]AlphaVaultDetail, len(vaults))
+	for i, v := range vaults {
+		d := &details[i]
+		d.Vault = v
+		d.resolveConfig(configs)
+
+		var poolErr error
+		switch v.PoolType {
+		case alphaVaultPoolDAMMv1:
+			r := v1Pools[v.PoolAddress]
+			d.DAMMv1Pool, poolErr = r.Pool, r.Err
+		case alphaVaultPoolDAMMv2:
+			r := v2Pools[v.PoolAddress]
+			poolErr = r.Err
+			if r.Pool != nil && (r.Pool.AlphaVault == v.VaultAddress || r.Pool.AlphaVault == "") {
+				d.DAMMv2Pool = r.Pool
+			} else if r.Pool != nil {
+				poolErr = fmt.Errorf("pool %s belongs to alpha vault %s", v.PoolAddress, r.Pool.AlphaVault)
+			}
+		}
+		if poolErr != nil {
+			d.Err = fmt.Errorf("meteora.ListAlphaVaultDetails: %s: %w", v.VaultAddress, poolErr)
+		}
+
+		if p := d.DAMMv2Pool; p != nil && p.PoolConfig.ActivationType == dammv2.ActivationTypeTimestamp {
+			d.Vesting = d.Timeline(time.Unix(p.PoolConfig.ActivationPoint, 0))
+		}
+	}
+	return details, nil
+}
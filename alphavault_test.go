@@ -0,0 +1,145 @@
+package meteora_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	meteora "github.com/ua1984/meteora-go"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/meteoratest"
+)
+
+type AlphaVaultTestSuite struct {
+	suite.Suite
+	srv    *meteoratest.Server
+	client *meteora.Client
+}
+
+func TestAlphaVault(t *testing.T) {
+	suite.Run(t, new(AlphaVaultTestSuite))
+}
+
+const activation = int64(1_700_000_000)
+
+func (s *AlphaVaultTestSuite) SetupTest() {
+	s.srv = meteoratest.NewServer()
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DAMMv1.AlphaVaults = []dammv1.AlphaVault{
+			{VaultAddress: "av1", PoolAddress: "v1pool", Base: "prorata1", PoolType: 1},
+			{VaultAddress: "av2", PoolAddress: "v2pool", Base: "fcfs1", PoolType: 2},
+			{VaultAddress: "av3", PoolAddress: "dlmmpool", Base: "custom", PoolType: 0},
+			{VaultAddress: "av4", PoolAddress: "other", Base: "fcfs1", PoolType: 2},
+		}
+		d.DAMMv1.AlphaVaultConfigs = dammv1.AlphaVaultConfigs{
+			ProrataConfigs: []dammv1.ProrataConfig{
+				{Address: "prorata1", MaxBuyingCap: 1000, StartVestingDuration: 3600, EndVestingDuration: 7200, EscrowFee: 5},
+			},
+			FCFSConfigs: []dammv1.FCFSConfig{
+				{Address: "fcfs1", MaxDepositingCap: 500, IndividualDepositingCap: 50, StartVestingDuration: 60, EndVestingDuration: 160, DepositingDurationUntilLastJoinPoint: 30, EscrowFee: 7},
+			},
+		}
+		d.DAMMv1.Pools = []dammv1.Pool{{PoolAddress: "v1pool"}}
+		d.DAMMv2.Pools = []dammv2.Pool{
+			{Address: "v2pool", AlphaVault: "av2", PoolConfig: dammv2.PoolConfig{ActivationType: 2, ActivationPoint: activation}},
+			{Address: "other", AlphaVault: "someone-else"},
+		}
+	})
+	s.client = s.srv.Client()
+}
+
+func (s *AlphaVaultTestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func (s *AlphaVaultTestSuite) TestListAlphaVaultDetails() {
+	// Act
+	details, err := s.client.ListAlphaVaultDetails(context.Background(), nil)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(details, 4)
+
+	s.Run("should join a pro-rata vault with its DAMM v1 pool", func() {
+		d := details[0]
+		s.NoError(d.Err)
+		s.Equal(meteora.AlphaVaultProrata, d.Mode)
+		s.Equal(int64(1000), d.Cap)
+		s.Zero(d.IndividualCap)
+		s.Equal(5, d.EscrowFee)
+		s.Require().NotNil(d.DAMMv1Pool)
+		s.Equal("v1pool", d.DAMMv1Pool.PoolAddress)
+		s.Nil(d.Vesting)
+	})
+
+	s.Run("should join an FCFS vault with its DAMM v2 pool and vesting", func() {
+		d := details[1]
+		s.NoError(d.Err)
+		s.Equal(meteora.AlphaVaultFCFS, d.Mode)
+		s.Equal(int64(50), d.IndividualCap)
+		s.Require().NotNil(d.DAMMv2Pool)
+		s.Require().NotNil(d.Vesting)
+		s.Equal(time.Unix(activation, 0), d.Vesting.Activation)
+		s.Equal(time.Unix(activation+30, 0), d.Vesting.LastJoin)
+		s.Equal(time.Unix(activation+60, 0), d.Vesting.VestingStart)
+		s.Equal(time.Unix(activation+160, 0), d.Vesting.VestingEnd)
+	})
+
+	s.Run("should leave a vault with a custom base unresolved", func() {
+		d := details[2]
+		s.NoError(d.Err)
+		s.Empty(d.Mode)
+		s.Nil(d.Timeline(time.Unix(activation, 0)))
+		s.Nil(d.DAMMv1Pool)
+		s.Nil(d.DAMMv2Pool)
+	})
+
+	s.Run("should reject a DAMM v2 pool of another vault", func() {
+		d := details[3]
+		s.ErrorContains(d.Err, "meteora.ListAlphaVaultDetails: av4: pool other belongs to alpha vault someone-else")
+		s.Nil(d.DAMMv2Pool)
+	})
+}
+
+func (s *AlphaVaultTestSuite) TestListAlphaVaultDetailsPoolFailure() {
+	// Arrange
+	s.srv.InjectFault(meteoratest.Fault{Path: meteoratest.DAMMv2Prefix, Status: http.StatusBadRequest})
+
+	// Act
+	details, err := s.client.ListAlphaVaultDetails(context.Background(), &dammv1.AlphaVaultParams{VaultAddress: []string{"av1", "av2"}})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(details, 2)
+	s.NoError(details[0].Err)
+	s.ErrorContains(details[1].Err, "meteora.ListAlphaVaultDetails: av2")
+	s.Equal(meteora.AlphaVaultFCFS, details[1].Mode)
+}
+
+func (s *AlphaVaultTestSuite) TestVested() {
+	// Arrange
+	timeline := meteora.VestingTimeline{VestingStart: time.Unix(100, 0), VestingEnd: time.Unix(200, 0)}
+
+	tests := []struct {
+		name string
+		at   int64
+		want float64
+	}{
+		{name: "should be zero before vesting starts", at: 50, want: 0},
+		{name: "should vest linearly", at: 125, want: 0.25},
+		{name: "should be fully vested at the end", at: 200, want: 1},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			got := timeline.Vested(time.Unix(tt.at, 0))
+
+			// Assert
+			s.InDelta(tt.want, got, 1e-9)
+		})
+	}
+}
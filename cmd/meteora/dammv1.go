@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/ua1984/meteora-go/dammv1"
+)
+
+var dammv1PoolColumns = []string{"pool_address", "pool_name", "pool_type", "pool_tvl", "trading_volume", "fee_volume", "apr", "farming_apy"}
+
+// defaultSearchSize is the page size sent by the search command when none is
+// given, since the API requires one.
+const defaultSearchSize = 10
+
+// poolFilterFlags are the filters shared by the pools and search commands.
+type poolFilterFlags struct {
+	unknown      **bool
+	poolType     **string
+	isMonitoring **bool
+	minTVL       **float64
+	hideLowAPR   **bool
+	launchpad    *[]string
+}
+
+func addPoolFilterFlags(fs *flag.FlagSet) *poolFilterFlags {
+	return &poolFilterFlags{
+		unknown:      optBool(fs, "unknown", "include pools with unrecognized tokens"),
+		poolType:     optString(fs, "pool-type", "pool type: dynamic, multitoken, lst or farms"),
+		isMonitoring: optBool(fs, "monitoring", "only pools under monitoring"),
+		minTVL:       optFloat(fs, "min-tvl", "hide pools with a lower TVL in USD"),
+		hideLowAPR:   optBool(fs, "hide-low-apr", "hide pools with a low APR"),
+		launchpad:    list(fs, "launchpad", "launchpad addresses"),
+	}
+}
+
+var dammv1Commands = []command{
+	{
+		name: "pools", summary: "List pools, optionally by address", maxArgs: 0, columns: dammv1PoolColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			address := list(fs, "address", "pool addresses")
+			pf := addPoolFilterFlags(fs)
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				pools, err := e.client.DAMMv1.ListPools(ctx, &dammv1.ListPoolsParams{
+					Address: *address, Unknown: *pf.unknown, PoolType: *pf.poolType, IsMonitoring: *pf.isMonitoring,
+					HideLowTVL: *pf.minTVL, HideLowAPR: *pf.hideLowAPR, Launchpad: *pf.launchpad,
+				})
+				return nonNil(pools), err
+			}
+		},
+	},
+	{
+		name: "search", summary: "Search pools with sorting and pagination", maxArgs: 0, columns: dammv1PoolColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			filter := optString(fs, "filter", "free-text search")
+			sortKey := optString(fs, "sort-key", "sort by tvl, volume, fee_tvl_ratio or l_m")
+			order := optString(fs, "order", "sort direction: asc or desc")
+			top := list(fs, "top", "pool addresses to put first")
+			mints := list(fs, "mint", "only pools with one of these token mints")
+			pairs := list(fs, "pair", "only pools with one of these token pairs (mintA-mintB)")
+			pf := addPoolFilterFlags(fs)
+			pages := addPageFlags(fs, "size", 100)
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return fetchPages(e, pages, 0, 100, func(page, size *int) ([]dammv1.Pool, pageInfo, error) {
+					params := &dammv1.SearchParams{
+						Size: defaultSearchSize, Filter: *filter, SortKey: *sortKey, OrderBy: *order, PoolsToTop: *top,
+						Unknown: *pf.unknown, PoolType: *pf.poolType, IsMonitoring: *pf.isMonitoring, HideLowTVL: *pf.minTVL,
+						HideLowAPR: *pf.hideLowAPR, IncludeTokenMints: *mints, IncludePoolTokenPairs: *pairs, Launchpad: *pf.launchpad,
+					}
+					if page != nil {
+						params.Page = *page
+					}
+					if size != nil {
+						params.Size = *size
+					}
+					resp, err := e.client.DAMMv1.SearchPools(ctx, params)
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					more := (params.Page+1)*params.Size < resp.TotalCount
+					return resp.Data, pageInfo{page: resp.Page, total: int64(resp.TotalCount), more: more}, nil
+				})
+			}
+		},
+	},
+	{
+		name: "pool", args: "<address>...", summary: "Show pools by address", minArgs: 1, maxArgs: -1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				results := e.client.DAMMv1.GetPools(ctx, args, nil)
+				if len(args) == 1 {
+					return results[0].Pool, results[0].Err
+				}
+				return collect(e, results, func(r dammv1.PoolResult) (string, *dammv1.Pool, error) {
+					return r.Address, r.Pool, r.Err
+				})
+			}
+		},
+	},
+	{
+		name: "metrics", summary: "Show pool metrics by pool type", maxArgs: 0,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return e.client.DAMMv1.GetPoolsMetrics(ctx)
+			}
+		},
+	},
+	{
+		name: "configs", summary: "List pool configurations", maxArgs: 0,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				configs, err := e.client.DAMMv1.ListPoolConfigs(ctx)
+				return nonNil(configs), err
+			}
+		},
+	},
+	{
+		name: "fee-config", args: "<config>", summary: "List the fee configurations of a config address", minArgs: 1, maxArgs: 1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				configs, err := e.client.DAMMv1.GetFeeConfig(ctx, args[0])
+				return nonNil(configs), err
+			}
+		},
+	},
+	{
+		name: "farms", summary: "List pools with farms", maxArgs: 0, columns: dammv1PoolColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			pages := addPageFlags(fs, "size", 100)
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return fetchPages(e, pages, 1, 100, func(page, size *int) ([]dammv1.Pool, pageInfo, error) {
+					pools, err := e.client.DAMMv1.ListPoolsWithFarm(ctx, &dammv1.PaginationParams{Page: page, Size: size})
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					// The response has no total, so a full page may be followed by more.
+					info := pageInfo{more: size != nil && len(pools) == *size}
+					if page != nil {
+						info.page = *page
+					}
+					return pools, info, nil
+				})
+			}
+		},
+	},
+	{
+		name: "alpha-vaults", summary: "List alpha vaults", maxArgs: 0,
+		setup: func(fs *flag.FlagSet) runFunc {
+			vaults := list(fs, "vault", "vault addresses")
+			pools := list(fs, "pool", "pool addresses")
+			mints := list(fs, "mint", "base mints")
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				result, err := e.client.DAMMv1.ListAlphaVaults(ctx, &dammv1.AlphaVaultParams{
+					VaultAddress: *vaults, PoolAddress: *pools, BaseMint: *mints,
+				})
+				return nonNil(result), err
+			}
+		},
+	},
+	{
+		name: "alpha-vault-configs", summary: "Show alpha vault configurations", maxArgs: 0,
+		setup: func(fs *flag.FlagSet) runFunc {
+			mode := fs.String("mode", "", "only list the configs of one mode: prorata or fcfs")
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				configs, err := e.client.DAMMv1.ListAlphaVaultConfigs(ctx)
+				if err != nil {
+					return nil, err
+				}
+				switch *mode {
+				case "":
+					return configs, nil
+				case "prorata":
+					return nonNil(configs.ProrataConfigs), nil
+				case "fcfs":
+					return nonNil(configs.FCFSConfigs), nil
+				default:
+					return nil, fmt.Errorf("invalid --mode %q (want prorata or fcfs)", *mode)
+				}
+			}
+		},
+	},
+	{
+		name: "pools-by-lp", args: "<vault-lp>", summary: "List the pools holding a vault LP", minArgs: 1, maxArgs: 1,
+		columns: dammv1PoolColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				pools, err := e.client.DAMMv1.GetPoolsByVaultLP(ctx, args[0])
+				return nonNil(pools), err
+			}
+		},
+	},
+}
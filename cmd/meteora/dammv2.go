@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/ua1984/meteora-go/dammv2"
+)
+
+func dammv2PageInfo[T any](r *dammv2.PaginatedResponse[T]) pageInfo {
+	return pageInfo{page: r.CurrentPage, pages: r.Pages, total: int64(r.Total), more: r.CurrentPage < r.Pages}
+}
+
+var dammv2Commands = []command{
+	{
+		name: "pools", summary: "List pools", maxArgs: 0, columns: poolColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			sf := addSearchFlags(fs, 1000)
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return fetchPages(e, sf.pages, 1, 1000, func(page, size *int) ([]dammv2.Pool, pageInfo, error) {
+					resp, err := e.client.DAMMv2.ListPools(ctx, &dammv2.ListPoolsParams{
+						Page: page, PageSize: size, Query: *sf.query, FilterBy: *sf.filter, SortBy: *sf.sort,
+					})
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Data, dammv2PageInfo(resp), nil
+				})
+			}
+		},
+	},
+	{
+		name: "groups", summary: "List pool groups by token pair", maxArgs: 0, columns: groupColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			sf := addSearchFlags(fs, 100)
+			volumeTW := optString(fs, "volume-tw", "time window of total_volume, e.g. 24h")
+			feeTVLRatioTW := optString(fs, "fee-tvl-ratio-tw", "time window of max_fee_tvl_ratio, e.g. 24h")
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return fetchPages(e, sf.pages, 1, 100, func(page, size *int) ([]dammv2.PoolGroup, pageInfo, error) {
+					resp, err := e.client.DAMMv2.ListGroups(ctx, &dammv2.ListGroupsParams{
+						Page: page, PageSize: size, Query: *sf.query, FilterBy: *sf.filter, SortBy: *sf.sort,
+						VolumeTW: *volumeTW, FeeTVLRatioTW: *feeTVLRatioTW,
+					})
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Data, dammv2PageInfo(resp), nil
+				})
+			}
+		},
+	},
+	{
+		name: "group", args: "<mint-mint>", summary: "List the pools of a group (mints in lexical order, joined by -)",
+		minArgs: 1, maxArgs: 1, columns: poolColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			sf := addSearchFlags(fs, 1000)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return fetchPages(e, sf.pages, 1, 1000, func(page, size *int) ([]dammv2.Pool, pageInfo, error) {
+					resp, err := e.client.DAMMv2.GetGroup(ctx, args[0], &dammv2.GetGroupParams{
+						Page: page, PageSize: size, Query: *sf.query, FilterBy: *sf.filter, SortBy: *sf.sort,
+					})
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Data, dammv2PageInfo(resp), nil
+				})
+			}
+		},
+	},
+	{
+		name: "pool", args: "<address>...", summary: "Show pools by address, fetching several concurrently",
+		minArgs: 1, maxArgs: -1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				if len(args) == 1 {
+					return e.client.DAMMv2.GetPool(ctx, args[0])
+				}
+				return collect(e, e.client.DAMMv2.GetPools(ctx, args, nil), func(r dammv2.PoolResult) (string, *dammv2.Pool, error) {
+					return r.Address, r.Pool, r.Err
+				})
+			}
+		},
+	},
+	{
+		name: "ohlcv", args: "<address>", summary: "Show price candles of a pool", minArgs: 1, maxArgs: 1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			tf := addTimeFlags(fs)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				resp, err := e.client.DAMMv2.GetOHLCV(ctx, args[0], &dammv2.OHLCVParams{
+					Timeframe: *tf.timeframe, StartTime: *tf.from, EndTime: *tf.to,
+				})
+				if err != nil {
+					return nil, err
+				}
+				return nonNil(resp.Data), nil
+			}
+		},
+	},
+	{
+		name: "volume", args: "<address>", summary: "Show volume and fee history of a pool", minArgs: 1, maxArgs: 1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			tf := addTimeFlags(fs)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				resp, err := e.client.DAMMv2.GetVolumeHistory(ctx, args[0], &dammv2.VolumeHistoryParams{
+					Timeframe: *tf.timeframe, StartTime: *tf.from, EndTime: *tf.to,
+				})
+				if err != nil {
+					return nil, err
+				}
+				return nonNil(resp.Data), nil
+			}
+		},
+	},
+	{
+		name: "metrics", summary: "Show protocol metrics", maxArgs: 0,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return e.client.DAMMv2.GetProtocolMetrics(ctx)
+			}
+		},
+	},
+	{
+		name: "closed-positions", args: "<wallet>", summary: "List closed positions of a wallet", minArgs: 1, maxArgs: 1,
+		columns: closedPositionColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			cf := addCursorFlags(fs)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return fetchCursor(e, *cf.cursor, *cf.all, func(cursor *string) ([]dammv2.ClosedPosition, *string, error) {
+					resp, err := e.client.DAMMv2.GetClosedPositions(ctx, args[0], &dammv2.GetClosedPositionsParams{
+						StartTime: *cf.from, EndTime: *cf.to, Limit: *cf.limit, NextCursor: cursor, Pool: *cf.pool,
+					})
+					if err != nil {
+						return nil, nil, err
+					}
+					return resp.Data, resp.NextCursor, nil
+				})
+			}
+		},
+	},
+	{
+		name: "open-positions", args: "<wallet>", summary: "List open positions of a wallet, by pool", minArgs: 1, maxArgs: 1,
+		columns: []string{"pool_address", "name", "fee_pct", "pool_price", "positions"},
+		setup: func(fs *flag.FlagSet) runFunc {
+			pool := optString(fs, "pool", "only positions in this pool")
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				resp, err := e.client.DAMMv2.GetOpenPositions(ctx, args[0], &dammv2.GetOpenPositionsParams{Pool: *pool})
+				if err != nil {
+					return nil, err
+				}
+				return nonNil(resp.Data), nil
+			}
+		},
+	},
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+var (
+	poolColumns  = []string{"address", "name", "tvl", "volume.24h", "fees.24h", "fee_tvl_ratio.24h", "current_price"}
+	groupColumns = []string{"lexical_order_mints", "group_name", "pool_count", "total_tvl", "total_volume", "max_fee_tvl_ratio", "max_farm_apr"}
+
+	closedPositionColumns = []string{
+		"position_address", "pool_address", "created_at", "closed_at", "total_deposits.amount_usd",
+		"total_withdraws.amount_usd", "total_claimed_fees.amount_usd", "pnl",
+	}
+)
+
+// searchFlags are the flags shared by the datapi pool and group listings.
+type searchFlags struct {
+	query  **string
+	filter **string
+	sort   **string
+	pages  *pageFlags
+}
+
+func addSearchFlags(fs *flag.FlagSet, maxSize int) *searchFlags {
+	return &searchFlags{
+		query:  optString(fs, "query", "match pools by name, token or address"),
+		filter: optString(fs, "filter", "filter_by expression, e.g. 'tvl>1000 && is_blacklisted=false'"),
+		sort:   optString(fs, "sort", "sort_by expression, e.g. volume_24h:desc"),
+		pages:  addPageFlags(fs, "page-size", maxSize),
+	}
+}
+
+// timeFlags are the flags of the time series commands.
+type timeFlags struct {
+	timeframe **string
+	from, to  **int64
+}
+
+func addTimeFlags(fs *flag.FlagSet) *timeFlags {
+	return &timeFlags{
+		timeframe: optString(fs, "timeframe", "bucket size: 5m, 30m, 1h, 2h, 4h, 12h or 24h"),
+		from:      optTime(fs, "from", "start time"),
+		to:        optTime(fs, "to", "end time"),
+	}
+}
+
+// cursorFlags are the flags of the closed positions commands.
+type cursorFlags struct {
+	from, to **int64
+	limit    **int
+	cursor   **string
+	pool     **string
+	all      *bool
+}
+
+func addCursorFlags(fs *flag.FlagSet) *cursorFlags {
+	return &cursorFlags{
+		from:   optTime(fs, "from", "only positions closed at or after"),
+		to:     optTime(fs, "to", "only positions closed at or before"),
+		limit:  optInt(fs, "limit", "results per page"),
+		cursor: optString(fs, "cursor", "cursor returned by a previous page"),
+		pool:   optString(fs, "pool", "only positions in this pool"),
+		all:    fs.Bool("all", false, "follow pagination and return every page"),
+	}
+}
+
+func dlmmPageInfo[T any](r *dlmm.PaginatedResponse[T]) pageInfo {
+	return pageInfo{page: r.CurrentPage, pages: r.Pages, total: int64(r.Total), more: r.CurrentPage < r.Pages}
+}
+
+var dlmmCommands = []command{
+	{
+		name: "pools", summary: "List pools", maxArgs: 0, columns: poolColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			sf := addSearchFlags(fs, 1000)
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return fetchPages(e, sf.pages, 1, 1000, func(page, size *int) ([]dlmm.Pool, pageInfo, error) {
+					resp, err := e.client.DLMM.ListPools(ctx, &dlmm.ListPoolsParams{
+						Page: page, PageSize: size, Query: *sf.query, FilterBy: *sf.filter, SortBy: *sf.sort,
+					})
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Data, dlmmPageInfo(resp), nil
+				})
+			}
+		},
+	},
+	{
+		name: "groups", summary: "List pool groups by token pair", maxArgs: 0, columns: groupColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			sf := addSearchFlags(fs, 100)
+			volumeTW := optString(fs, "volume-tw", "time window of total_volume, e.g. 24h")
+			feeTVLRatioTW := optString(fs, "fee-tvl-ratio-tw", "time window of max_fee_tvl_ratio, e.g. 24h")
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return fetchPages(e, sf.pages, 1, 100, func(page, size *int) ([]dlmm.PoolGroup, pageInfo, error) {
+					resp, err := e.client.DLMM.ListGroups(ctx, &dlmm.ListGroupsParams{
+						Page: page, PageSize: size, Query: *sf.query, FilterBy: *sf.filter, SortBy: *sf.sort,
+						VolumeTW: *volumeTW, FeeTVLRatioTW: *feeTVLRatioTW,
+					})
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Data, dlmmPageInfo(resp), nil
+				})
+			}
+		},
+	},
+	{
+		name: "group", args: "<mint-mint>", summary: "List the pools of a group (mints in lexical order, joined by -)",
+		minArgs: 1, maxArgs: 1, columns: poolColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			sf := addSearchFlags(fs, 1000)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return fetchPages(e, sf.pages, 1, 1000, func(page, size *int) ([]dlmm.Pool, pageInfo, error) {
+					resp, err := e.client.DLMM.GetGroup(ctx, args[0], &dlmm.GetGroupParams{
+						Page: page, PageSize: size, Query: *sf.query, FilterBy: *sf.filter, SortBy: *sf.sort,
+					})
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Data, dlmmPageInfo(resp), nil
+				})
+			}
+		},
+	},
+	{
+		name: "pool", args: "<address>...", summary: "Show pools by address, fetching several concurrently",
+		minArgs: 1, maxArgs: -1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				if len(args) == 1 {
+					return e.client.DLMM.GetPool(ctx, args[0])
+				}
+				return collect(e, e.client.DLMM.GetPools(ctx, args, nil), func(r dlmm.PoolResult) (string, *dlmm.Pool, error) {
+					return r.Address, r.Pool, r.Err
+				})
+			}
+		},
+	},
+	{
+		name: "ohlcv", args: "<address>", summary: "Show price candles of a pool", minArgs: 1, maxArgs: 1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			tf := addTimeFlags(fs)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				resp, err := e.client.DLMM.GetOHLCV(ctx, args[0], &dlmm.OHLCVParams{TimeframeBasedParams: dlmm.TimeframeBasedParams{
+					Timeframe: *tf.timeframe, StartTime: *tf.from, EndTime: *tf.to,
+				}})
+				if err != nil {
+					return nil, err
+				}
+				return nonNil(resp.Data), nil
+			}
+		},
+	},
+	{
+		name: "volume", args: "<address>", summary: "Show volume and fee history of a pool", minArgs: 1, maxArgs: 1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			tf := addTimeFlags(fs)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				resp, err := e.client.DLMM.GetVolumeHistory(ctx, args[0], &dlmm.VolumeHistoryParams{TimeframeBasedParams: dlmm.TimeframeBasedParams{
+					Timeframe: *tf.timeframe, StartTime: *tf.from, EndTime: *tf.to,
+				}})
+				if err != nil {
+					return nil, err
+				}
+				return nonNil(resp.Data), nil
+			}
+		},
+	},
+	{
+		name: "metrics", summary: "Show protocol metrics", maxArgs: 0,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return e.client.DLMM.GetProtocolMetrics(ctx)
+			}
+		},
+	},
+	{
+		name: "closed-positions", args: "<wallet>", summary: "List closed positions of a wallet", minArgs: 1, maxArgs: 1,
+		columns: closedPositionColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			cf := addCursorFlags(fs)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return fetchCursor(e, *cf.cursor, *cf.all, func(cursor *string) ([]dlmm.ClosedPosition, *string, error) {
+					resp, err := e.client.DLMM.GetClosedPositions(ctx, args[0], &dlmm.GetClosedPositionsParams{
+						StartTime: *cf.from, EndTime: *cf.to, Limit: *cf.limit, NextCursor: cursor, Pool: *cf.pool,
+					})
+					if err != nil {
+						return nil, nil, err
+					}
+					return resp.Data, resp.NextCursor, nil
+				})
+			}
+		},
+	},
+	{
+		name: "open-positions", args: "<wallet>", summary: "List open positions of a wallet, by pool", minArgs: 1, maxArgs: 1,
+		columns: []string{"pool_address", "name", "active_bin_id", "pool_price", "positions"},
+		setup: func(fs *flag.FlagSet) runFunc {
+			pool := optString(fs, "pool", "only positions in this pool")
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				resp, err := e.client.DLMM.GetOpenPositions(ctx, args[0], &dlmm.GetOpenPositionsParams{Pool: *pool})
+				if err != nil {
+					return nil, err
+				}
+				return nonNil(resp.Data), nil
+			}
+		},
+	},
+	{
+		name: "position-events", args: "<position>", summary: "List the historical events of a position", minArgs: 1, maxArgs: 1,
+		columns: []string{"blockTime", "eventType", "signature", "amountX", "amountY", "totalUsd"},
+		setup: func(fs *flag.FlagSet) runFunc {
+			eventType := optString(fs, "type", "only events of this type: add, remove, claim_fee or claim_reward")
+			order := optString(fs, "order", "order by block time: asc or desc")
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				params := &dlmm.GetPositionHistoricalEventsParams{}
+				if *eventType != nil {
+					t := dlmm.PositionEventType(**eventType)
+					params.EventType = &t
+				}
+				if *order != nil {
+					o := dlmm.PositionEventOrderDirection(**order)
+					params.OrderDirection = &o
+				}
+				resp, err := e.client.DLMM.GetPositionHistoricalEvents(ctx, args[0], params)
+				if err != nil {
+					return nil, err
+				}
+				return nonNil(resp.Events), nil
+			}
+		},
+	},
+	{
+		name: "position-fees", args: "<position>", summary: "Show the total claimed fees of a position", minArgs: 1, maxArgs: 1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				fees, err := e.client.DLMM.GetPositionTotalClaimFees(ctx, args[0])
+				return nonNil(fees), err
+			}
+		},
+	},
+	{
+		name: "pnl", args: "<pool>", summary: "List the position PnL of a user in a pool", minArgs: 1, maxArgs: 1,
+		columns: []string{"positionAddress", "isClosed", "isOutOfRange", "pnlUsd", "pnlPctChange", "minPrice", "maxPrice"},
+		setup: func(fs *flag.FlagSet) runFunc {
+			user := fs.String("user", "", "wallet address (required)")
+			status := optString(fs, "status", "position status: open, closed or all")
+			pf := addPageFlags(fs, "page-size", 100)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				if *user == "" {
+					return nil, errRequired("--user")
+				}
+				return fetchPages(e, pf, 1, 100, func(page, size *int) ([]dlmm.PositionPnLData, pageInfo, error) {
+					params := &dlmm.GetPoolPositionPnLParams{User: *user, Page: page, PageSize: size}
+					if *status != nil {
+						s := dlmm.PositionStatus(**status)
+						params.Status = &s
+					}
+					resp, err := e.client.DLMM.GetPoolPositionPnL(ctx, args[0], params)
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Positions, pageInfo{page: resp.Page, total: resp.TotalCount, more: resp.HasNext}, nil
+				})
+			}
+		},
+	},
+	{
+		name: "portfolio", args: "<wallet>", summary: "List the per-pool portfolio of a wallet", minArgs: 1, maxArgs: 1,
+		columns: []string{"poolAddress", "tokenX", "tokenY", "totalDeposit", "totalWithdrawal", "totalFee", "pnlUsd", "pnlPctChange"},
+		setup: func(fs *flag.FlagSet) runFunc {
+			daysBack := optInt(fs, "days-back", "number of days of history")
+			pf := addPageFlags(fs, "page-size", 100)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return fetchPages(e, pf, 1, 100, func(page, size *int) ([]dlmm.PoolPortfolioItem, pageInfo, error) {
+					resp, err := e.client.DLMM.GetPortfolio(ctx, &dlmm.GetPortfolioParams{
+						User: args[0], Page: page, PageSize: size, DaysBack: *daysBack,
+					})
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Pools, pageInfo{page: resp.Page, total: resp.TotalCount, more: resp.HasNext}, nil
+				})
+			}
+		},
+	},
+	{
+		name: "open-portfolio", args: "<wallet>", summary: "List the pools with open positions of a wallet", minArgs: 1, maxArgs: 1,
+		columns: []string{"poolAddress", "tokenX", "tokenY", "openPositionCount", "positionsOutOfRange", "balances", "pnl", "pnlPctChange"},
+		setup: func(fs *flag.FlagSet) runFunc {
+			sortBy := optString(fs, "sort", "sort by current_balances or unclaimed_fee")
+			direction := optString(fs, "direction", "sort direction: asc or desc")
+			pf := addPageFlags(fs, "page-size", 100)
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return fetchPages(e, pf, 1, 100, func(page, size *int) ([]dlmm.PoolOpenPortfolioItem, pageInfo, error) {
+					params := &dlmm.GetOpenPortfolioParams{User: args[0], Page: page, PageSize: size}
+					if *sortBy != nil {
+						s := dlmm.GetOpenPortfolioSort(**sortBy)
+						params.SortBy = &s
+					}
+					if *direction != nil {
+						d := dlmm.SortDirection(**direction)
+						params.SortDirection = &d
+					}
+					resp, err := e.client.DLMM.GetOpenPortfolio(ctx, params)
+					if err != nil {
+						return nil, pageInfo{}, err
+					}
+					return resp.Pools, pageInfo{page: resp.Page, total: resp.TotalCount, more: resp.HasNext}, nil
+				})
+			}
+		},
+	},
+	{
+		name: "portfolio-total", args: "<wallet>", summary: "Show the all-time PnL of a wallet", minArgs: 1, maxArgs: 1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return e.client.DLMM.GetPortfolioTotal(ctx, args[0])
+			}
+		},
+	},
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseArgs parses fs from args, allowing flags to appear after positional
+// arguments, and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// ptrValue is a flag.Value that stays nil until the flag is set, for the
+// optional pointer fields of the params structs.
+type ptrValue[T any] struct {
+	v     **T
+	parse func(string) (T, error)
+}
+
+func (p ptrValue[T]) Set(s string) error {
+	v, err := p.parse(s)
+	if err != nil {
+		return err
+	}
+	*p.v = &v
+	return nil
+}
+
+func (p ptrValue[T]) String() string {
+	if p.v == nil || *p.v == nil {
+		return ""
+	}
+	return fmt.Sprint(**p.v)
+}
+
+// boolPtrValue is a ptrValue that can be set without a value, as in --unknown.
+type boolPtrValue struct {
+	ptrValue[bool]
+}
+
+func (boolPtrValue) IsBoolFlag() bool { return true }
+
+func optString(fs *flag.FlagSet, name, usage string) **string {
+	var v *string
+	fs.Var(ptrValue[string]{&v, func(s string) (string, error) { return s, nil }}, name, usage)
+	return &v
+}
+
+func optInt(fs *flag.FlagSet, name, usage string) **int {
+	var v *int
+	fs.Var(ptrValue[int]{&v, strconv.Atoi}, name, usage)
+	return &v
+}
+
+func optFloat(fs *flag.FlagSet, name, usage string) **float64 {
+	var v *float64
+	fs.Var(ptrValue[float64]{&v, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }}, name, usage)
+	return &v
+}
+
+func optBool(fs *flag.FlagSet, name, usage string) **bool {
+	var v *bool
+	fs.Var(boolPtrValue{ptrValue[bool]{&v, strconv.ParseBool}}, name, usage)
+	return &v
+}
+
+// optTime registers a time flag accepting Unix seconds, RFC 3339 timestamps
+// or dates (2006-01-02, UTC). The value is stored as Unix seconds.
+func optTime(fs *flag.FlagSet, name, usage string) **int64 {
+	var v *int64
+	fs.Var(ptrValue[int64]{&v, parseTime}, name, usage+" (Unix seconds, RFC 3339 or YYYY-MM-DD)")
+	return &v
+}
+
+func parseTime(s string) (int64, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Unix(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q", s)
+}
+
+// listValue is a flag.Value collecting comma-separated values from one or
+// more occurrences of the flag.
+type listValue []string
+
+func (l *listValue) Set(s string) error {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+func (l *listValue) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func list(fs *flag.FlagSet, name, usage string) *[]string {
+	var v listValue
+	fs.Var(&v, name, usage+" (comma-separated or repeated)")
+	return (*[]string)(&v)
+}
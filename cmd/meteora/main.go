@@ -0,0 +1,291 @@
+// Command meteora queries the Meteora APIs from the command line.
+//
+// Usage:
+//
+//	meteora [global flags] <service> <command> [flags] [args]
+//
+// Services mirror the SDK packages: dlmm, dammv2, dammv1, stake2earn and
+// vault (Dynamic Vault). Run "meteora <service>" to list its commands and
+// "meteora <service> <command> -h" for the flags of a command. Global flags
+// may also follow the command.
+//
+// Examples:
+//
+//	meteora dlmm pools --filter 'tvl>1000' --sort volume_24h:desc
+//	meteora dlmm ohlcv <address> --timeframe 1h -o csv
+//	meteora dammv1 search --filter sol --all -o ndjson
+//	meteora stake2earn vault <address> -o json
+//	meteora vault apy <mint> --from 2026-01-01 --to 2026-02-01
+//
+// Base URLs can be overridden with flags or with the METEORA_DLMM_URL,
+// METEORA_DAMMV2_URL, METEORA_DAMMV1_URL, METEORA_STAKE2EARN_URL and
+// METEORA_VAULT_URL environment variables; flags take precedence.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	meteora "github.com/ua1984/meteora-go"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// globals holds the flags accepted before and after the command.
+type globals struct {
+	output      string
+	columns     []string
+	dlmmURL     string
+	dammv2URL   string
+	dammv1URL   string
+	s2eURL      string
+	vaultURL    string
+	timeout     time.Duration
+	noRateLimit bool
+}
+
+func newGlobals(getenv func(string) string) *globals {
+	return &globals{
+		output:    formatTable,
+		dlmmURL:   getenv("METEORA_DLMM_URL"),
+		dammv2URL: getenv("METEORA_DAMMV2_URL"),
+		dammv1URL: getenv("METEORA_DAMMV1_URL"),
+		s2eURL:    getenv("METEORA_STAKE2EARN_URL"),
+		vaultURL:  getenv("METEORA_VAULT_URL"),
+		timeout:   30 * time.Second,
+	}
+}
+
+// register adds the global flags to fs, keeping values already parsed.
+func (g *globals) register(fs *flag.FlagSet) {
+	usage := "output format: " + strings.Join(formats, ", ")
+	fs.StringVar(&g.output, "output", g.output, usage)
+	fs.StringVar(&g.output, "o", g.output, "shorthand for --output")
+	fs.Var((*listValue)(&g.columns), "columns", "columns for table and CSV output, by dotted JSON name")
+	fs.StringVar(&g.dlmmURL, "dlmm-url", g.dlmmURL, "DLMM base URL (env METEORA_DLMM_URL)")
+	fs.StringVar(&g.dammv2URL, "dammv2-url", g.dammv2URL, "DAMM v2 base URL (env METEORA_DAMMV2_URL)")
+	fs.StringVar(&g.dammv1URL, "dammv1-url", g.dammv1URL, "DAMM v1 base URL (env METEORA_DAMMV1_URL)")
+	fs.StringVar(&g.s2eURL, "stake2earn-url", g.s2eURL, "Stake2Earn base URL (env METEORA_STAKE2EARN_URL)")
+	fs.StringVar(&g.vaultURL, "vault-url", g.vaultURL, "Dynamic Vault base URL (env METEORA_VAULT_URL)")
+	fs.DurationVar(&g.timeout, "timeout", g.timeout, "HTTP timeout per request")
+	fs.BoolVar(&g.noRateLimit, "no-rate-limit", g.noRateLimit, "disable client-side rate limiting")
+}
+
+func (g *globals) client() *meteora.Client {
+	opts := []meteora.Option{meteora.WithHTTPClient(&http.Client{Timeout: g.timeout})}
+	for _, o := range []struct {
+		url string
+		opt func(string) meteora.Option
+	}{
+		{g.dlmmURL, meteora.WithDLMMBaseURL},
+		{g.dammv2URL, meteora.WithDAMMv2BaseURL},
+		{g.dammv1URL, meteora.WithDAMMv1BaseURL},
+		{g.s2eURL, meteora.WithStake2EarnBaseURL},
+		{g.vaultURL, meteora.WithDynamicVaultBaseURL},
+	} {
+		if o.url != "" {
+			opts = append(opts, o.opt(o.url))
+		}
+	}
+	if !g.noRateLimit {
+		opts = append(opts, meteora.WithRateLimit())
+	}
+	return meteora.New(opts...)
+}
+
+// env is passed to every command.
+type env struct {
+	client *meteora.Client
+	stderr io.Writer
+}
+
+// notef writes an informational message to stderr, where it does not mix
+// with the command output.
+func (e *env) notef(format string, args ...any) {
+	fmt.Fprintf(e.stderr, format+"\n", args...)
+}
+
+// runFunc executes a command with its positional arguments and returns the
+// value to render.
+type runFunc func(ctx context.Context, e *env, args []string) (any, error)
+
+type command struct {
+	name    string
+	args    string
+	summary string
+
+	// minArgs and maxArgs bound the number of positional arguments.
+	// A negative maxArgs allows any number.
+	minArgs, maxArgs int
+
+	// columns are the default table columns. All columns are shown if empty.
+	columns []string
+
+	// setup registers the command flags and returns the function running it.
+	setup func(fs *flag.FlagSet) runFunc
+}
+
+type service struct {
+	name     string
+	summary  string
+	commands []command
+}
+
+var services = []service{
+	{"dlmm", "DLMM pools, positions and portfolios", dlmmCommands},
+	{"dammv2", "DAMM v2 pools and positions", dammv2Commands},
+	{"dammv1", "DAMM v1 pools, farms and alpha vaults", dammv1Commands},
+	{"stake2earn", "Stake2Earn vaults", stake2earnCommands},
+	{"vault", "Dynamic Vault vaults, APY and virtual prices", vaultCommands},
+}
+
+// errPartial is returned by batch commands when some items failed. The items
+// that succeeded are still written.
+var errPartial = errors.New("some items failed")
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	g := newGlobals(getenv)
+	fs := flag.NewFlagSet("meteora", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	g.register(fs)
+	fs.Usage = func() { printUsage(stderr, fs) }
+	if err := fs.Parse(args); err != nil {
+		return usageCode(err)
+	}
+	args = fs.Args()
+	if len(args) == 0 || args[0] == "help" {
+		fs.Usage()
+		return exitUsage
+	}
+
+	svc := findService(args[0])
+	if svc == nil {
+		fmt.Fprintf(stderr, "meteora: unknown service %q\n", args[0])
+		fs.Usage()
+		return exitUsage
+	}
+	if len(args) == 1 || args[1] == "help" || args[1] == "-h" || args[1] == "--help" {
+		printServiceUsage(stderr, svc)
+		return exitUsage
+	}
+	cmd := findCommand(svc, args[1])
+	if cmd == nil {
+		fmt.Fprintf(stderr, "meteora: unknown %s command %q\n", svc.name, args[1])
+		printServiceUsage(stderr, svc)
+		return exitUsage
+	}
+
+	cfs := flag.NewFlagSet("meteora "+svc.name+" "+cmd.name, flag.ContinueOnError)
+	cfs.SetOutput(stderr)
+	runCmd := cmd.setup(cfs)
+	g.register(cfs)
+	cfs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: meteora %s %s [flags] %s\n\n%s\n\nFlags:\n", svc.name, cmd.name, cmd.args, cmd.summary)
+		cfs.PrintDefaults()
+	}
+	positional, err := parseArgs(cfs, args[2:])
+	if err != nil {
+		return usageCode(err)
+	}
+	if len(positional) < cmd.minArgs || (cmd.maxArgs >= 0 && len(positional) > cmd.maxArgs) {
+		want := cmd.args
+		if want == "" {
+			want = "no arguments"
+		}
+		fmt.Fprintf(stderr, "meteora: %s %s expects %s\n", svc.name, cmd.name, want)
+		cfs.Usage()
+		return exitUsage
+	}
+	if !validFormat(g.output) {
+		fmt.Fprintf(stderr, "meteora: unknown output format %q (want %s)\n", g.output, strings.Join(formats, ", "))
+		return exitUsage
+	}
+
+	result, runErr := runCmd(ctx, &env{client: g.client(), stderr: stderr}, positional)
+	if runErr != nil && !errors.Is(runErr, errPartial) {
+		fmt.Fprintf(stderr, "meteora: %v\n", runErr)
+		return exitError
+	}
+
+	columns := g.columns
+	if len(columns) == 0 && g.output == formatTable {
+		columns = cmd.columns
+	}
+	if err := render(stdout, g.output, result, columns); err != nil {
+		fmt.Fprintf(stderr, "meteora: %v\n", err)
+		return exitError
+	}
+	if runErr != nil {
+		fmt.Fprintf(stderr, "meteora: %v\n", runErr)
+		return exitError
+	}
+	return exitOK
+}
+
+func errRequired(flag string) error {
+	return fmt.Errorf("%s is required", flag)
+}
+
+func usageCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitUsage
+}
+
+func findService(name string) *service {
+	for i := range services {
+		if services[i].name == name {
+			return &services[i]
+		}
+	}
+	return nil
+}
+
+func findCommand(svc *service, name string) *command {
+	for i := range svc.commands {
+		if svc.commands[i].name == name {
+			return &svc.commands[i]
+		}
+	}
+	return nil
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: meteora [flags] <service> <command> [flags] [args]\n\nServices:\n")
+	for _, s := range services {
+		fmt.Fprintf(w, "  %-12s %s\n", s.name, s.summary)
+	}
+	fmt.Fprintf(w, "\nGlobal flags, also accepted after the command:\n")
+	fs.PrintDefaults()
+}
+
+func printServiceUsage(w io.Writer, svc *service) {
+	fmt.Fprintf(w, "Usage: meteora %s <command> [flags] [args]\n\nCommands:\n", svc.name)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range svc.commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", strings.TrimSpace(c.name+" "+c.args), c.summary)
+	}
+	tw.Flush()
+}
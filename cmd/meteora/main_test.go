@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/dynamicvault"
+	"github.com/ua1984/meteora-go/meteoratest"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+type CLITestSuite struct {
+	suite.Suite
+	srv *meteoratest.Server
+}
+
+func TestCLI(t *testing.T) {
+	suite.Run(t, new(CLITestSuite))
+}
+
+func (s *CLITestSuite) SetupTest() {
+	s.srv = meteoratest.NewServer()
+	s.srv.Seed(func(d *meteoratest.Data) {
+		for i := 1; i <= 5; i++ {
+			d.DLMM.Pools = append(d.DLMM.Pools, dlmm.Pool{
+				Address: fmt.Sprintf("pool%d", i),
+				Name:    "SOL-USDC",
+				TVL:     float64(i * 1000),
+				Volume:  dlmm.TimeBuckets{Hour24: float64(i * 100)},
+			})
+		}
+		d.DAMMv1.Pools = []dammv1.Pool{
+			{PoolAddress: "v1a", PoolName: "SOL-USDC", PoolTVL: "300", PoolTokenMints: []string{"SOL", "USDC"}},
+			{PoolAddress: "v1b", PoolName: "JUP-SOL", PoolTVL: "200", PoolTokenMints: []string{"JUP", "SOL"}},
+			{PoolAddress: "v1c", PoolName: "BONK-SOL", PoolTVL: "100", PoolTokenMints: []string{"BONK", "SOL"}},
+		}
+		d.Stake2Earn.Vaults = []stake2earn.Vault{{VaultAddress: "vault1", PoolAddress: "v1a", TokenASymbol: "SOL"}}
+		d.DynamicVault.APYHistory["USDC"] = []dynamicvault.APYEntry{
+			{APY: 5, Timestamp: 1700000000},
+			{APY: 6, Timestamp: 1700086400},
+			{APY: 7, Timestamp: 1800000000},
+		}
+	})
+}
+
+func (s *CLITestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+// env returns the environment pointing every service at the fake server.
+func (s *CLITestSuite) env(name string) string {
+	return map[string]string{
+		"METEORA_DLMM_URL":       s.srv.URL + meteoratest.DLMMPrefix,
+		"METEORA_DAMMV2_URL":     s.srv.URL + meteoratest.DAMMv2Prefix,
+		"METEORA_DAMMV1_URL":     s.srv.URL + meteoratest.DAMMv1Prefix,
+		"METEORA_STAKE2EARN_URL": s.srv.URL + meteoratest.Stake2EarnPrefix,
+		"METEORA_VAULT_URL":      s.srv.URL + meteoratest.DynamicVaultPrefix,
+	}[name]
+}
+
+func (s *CLITestSuite) run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), append([]string{"--no-rate-limit"}, args...), &stdout, &stderr, s.env)
+	return code, stdout.String(), stderr.String()
+}
+
+func (s *CLITestSuite) TestOutputFormats() {
+	tests := []struct {
+		name   string
+		args   []string
+		assert func(out string)
+	}{
+		{
+			name: "should print the default table columns",
+			args: []string{"dlmm", "pools", "--sort", "tvl:desc", "--page-size", "2"},
+			assert: func(out string) {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				s.Require().Len(lines, 3)
+				s.Equal([]string{"ADDRESS", "NAME", "TVL", "VOLUME.24H", "FEES.24H", "FEE_TVL_RATIO.24H", "CURRENT_PRICE"}, strings.Fields(lines[0]))
+				s.Equal("pool5", strings.Fields(lines[1])[0])
+			},
+		},
+		{
+			name: "should print selected CSV columns",
+			args: []string{"dlmm", "pools", "-o", "csv", "--columns", "address,volume.24h", "--filter", "tvl>=4000"},
+			assert: func(out string) {
+				rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+				s.Require().NoError(err)
+				s.Equal([][]string{{"address", "volume.24h"}, {"pool5", "500"}, {"pool4", "400"}}, rows)
+			},
+		},
+		{
+			name: "should print one JSON object per line",
+			args: []string{"dlmm", "pools", "-o", "ndjson", "--page-size", "3"},
+			assert: func(out string) {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				s.Require().Len(lines, 3)
+				var p dlmm.Pool
+				s.Require().NoError(json.Unmarshal([]byte(lines[0]), &p))
+				s.Equal("pool5", p.Address)
+			},
+		},
+		{
+			name: "should print a single record as name/value lines",
+			args: []string{"stake2earn", "vault", "vault1"},
+			assert: func(out string) {
+				s.Contains(out, "vault_address")
+				s.Contains(out, "flags.")
+				s.Regexp(`(?m)^pool_address\s+v1a$`, out)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			code, out, stderr := s.run(tt.args...)
+
+			// Assert
+			s.Equal(exitOK, code, stderr)
+			tt.assert(out)
+		})
+	}
+}
+
+func (s *CLITestSuite) TestAllPages() {
+	tests := []struct {
+		name      string
+		args      []string
+		wantItems int
+		wantNote  bool
+	}{
+		{
+			name:      "should return one page and point to --all",
+			args:      []string{"dlmm", "pools", "--page-size", "2"},
+			wantItems: 2,
+			wantNote:  true,
+		},
+		{
+			name:      "should follow page-based pagination",
+			args:      []string{"dlmm", "pools", "--page-size", "2", "--all"},
+			wantItems: 5,
+		},
+		{
+			name:      "should follow 0-based search pagination",
+			args:      []string{"dammv1", "search", "--size", "2", "--all"},
+			wantItems: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			code, out, stderr := s.run(append(tt.args, "-o", "json")...)
+
+			// Assert
+			s.Require().Equal(exitOK, code, stderr)
+			var items []map[string]any
+			s.Require().NoError(json.Unmarshal([]byte(out), &items))
+			s.Len(items, tt.wantItems)
+			s.Equal(tt.wantNote, strings.Contains(stderr, "--all"))
+		})
+	}
+}
+
+func (s *CLITestSuite) TestVaultAPYRange() {
+	// Act
+	code, out, stderr := s.run("vault", "apy", "USDC", "--from", "2023-11-14", "--to", "2023-11-16T00:00:00Z", "-o", "json")
+
+	// Assert
+	s.Require().Equal(exitOK, code, stderr)
+	var entries []dynamicvault.APYEntry
+	s.Require().NoError(json.Unmarshal([]byte(out), &entries))
+	s.Len(entries, 2)
+	s.Equal("/dynamicvault/apy_filter/USDC/1699920000/1700092800", s.srv.Requests()[0].Path)
+}
+
+func (s *CLITestSuite) TestBaseURLFlagOverridesEnv() {
+	// Arrange
+	other := meteoratest.NewServer()
+	defer other.Close()
+
+	// Act
+	code, _, stderr := s.run("dlmm", "pools", "--dlmm-url", other.URL+meteoratest.DLMMPrefix)
+
+	// Assert
+	s.Equal(exitOK, code, stderr)
+	s.Empty(s.srv.Requests())
+	s.Len(other.Requests(), 1)
+}
+
+func (s *CLITestSuite) TestErrors() {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "should reject unknown services",
+			args:       []string{"nope"},
+			wantCode:   exitUsage,
+			wantStderr: `unknown service "nope"`,
+		},
+		{
+			name:       "should reject missing arguments",
+			args:       []string{"dlmm", "ohlcv"},
+			wantCode:   exitUsage,
+			wantStderr: "expects <address>",
+		},
+		{
+			name:       "should reject unknown output formats before sending requests",
+			args:       []string{"dlmm", "pools", "-o", "xml"},
+			wantCode:   exitUsage,
+			wantStderr: `unknown output format "xml"`,
+		},
+		{
+			name:       "should report API errors",
+			args:       []string{"dlmm", "pool", "missing"},
+			wantCode:   exitError,
+			wantStderr: "dlmm.GetPool",
+		},
+		{
+			name:       "should print the items that succeeded and report the failed ones",
+			args:       []string{"dlmm", "pool", "pool1", "missing", "-o", "csv", "--columns", "address"},
+			wantCode:   exitError,
+			wantStdout: "address\npool1\n",
+			wantStderr: "some items failed: 1 of 2",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			code, out, stderr := s.run(tt.args...)
+
+			// Assert
+			s.Equal(tt.wantCode, code)
+			s.Equal(tt.wantStdout, out)
+			s.Contains(stderr, tt.wantStderr)
+		})
+	}
+}
+
+func (s *CLITestSuite) TestFaultRetried() {
+	// Arrange
+	s.srv.InjectFault(meteoratest.Fault{Path: "/stake2earn", Status: http.StatusServiceUnavailable, Count: 1})
+
+	// Act
+	code, out, stderr := s.run("stake2earn", "vaults", "-o", "csv", "--columns", "vault_address")
+
+	// Assert
+	s.Equal(exitOK, code, stderr)
+	s.Equal("vault_address\nvault1\n", out)
+}
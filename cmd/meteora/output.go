@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ua1984/meteora-go/internal/flatten"
+)
+
+// Output formats.
+const (
+	formatTable  = "table"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+var formats = []string{formatTable, formatJSON, formatNDJSON, formatCSV}
+
+func validFormat(format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// render writes v to w in format. Slices are written one element per row or
+// line; other values as a single record. Table and CSV output flatten nested
+// structs into dotted column names taken from the JSON tags, limited to
+// columns when it is not empty.
+func render(w io.Writer, format string, v any, columns []string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, r := range records(v) {
+			if err := enc.Encode(r.Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatCSV, formatTable:
+		recs := records(v)
+		cols, err := flatten.Select(flatten.Columns(elemType(v)), columns)
+		if err != nil {
+			return err
+		}
+		if format == formatCSV {
+			return writeCSV(w, cols, recs)
+		}
+		if reflect.ValueOf(v).Kind() != reflect.Slice {
+			return writeRecordTable(w, cols, recs)
+		}
+		return writeTable(w, cols, recs)
+	default:
+		return fmt.Errorf("unknown output format %q (want %s)", format, strings.Join(formats, ", "))
+	}
+}
+
+// records returns the elements of v if it is a slice, or v itself otherwise.
+func records(v any) []reflect.Value {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []reflect.Value{rv}
+	}
+	recs := make([]reflect.Value, rv.Len())
+	for i := range recs {
+		recs[i] = rv.Index(i)
+	}
+	return recs
+}
+
+func elemType(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Slice {
+		return t.Elem()
+	}
+	return t
+}
+
+func writeCSV(w io.Writer, cols []flatten.Column, recs []reflect.Value) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, len(cols))
+	for _, rec := range recs {
+		for i, c := range cols {
+			row[i] = c.Format(rec)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeTable(w io.Writer, cols []flatten.Column, recs []reflect.Value) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = strings.ToUpper(c.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	row := make([]string, len(cols))
+	for _, rec := range recs {
+		for i, c := range cols {
+			row[i] = tableCell(c.Format(rec))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeRecordTable writes a single record as name/value lines.
+func writeRecordTable(w io.Writer, cols []flatten.Column, recs []reflect.Value) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, rec := range recs {
+		for _, c := range cols {
+			fmt.Fprintf(tw, "%s\t%s\n", c.Name, tableCell(c.Format(rec)))
+		}
+	}
+	return tw.Flush()
+}
+
+// tableCell keeps a value on one line and shortens long JSON blobs.
+func tableCell(s string) string {
+	s = strings.NewReplacer("\t", " ", "\n", " ").Replace(s)
+	if r := []rune(s); len(r) > 80 {
+		s = string(r[:77]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// pageFlags are the flags of page-based list commands.
+type pageFlags struct {
+	page     **int
+	pageSize **int
+	all      *bool
+}
+
+// addPageFlags registers --page, --page-size (or --size) and --all. maxSize is
+// the page size used by --all when none is given.
+func addPageFlags(fs *flag.FlagSet, sizeName string, maxSize int) *pageFlags {
+	return &pageFlags{
+		page:     optInt(fs, "page", "page number"),
+		pageSize: optInt(fs, sizeName, fmt.Sprintf("results per page (max %d)", maxSize)),
+		all:      fs.Bool("all", false, "follow pagination and return every page"),
+	}
+}
+
+// fetchPages returns the requested page, or every page starting at the requested
+// one with --all. get fetches a page (nil for the API default) with the given
+// size and reports the pagination state; first is the number of the first
+// page.
+func fetchPages[T any](e *env, pf *pageFlags, first, maxSize int, get func(page, size *int) ([]T, pageInfo, error)) ([]T, error) {
+	if !*pf.all {
+		items, info, err := get(*pf.page, *pf.pageSize)
+		if err != nil {
+			return nil, err
+		}
+		if info.more {
+			e.notef("%s; use --all to fetch every page", info)
+		}
+		return nonNil(items), nil
+	}
+
+	page := first
+	if *pf.page != nil {
+		page = **pf.page
+	}
+	size := maxSize
+	if *pf.pageSize != nil {
+		size = **pf.pageSize
+	}
+	var all []T
+	for {
+		p := page
+		items, info, err := get(&p, &size)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+		all = append(all, items...)
+		if !info.more || len(items) == 0 {
+			return nonNil(all), nil
+		}
+		page++
+	}
+}
+
+// pageInfo describes the position of a page in a paginated listing.
+type pageInfo struct {
+	page  int
+	pages int
+	total int64
+	more  bool
+}
+
+func (p pageInfo) String() string {
+	if p.pages > 0 {
+		return fmt.Sprintf("page %d of %d, %d results in total", p.page, p.pages, p.total)
+	}
+	if p.total > 0 {
+		return fmt.Sprintf("page %d, %d results in total", p.page, p.total)
+	}
+	return fmt.Sprintf("page %d, more results available", p.page)
+}
+
+// fetchCursor returns one page of a cursor-paginated listing, or every page
+// with all. get fetches the page at cursor (nil for the first page) and
+// returns the next cursor, or nil after the last page.
+func fetchCursor[T any](e *env, cursor *string, all bool, get func(cursor *string) ([]T, *string, error)) ([]T, error) {
+	var items []T
+	for {
+		page, next, err := get(cursor)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if next == nil || *next == "" || len(page) == 0 {
+			return nonNil(items), nil
+		}
+		if !all {
+			e.notef("more results available; use --cursor %s or --all", *next)
+			return nonNil(items), nil
+		}
+		cursor = next
+	}
+}
+
+// collect returns the successful items of a batch call and reports the
+// failed ones on stderr. It returns errPartial if any item failed.
+func collect[R, T any](e *env, results []R, split func(R) (string, *T, error)) ([]T, error) {
+	items := []T{}
+	var failed int
+	for _, r := range results {
+		key, item, err := split(r)
+		if err != nil {
+			e.notef("%s: %v", key, err)
+			failed++
+			continue
+		}
+		items = append(items, *item)
+	}
+	if failed > 0 {
+		return items, fmt.Errorf("%w: %d of %d", errPartial, failed, len(results))
+	}
+	return items, nil
+}
+
+// nonNil returns an empty slice instead of nil so JSON output is [] rather
+// than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+var stake2earnVaultColumns = []string{
+	"vault_address", "pool_address", "token_a_symbol", "token_b_symbol", "total_staked_amount_usd",
+	"daily_reward_usd", "current_reward_usd", "marketcap",
+}
+
+var stake2earnCommands = []command{
+	{
+		name: "analytics", summary: "Show protocol-wide analytics", maxArgs: 0,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				return e.client.Stake2Earn.GetAnalytics(ctx)
+			}
+		},
+	},
+	{
+		name: "vaults", summary: "List vaults, optionally by pool", maxArgs: 0, columns: stake2earnVaultColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			pools := list(fs, "pool", "only vaults of these pool addresses")
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				var resp *stake2earn.VaultListResponse
+				var err error
+				if len(*pools) > 0 {
+					resp, err = e.client.Stake2Earn.FilterVaults(ctx, &stake2earn.FilterParams{PoolAddresses: *pools})
+				} else {
+					resp, err = e.client.Stake2Earn.ListVaults(ctx)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return nonNil(resp.Data), nil
+			}
+		},
+	},
+	{
+		name: "vault", args: "<address>", summary: "Show a vault", minArgs: 1, maxArgs: 1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return e.client.Stake2Earn.GetVault(ctx, args[0])
+			}
+		},
+	},
+	{
+		name: "vaults-by-pool", args: "<pool>...", summary: "Show the vault of each pool", minArgs: 1, maxArgs: -1,
+		columns: stake2earnVaultColumns,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				return collect(e, e.client.Stake2Earn.GetVaultsByPool(ctx, args, nil), func(r stake2earn.VaultResult) (string, *stake2earn.Vault, error) {
+					return r.PoolAddress, r.Vault, r.Err
+				})
+			}
+		},
+	},
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/ua1984/meteora-go/dynamicvault"
+)
+
+// defaultAPYRange is the range of the apy command when only one end is given.
+const defaultAPYRange = 7 * 24 * time.Hour
+
+// apyRow is one strategy APY of an APY state.
+type apyRow struct {
+	Horizon      string  `json:"horizon"`
+	StrategyName string  `json:"strategy_name"`
+	Strategy     string  `json:"strategy"`
+	APY          float64 `json:"apy"`
+}
+
+var vaultCommands = []command{
+	{
+		name: "list", summary: "List vaults", maxArgs: 0,
+		columns: []string{"symbol", "token_address", "pubkey", "usd_rate", "closest_apy", "average_apy", "long_apy", "total_amount", "virtual_price", "enabled"},
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				vaults, err := e.client.DynamicVault.ListVaultInfo(ctx)
+				return nonNil(vaults), err
+			}
+		},
+	},
+	{
+		name: "addresses", summary: "List vault, LP mint and fee addresses", maxArgs: 0,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, _ []string) (any, error) {
+				addresses, err := e.client.DynamicVault.ListVaultAddresses(ctx)
+				return nonNil(addresses), err
+			}
+		},
+	},
+	{
+		name: "state", args: "<mint>...", summary: "Show vault states by token mint, fetching several concurrently",
+		minArgs: 1, maxArgs: -1,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				if len(args) == 1 {
+					return e.client.DynamicVault.GetVaultState(ctx, args[0])
+				}
+				return collect(e, e.client.DynamicVault.GetVaultStates(ctx, args, nil), func(r dynamicvault.VaultStateResult) (string, *dynamicvault.VaultState, error) {
+					return r.TokenMint, r.State, r.Err
+				})
+			}
+		},
+	},
+	{
+		name: "apy", args: "<mint>", minArgs: 1, maxArgs: 1,
+		summary: "Show APY history between --from and --to, or the current APY by strategy without them",
+		setup: func(fs *flag.FlagSet) runFunc {
+			from := optTime(fs, "from", "start time (default 7 days before --to)")
+			to := optTime(fs, "to", "end time (default now)")
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				if *from == nil && *to == nil {
+					state, err := e.client.DynamicVault.GetAPYState(ctx, args[0])
+					if err != nil {
+						return nil, err
+					}
+					rows := []apyRow{}
+					for _, h := range []struct {
+						name string
+						apys []dynamicvault.APYBreakdown
+					}{{"closest", state.ClosestAPY}, {"average", state.AverageAPY}, {"long", state.LongAPY}} {
+						for _, a := range h.apys {
+							rows = append(rows, apyRow{Horizon: h.name, StrategyName: a.StrategyName, Strategy: a.Strategy, APY: a.APY})
+						}
+					}
+					return rows, nil
+				}
+
+				end := time.Now().Unix()
+				if *to != nil {
+					end = **to
+				}
+				start := end - int64(defaultAPYRange/time.Second)
+				if *from != nil {
+					start = **from
+				}
+				entries, err := e.client.DynamicVault.GetAPYByTimeRange(ctx, args[0], start, end)
+				return nonNil(entries), err
+			}
+		},
+	},
+	{
+		name: "virtual-price", args: "<mint> <strategy>", summary: "Show the virtual price history of a vault strategy",
+		minArgs: 2, maxArgs: 2,
+		setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, e *env, args []string) (any, error) {
+				prices, err := e.client.DynamicVault.GetVirtualPrice(ctx, args[0], args[1])
+				return nonNil(prices), err
+			}
+		},
+	},
+}
@@ -0,0 +1,42 @@
+package dammv1
+
+import "context"
+
+// API is the set of DAMM v1 requests implemented by Client. Code that depends
+// on API instead of *Client can substitute a fake, such as the ones in the
+// meteoratest package.
+type API interface {
+	// ListPools returns pools, optionally filtered by the provided parameters.
+	ListPools(ctx context.Context, params *ListPoolsParams) ([]Pool, error)
+
+	// SearchPools searches for pools with filtering and pagination.
+	SearchPools(ctx context.Context, params *SearchParams) (*SearchResult, error)
+
+	// GetPoolsMetrics returns protocol-level pool metrics.
+	GetPoolsMetrics(ctx context.Context) (*PoolMetrics, error)
+
+	// ListPoolConfigs returns all pool configurations.
+	ListPoolConfigs(ctx context.Context) ([]PoolConfig, error)
+
+	// GetFeeConfig returns fee configurations for a config address.
+	GetFeeConfig(ctx context.Context, configAddr string) ([]FeeConfig, error)
+
+	// ListPoolsWithFarm returns pools that have farming, with pagination.
+	ListPoolsWithFarm(ctx context.Context, params *PaginationParams) ([]Pool, error)
+
+	// ListAlphaVaults returns alpha vaults, optionally filtered by vault address,
+	// pool address, or base mint.
+	ListAlphaVaults(ctx context.Context, params *AlphaVaultParams) ([]AlphaVault, error)
+
+	// ListAlphaVaultConfigs returns all alpha vault configurations.
+	ListAlphaVaultConfigs(ctx context.Context) (*AlphaVaultConfigs, error)
+
+	// GetPoolsByVaultLP returns pools associated with a vault LP address.
+	GetPoolsByVaultLP(ctx context.Context, address string) ([]Pool, error)
+
+	// GetPools fetches multiple pools using the multi-address filter of ListPools,
+	// sending the addresses in chunks of BatchOptions.ChunkSize.
+	GetPools(ctx context.Context, addresses []string, opts *BatchOptions) []PoolResult
+}
+
+var _ API = (*Client)(nil)
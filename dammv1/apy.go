@@ -0,0 +1,61 @@
+package dammv1
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// APYBreakdown holds the yield figures of a pool as numbers. All values are
+// percentages.
+type APYBreakdown struct {
+	// TradeAPY is the trading APY.
+	TradeAPY float64 `json:"trade_apy"`
+
+	// WeeklyTradeAPY is the 7-day trading APY.
+	WeeklyTradeAPY float64 `json:"weekly_trade_apy"`
+
+	// DailyBaseAPY is the daily base APY.
+	DailyBaseAPY float64 `json:"daily_base_apy"`
+
+	// WeeklyBaseAPY is the weekly base APY.
+	WeeklyBaseAPY float64 `json:"weekly_base_apy"`
+
+	// APR is the annual percentage rate.
+	APR float64 `json:"apr"`
+
+	// FarmingAPY is the farming APY.
+	FarmingAPY float64 `json:"farming_apy"`
+}
+
+// APYBreakdown parses the pool's yield figures, which the API returns as a mix
+// of strings and numbers. An empty string parses as zero.
+func (p *Pool) APYBreakdown() (APYBreakdown, error) {
+	b := APYBreakdown{APR: p.APR}
+	fields := []struct {
+		name  string
+		value string
+		dst   *float64
+	}{
+		{"trade_apy", p.TradeAPY, &b.TradeAPY},
+		{"weekly_trade_apy", p.WeeklyTradeAPY, &b.WeeklyTradeAPY},
+		{"daily_base_apy", p.DailyBaseAPY, &b.DailyBaseAPY},
+		{"weekly_base_apy", p.WeeklyBaseAPY, &b.WeeklyBaseAPY},
+		{"farming_apy", p.FarmingAPY, &b.FarmingAPY},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(f.value, 64)
+		if err != nil {
+			return APYBreakdown{}, fmt.Errorf("dammv1.Pool.APYBreakdown: %s: %s: %w", p.PoolAddress, f.name, err)
+		}
+		*f.dst = v
+	}
+	return b, nil
+}
+
+// fields returns pointers to every field of b, in declaration order.
+func (b *APYBreakdown) fields() []*float64 {
+	return []*float64{&b.TradeAPY, &b.WeeklyTradeAPY, &b.DailyBaseAPY, &b.WeeklyBaseAPY, &b.APR, &b.FarmingAPY}
+}
@@ -0,0 +1,174 @@
+package dammv1_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/meteoratest"
+)
+
+type APYTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestAPY(t *testing.T) {
+	suite.Run(t, new(APYTestSuite))
+}
+
+func (s *APYTestSuite) SetupTest() {
+	s.now = time.Unix(1_700_000_000, 0)
+}
+
+func (s *APYTestSuite) TestAPYBreakdown() {
+	tests := []struct {
+		name    string
+		pool    dammv1.Pool
+		want    dammv1.APYBreakdown
+		wantErr string
+	}{
+		{
+			name: "should parse every figure",
+			pool: dammv1.Pool{TradeAPY: "12.5", WeeklyTradeAPY: "10", DailyBaseAPY: "3", WeeklyBaseAPY: "2.5", APR: 11, FarmingAPY: "40"},
+			want: dammv1.APYBreakdown{TradeAPY: 12.5, WeeklyTradeAPY: 10, DailyBaseAPY: 3, WeeklyBaseAPY: 2.5, APR: 11, FarmingAPY: 40},
+		},
+		{
+			name: "should parse empty strings as zero",
+			pool: dammv1.Pool{TradeAPY: "1"},
+			want: dammv1.APYBreakdown{TradeAPY: 1},
+		},
+		{
+			name:    "should reject invalid numbers",
+			pool:    dammv1.Pool{PoolAddress: "pool1", FarmingAPY: "n/a"},
+			wantErr: "dammv1.Pool.APYBreakdown: pool1: farming_apy",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			got, err := tt.pool.APYBreakdown()
+
+			// Assert
+			if tt.wantErr != "" {
+				s.ErrorContains(err, tt.wantErr)
+				return
+			}
+			s.Require().NoError(err)
+			s.Equal(tt.want, got)
+		})
+	}
+}
+
+func (s *APYTestSuite) TestRecord() {
+	// Arrange
+	store := dammv1.NewMemoryAPYStore()
+	tracker := dammv1.NewAPYTracker(nil, store, &dammv1.APYTrackerOptions{Window: 48 * time.Hour})
+	farm := "farm1"
+	pool := dammv1.Pool{PoolAddress: "pool1", PoolName: "SOL-USDC", FarmingPool: &farm, FarmRewardDurationEnd: s.now.Add(5 * 24 * time.Hour).Unix()}
+	ctx := context.Background()
+
+	// Act
+	for i, apy := range []string{"100", "10", "20"} {
+		pool.TradeAPY = apy
+		_, err := tracker.Record(ctx, []dammv1.Pool{pool}, s.now.Add(time.Duration(i)*24*time.Hour))
+		s.Require().NoError(err)
+	}
+	pool.TradeAPY = "30"
+	reports, err := tracker.Record(ctx, []dammv1.Pool{pool, {PoolAddress: "bad", TradeAPY: "x"}}, s.now.Add(3*24*time.Hour))
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(reports, 2)
+	r := reports[0]
+	s.Equal("SOL-USDC", r.Name)
+	s.InDelta(30, r.Current.TradeAPY, 1e-9)
+	s.Equal(3, r.Samples)
+	s.InDelta(20, r.Average.TradeAPY, 1e-9)
+	s.InDelta(8.164965809, r.Volatility.TradeAPY, 1e-6)
+	s.True(r.Farming)
+	s.Equal(48*time.Hour, r.FarmEndsIn)
+	s.True(r.FarmEndingSoon)
+	s.False(r.FarmEnded)
+	s.Error(reports[1].Err)
+
+	history, err := store.History(ctx, "pool1", time.Time{}, time.Time{})
+	s.Require().NoError(err)
+	s.Len(history, 4)
+	bad, err := store.History(ctx, "bad", time.Time{}, time.Time{})
+	s.Require().NoError(err)
+	s.Empty(bad)
+}
+
+func (s *APYTestSuite) TestFarmFlags() {
+	tests := []struct {
+		name         string
+		end          time.Duration
+		expire       bool
+		wantSoon     bool
+		wantEnded    bool
+		wantFarmEnds bool
+	}{
+		{name: "should not flag a distant end", end: 10 * 24 * time.Hour, wantFarmEnds: true},
+		{name: "should flag an end within the warning", end: time.Hour, wantSoon: true, wantFarmEnds: true},
+		{name: "should flag a past end", end: -time.Hour, wantEnded: true, wantFarmEnds: true},
+		{name: "should flag an expired farm", expire: true, wantEnded: true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Arrange
+			tracker := dammv1.NewAPYTracker(nil, dammv1.NewMemoryAPYStore(), nil)
+			pool := dammv1.Pool{PoolAddress: "pool1", FarmExpire: tt.expire}
+			if tt.end != 0 {
+				pool.FarmRewardDurationEnd = s.now.Add(tt.end).Unix()
+			}
+
+			// Act
+			reports, err := tracker.Record(context.Background(), []dammv1.Pool{pool}, s.now)
+
+			// Assert
+			s.Require().NoError(err)
+			s.Equal(tt.wantSoon, reports[0].FarmEndingSoon)
+			s.Equal(tt.wantEnded, reports[0].FarmEnded)
+			s.Equal(tt.wantFarmEnds, !reports[0].FarmEnd.IsZero())
+		})
+	}
+}
+
+func (s *APYTestSuite) TestTrack() {
+	// Arrange
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.Seed(func(d *meteoratest.Data) {
+		d.DAMMv1.Pools = []dammv1.Pool{{PoolAddress: "pool1", TradeAPY: "5"}, {PoolAddress: "pool2", TradeAPY: "7"}}
+	})
+	client := srv.Client().DAMMv1
+	tracker := dammv1.NewAPYTracker(client, dammv1.NewMemoryAPYStore(), nil)
+
+	// Act
+	reports, err := tracker.Track(context.Background(), []string{"pool2"})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(reports, 1)
+	s.Equal("pool2", reports[0].Pool)
+	s.InDelta(7, reports[0].Current.TradeAPY, 1e-9)
+	s.Equal(1, reports[0].Samples)
+}
+
+func (s *APYTestSuite) TestRunInvalidInterval() {
+	// Arrange
+	tracker := dammv1.NewAPYTracker(nil, dammv1.NewMemoryAPYStore(), nil)
+	called := false
+
+	// Act
+	err := tracker.Run(context.Background(), 0, []string{"pool1"}, func([]dammv1.APYReport, error) { called = true })
+
+	// Assert
+	s.EqualError(err, "dammv1.APYTracker.Run: interval 0s is not positive")
+	s.False(called)
+}
@@ -0,0 +1,276 @@
+package dammv1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults of APYTrackerOptions.
+const (
+	// DefaultAPYWindow is the trailing window of APYReport statistics.
+	DefaultAPYWindow = 7 * 24 * time.Hour
+
+	// DefaultFarmEndWarning is how long before FarmRewardDurationEnd a farm is
+	// flagged as ending soon.
+	DefaultFarmEndWarning = 72 * time.Hour
+)
+
+// APYRecord is the yield of a pool at one point in time.
+type APYRecord struct {
+	// Pool is the pool address.
+	Pool string `json:"pool"`
+
+	// Time is when the yield was recorded.
+	Time time.Time `json:"time"`
+
+	// APY is the pool's yield at Time.
+	APY APYBreakdown `json:"apy"`
+}
+
+// APYStore persists APY records. Implementations must be safe for concurrent
+// use.
+type APYStore interface {
+	// Append stores records.
+	Append(ctx context.Context, records []APYRecord) error
+
+	// History returns the records of pool taken between from and to
+	// inclusive, oldest first. A zero from or to leaves that end unbounded.
+	History(ctx context.Context, pool string, from, to time.Time) ([]APYRecord, error)
+}
+
+// MemoryAPYStore keeps APY records in memory.
+type MemoryAPYStore struct {
+	mu      sync.RWMutex
+	records map[string][]APYRecord
+}
+
+var _ APYStore = (*MemoryAPYStore)(nil)
+
+// NewMemoryAPYStore returns an empty MemoryAPYStore.
+func NewMemoryAPYStore() *MemoryAPYStore {
+	return &MemoryAPYStore{records: map[string][]APYRecord{}}
+}
+
+// Append stores records.
+func (m *MemoryAPYStore) Append(ctx context.Context, records []APYRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range records {
+		s := m.records[r.Pool]
+		i := sort.Search(len(s), func(i int) bool { return s[i].Time.After(r.Time) })
+		s = append(s, APYRecord{})
+		copy(s[i+1:], s[i:])
+		s[i] = r
+		m.records[r.Pool] = s
+	}
+	return nil
+}
+
+// History returns the stored records of pool between from and to.
+func (m *MemoryAPYStore) History(ctx context.Context, pool string, from, to time.Time) ([]APYRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s := m.records[pool]
+	lo, hi := 0, len(s)
+	if !from.IsZero() {
+		lo = sort.Search(len(s), func(i int) bool { return !s[i].Time.Before(from) })
+	}
+	if !to.IsZero() {
+		hi = sort.Search(len(s), func(i int) bool { return s[i].Time.After(to) })
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return append([]APYRecord(nil), s[lo:hi]...), nil
+}
+
+// APYTrackerOptions are optional parameters for NewAPYTracker.
+type APYTrackerOptions struct {
+	// Window is the trailing window of the average and volatility.
+	// Default: DefaultAPYWindow.
+	Window time.Duration
+
+	// FarmEndWarning is how long before its end a farm is flagged as ending
+	// soon. Default: DefaultFarmEndWarning.
+	FarmEndWarning time.Duration
+}
+
+// APYReport is the yield of a pool and its recent history.
+type APYReport struct {
+	// Pool is the pool address.
+	Pool string `json:"pool"`
+
+	// Name is the pool name.
+	Name string `json:"name"`
+
+	// Time is when the report was made.
+	Time time.Time `json:"time"`
+
+	// Current is the pool's yield at Time.
+	Current APYBreakdown `json:"current"`
+
+	// Samples is the number of records in the trailing window, Current
+	// included.
+	Samples int `json:"samples"`
+
+	// Average is the mean of each yield figure over the trailing window.
+	Average APYBreakdown `json:"average"`
+
+	// Volatility is the standard deviation of each yield figure over the
+	// trailing window, in percentage points.
+	Volatility APYBreakdown `json:"volatility"`
+
+	// Farming reports whether the pool has a farming pool.
+	Farming bool `json:"farming"`
+
+	// FarmEnd is when the farm rewards end. It is zero when the API reports
+	// no end.
+	FarmEnd time.Time `json:"farm_end"`
+
+	// FarmEndsIn is the time from Time to FarmEnd. It is negative once the
+	// rewards have ended and zero when FarmEnd is.
+	FarmEndsIn time.Duration `json:"farm_ends_in"`
+
+	// FarmEndingSoon reports whether the farm rewards end within the warning
+	// period.
+	FarmEndingSoon bool `json:"farm_ending_soon"`
+
+	// FarmEnded reports whether the farm has FarmExpire set or its rewards
+	// have ended.
+	FarmEnded bool `json:"farm_ended"`
+
+	// Err is the error returned when parsing the pool's yield. The other
+	// fields are only set when it is nil.
+	Err error `json:"-"`
+}
+
+// APYTracker records the yield of DAMM v1 pools in an APYStore and reports
+// trailing statistics and farms whose rewards are ending.
+type APYTracker struct {
+	api            API
+	store          APYStore
+	window         time.Duration
+	farmEndWarning time.Duration
+	now            func() time.Time
+}
+
+// NewAPYTracker returns an APYTracker listing pools with api and recording
+// them in store. opts may be nil.
+func NewAPYTracker(api API, store APYStore, opts *APYTrackerOptions) *APYTracker {
+	t := &APYTracker{api: api, store: store, window: DefaultAPYWindow, farmEndWarning: DefaultFarmEndWarning, now: time.Now}
+	if opts != nil && opts.Window > 0 {
+		t.window = opts.Window
+	}
+	if opts != nil && opts.FarmEndWarning > 0 {
+		t.farmEndWarning = opts.FarmEndWarning
+	}
+	return t
+}
+
+// Track lists the pools with the given addresses, or every pool when
+// addresses is empty, and records them with Record at the current time.
+func (t *APYTracker) Track(ctx context.Context, addresses []string) ([]APYReport, error) {
+	pools, err := t.api.ListPools(ctx, &ListPoolsParams{Address: addresses})
+	if err != nil {
+		return nil, fmt.Errorf("dammv1.APYTracker.Track: %w", err)
+	}
+	return t.Record(ctx, pools, t.now())
+}
+
+// Record stores the yield of pools at the given time and returns one report
+// per pool, in order. A pool whose yield cannot be parsed is not stored and
+// has Err set on its report.
+func (t *APYTracker) Record(ctx context.Context, pools []Pool, at time.Time) ([]APYReport, error) {
+	reports := make([]APYReport, len(pools))
+	var records []APYRecord
+	for i := range pools {
+		p := &pools[i]
+		r := &reports[i]
+		r.Pool, r.Name, r.Time = p.PoolAddress, p.PoolName, at
+		r.Current, r.Err = p.APYBreakdown()
+		if r.Err != nil {
+			continue
+		}
+		records = append(records, APYRecord{Pool: p.PoolAddress, Time: at, APY: r.Current})
+		t.flagFarm(r, p)
+	}
+	if err := t.store.Append(ctx, records); err != nil {
+		return nil, fmt.Errorf("dammv1.APYTracker.Record: %w", err)
+	}
+
+	for i := range reports {
+		r := &reports[i]
+		if r.Err != nil {
+			continue
+		}
+		history, err := t.store.History(ctx, r.Pool, at.Add(-t.window), at)
+		if err != nil {
+			return nil, fmt.Errorf("dammv1.APYTracker.Record: %s: %w", r.Pool, err)
+		}
+		r.summarize(history)
+	}
+	return reports, nil
+}
+
+// Run tracks the pools with the given addresses immediately and then every
+// interval until ctx is done, passing each result to handle, which may be
+// nil. Errors do not stop the loop. Run returns the error of ctx, or an error
+// right away if interval is not positive.
+func (t *APYTracker) Run(ctx context.Context, interval time.Duration, addresses []string, handle func([]APYReport, error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("dammv1.APYTracker.Run: interval %v is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		reports, err := t.Track(ctx, addresses)
+		if handle != nil && ctx.Err() == nil {
+			handle(reports, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *APYTracker) flagFarm(r *APYReport, p *Pool) {
+	r.Farming = p.FarmingPool != nil
+	r.FarmEnded = p.FarmExpire
+	if p.FarmRewardDurationEnd > 0 {
+		r.FarmEnd = time.Unix(p.FarmRewardDurationEnd, 0)
+		r.FarmEndsIn = r.FarmEnd.Sub(r.Time)
+		r.FarmEnded = r.FarmEnded || r.FarmEndsIn <= 0
+	}
+	r.FarmEndingSoon = !r.FarmEnded && !r.FarmEnd.IsZero() && r.FarmEndsIn <= t.farmEndWarning
+}
+
+// summarize computes the average and volatility of each yield figure over
+// history.
+func (r *APYReport) summarize(history []APYRecord) {
+	r.Samples = len(history)
+	if r.Samples == 0 {
+		return
+	}
+	n := float64(r.Samples)
+	avg, vol := r.Average.fields(), r.Volatility.fields()
+	for _, h := range history {
+		for i, v := range h.APY.fields() {
+			*avg[i] += *v / n
+		}
+	}
+	for _, h := range history {
+		for i, v := range h.APY.fields() {
+			d := *v - *avg[i]
+			*vol[i] += d * d / n
+		}
+	}
+	for _, v := range vol {
+		*v = math.Sqrt(*v)
+	}
+}
@@ -0,0 +1,93 @@
+package dammv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ua1984/meteora-go/internal/batch"
+)
+
+// ErrPoolNotFound is reported by GetPools for addresses the API did not return.
+var ErrPoolNotFound = errors.New("pool not found")
+
+// BatchOptions are optional parameters for batch methods such as GetPools.
+type BatchOptions struct {
+	// Concurrency is the maximum number of requests in flight. Default: 8.
+	// Requests are additionally throttled by the client's rate limit, if set.
+	Concurrency int
+
+	// ChunkSize is the maximum number of addresses sent per request.
+	// Default: the client's chunk size (see Client.SetChunkSize).
+	ChunkSize int
+}
+
+// PoolResult is the outcome of fetching a single pool in a batch.
+type PoolResult struct {
+	// Address is the requested pool address.
+	Address string
+
+	// Pool is the fetched pool, or nil if Err is set.
+	Pool *Pool
+
+	// Err is the error returned for this address, if any.
+	Err error
+}
+
+// GetPools fetches multiple pools using the multi-address filter of ListPools,
+// sending the addresses in chunks of BatchOptions.ChunkSize. It returns one
+// result per unique address, in order of first appearance. Addresses that are
+// not returned by the API are reported with ErrPoolNotFound. Unlike ListPools,
+// a failed chunk does not fail the batch; its error is reported on each of its
+// addresses.
+func (c *Client) GetPools(ctx context.Context, addresses []string, opts *BatchOptions) []PoolResult {
+	unique := batch.Unique(addresses)
+	size := opts.chunkSize()
+	if size <= 0 {
+		size = c.chunkSize
+	}
+	chunks := batch.Chunk(unique, size)
+
+	pools := make([][]Pool, len(chunks))
+	errs := make([]error, len(chunks))
+	batch.Run(ctx, len(chunks), opts.concurrency(), func(ctx context.Context, i int) {
+		pools[i], errs[i] = c.ListPools(ctx, &ListPoolsParams{Address: chunks[i]})
+	})
+
+	byAddress := map[string]*Pool{}
+	chunkErr := map[string]error{}
+	for i, chunk := range chunks {
+		for j := range pools[i] {
+			byAddress[pools[i][j].PoolAddress] = &pools[i][j]
+		}
+		if errs[i] != nil {
+			for _, addr := range chunk {
+				chunkErr[addr] = errs[i]
+			}
+		}
+	}
+
+	results := make([]PoolResult, len(unique))
+	for i, addr := range unique {
+		results[i] = PoolResult{Address: addr, Pool: byAddress[addr], Err: chunkErr[addr]}
+		if results[i].Pool == nil && results[i].Err == nil {
+			results[i].Err = fmt.Errorf("dammv1.GetPools: %s: %w", addr, ErrPoolNotFound)
+		}
+	}
+
+	return results
+}
+
+func (o *BatchOptions) concurrency() int {
+	if o == nil {
+		return 0
+	}
+	return o.Concurrency
+}
+
+func (o *BatchOptions) chunkSize() int {
+	if o == nil {
+		return 0
+	}
+	return o.ChunkSize
+}
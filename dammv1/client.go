@@ -6,21 +6,54 @@ import (
 	"net/url"
 	"strconv"
 
+	"github.com/ua1984/meteora-go/internal/batch"
 	"github.com/ua1984/meteora-go/internal/httpclient"
 )
 
 // Client provides access to the DAMM v1 API.
 type Client struct {
-	http *httpclient.Client
+	http      *httpclient.Client
+	chunkSize int
 }
 
 // NewClient creates a new DAMM v1 client.
 func NewClient(http *httpclient.Client) *Client {
-	return &Client{http: http}
+	return &Client{http: http, chunkSize: batch.DefaultChunkSize}
+}
+
+// SetChunkSize sets the maximum number of values sent per request for
+// multi-value query parameters such as ListPoolsParams.Address. Longer lists
+// are split into several concurrent requests whose responses are merged.
+// A size of zero or less restores the default of 100.
+func (c *Client) SetChunkSize(size int) {
+	if size <= 0 {
+		size = batch.DefaultChunkSize
+	}
+	c.chunkSize = size
 }
 
 // ListPools returns pools, optionally filtered by the provided parameters.
+// Address lists longer than the client's chunk size are fetched in several
+// requests and merged, without duplicate pools.
 func (c *Client) ListPools(ctx context.Context, params *ListPoolsParams) ([]Pool, error) {
+	if params == nil || len(params.Address) <= c.chunkSize {
+		return c.listPools(ctx, params)
+	}
+
+	chunks := batch.Chunk(batch.Unique(params.Address), c.chunkSize)
+	results, err := batch.Map(ctx, len(chunks), batch.DefaultConcurrency, func(ctx context.Context, i int) ([]Pool, error) {
+		p := *params
+		p.Address = chunks[i]
+		return c.listPools(ctx, &p)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergePools(results), nil
+}
+
+func (c *Client) listPools(ctx context.Context, params *ListPoolsParams) ([]Pool, error) {
 	var q url.Values
 	if params != nil {
 		q = url.Values{}
@@ -59,7 +92,20 @@ func (c *Client) ListPools(ctx context.Context, params *ListPoolsParams) ([]Pool
 }
 
 // SearchPools searches for pools with filtering and pagination.
+//
+// Unlike the other multi-value parameters, IncludeTokenMints is not split into
+// several requests: pages of separate searches cannot be merged into one
+// correctly sorted page. A list longer than the client's chunk size is an
+// error; search with smaller lists and merge the results yourself.
 func (c *Client) SearchPools(ctx context.Context, params *SearchParams) (*SearchResult, error) {
+	if params != nil && len(params.IncludeTokenMints) > c.chunkSize {
+		return nil, fmt.Errorf("dammv1.SearchPools: %d include token mints exceed the chunk size of %d",
+			len(params.IncludeTokenMints), c.chunkSize)
+	}
+	return c.searchPools(ctx, params)
+}
+
+func (c *Client) searchPools(ctx context.Context, params *SearchParams) (*SearchResult, error) {
 	q := url.Values{}
 	if params != nil {
 		q.Set("page", strconv.Itoa(params.Page))
@@ -163,7 +209,46 @@ func (c *Client) ListPoolsWithFarm(ctx context.Context, params *PaginationParams
 }
 
 // ListAlphaVaults returns alpha vaults, optionally filtered by vault address, pool address, or base mint.
+// Filter lists longer than the client's chunk size are fetched in several
+// requests, one per combination of chunks, and merged without duplicate vaults.
 func (c *Client) ListAlphaVaults(ctx context.Context, params *AlphaVaultParams) ([]AlphaVault, error) {
+	if params == nil || (len(params.VaultAddress) <= c.chunkSize &&
+		len(params.PoolAddress) <= c.chunkSize && len(params.BaseMint) <= c.chunkSize) {
+		return c.listAlphaVaults(ctx, params)
+	}
+
+	var combos []AlphaVaultParams
+	for _, vaults := range c.chunkOrKeep(params.VaultAddress) {
+		for _, pools := range c.chunkOrKeep(params.PoolAddress) {
+			for _, mints := range c.chunkOrKeep(params.BaseMint) {
+				combos = append(combos, AlphaVaultParams{VaultAddress: vaults, PoolAddress: pools, BaseMint: mints})
+			}
+		}
+	}
+
+	results, err := batch.Map(ctx, len(combos), batch.DefaultConcurrency, func(ctx context.Context, i int) ([]AlphaVault, error) {
+		return c.listAlphaVaults(ctx, &combos[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var vaults []AlphaVault
+	for _, r := range results {
+		for _, v := range r {
+			if seen[v.VaultAddress] {
+				continue
+			}
+			seen[v.VaultAddress] = true
+			vaults = append(vaults, v)
+		}
+	}
+
+	return vaults, nil
+}
+
+func (c *Client) listAlphaVaults(ctx context.Context, params *AlphaVaultParams) ([]AlphaVault, error) {
 	var q url.Values
 	if params != nil {
 		q = url.Values{}
@@ -211,3 +296,29 @@ func (c *Client) GetPoolsByVaultLP(ctx context.Context, address string) ([]Pool,
 
 	return pools, nil
 }
+
+// chunkOrKeep splits values into chunks of the client's chunk size. Lists that
+// fit in one request, including empty ones, are returned as a single chunk.
+func (c *Client) chunkOrKeep(values []string) [][]string {
+	if len(values) <= c.chunkSize {
+		return [][]string{values}
+	}
+	return batch.Chunk(batch.Unique(values), c.chunkSize)
+}
+
+// mergePools concatenates the pools of several chunked responses, keeping the
+// first occurrence of each pool address.
+func mergePools(results [][]Pool) []Pool {
+	seen := map[string]bool{}
+	var pools []Pool
+	for _, r := range results {
+		for _, p := range r {
+			if seen[p.PoolAddress] {
+				continue
+			}
+			seen[p.PoolAddress] = true
+			pools = append(pools, p)
+		}
+	}
+	return pools
+}
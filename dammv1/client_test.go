@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -317,3 +318,134 @@ func (s *ClientTestSuite) TestGetPoolsByVaultLP() {
 	s.NoError(err)
 	s.Equal(wantPools, pools)
 }
+
+func (s *ClientTestSuite) TestGetPools() {
+	// Arrange
+	var mu sync.Mutex
+	var requested [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("/pools", r.URL.Path)
+		addresses := r.URL.Query()["address"]
+		mu.Lock()
+		requested = append(requested, addresses)
+		mu.Unlock()
+		if addresses[len(addresses)-1] == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var pools []dammv1.Pool
+		for _, addr := range addresses {
+			if addr != "missing" {
+				pools = append(pools, dammv1.Pool{PoolAddress: addr})
+			}
+		}
+		json.NewEncoder(w).Encode(pools)
+	}))
+	defer server.Close()
+	client := dammv1.NewClient(httpclient.New(server.URL, nil))
+
+	// Act
+	results := client.GetPools(context.Background(), []string{"pool1", "missing", "pool1", "pool2", "fail", "pool3"}, &dammv1.BatchOptions{ChunkSize: 2})
+
+	// Assert
+	s.Len(requested, 3)
+	s.Require().Len(results, 5)
+	s.Equal("pool1", results[0].Pool.PoolAddress)
+	s.ErrorIs(results[1].Err, dammv1.ErrPoolNotFound)
+	s.Error(results[2].Err, "pool2 shares a chunk with the failing address")
+	s.Error(results[3].Err)
+	s.NotErrorIs(results[3].Err, dammv1.ErrPoolNotFound)
+	s.Equal("pool3", results[4].Pool.PoolAddress)
+}
+
+func (s *ClientTestSuite) TestChunkedQueries() {
+	// Arrange
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/pools":
+			s.LessOrEqual(len(q["address"]), 2)
+			s.Equal("dynamic", q.Get("pool_type"))
+			// Every chunk also returns "shared" to exercise deduplication.
+			pools := []dammv1.Pool{{PoolAddress: "shared"}}
+			for _, addr := range q["address"] {
+				pools = append(pools, dammv1.Pool{PoolAddress: addr})
+			}
+			json.NewEncoder(w).Encode(pools)
+		case "/pools/search":
+			s.LessOrEqual(len(q["include_token_mints"]), 2)
+			pools := []dammv1.Pool{{PoolAddress: "shared"}}
+			for _, mint := range q["include_token_mints"] {
+				pools = append(pools, dammv1.Pool{PoolAddress: "pool-" + mint})
+			}
+			json.NewEncoder(w).Encode(dammv1.SearchResult{Data: pools, Page: 1, TotalCount: len(pools) + 10})
+		case "/alpha-vault":
+			s.LessOrEqual(len(q["vault_address"]), 2)
+			s.LessOrEqual(len(q["base_mint"]), 2)
+			var vaults []dammv1.AlphaVault
+			for _, v := range q["vault_address"] {
+				vaults = append(vaults, dammv1.AlphaVault{VaultAddress: v})
+			}
+			json.NewEncoder(w).Encode(vaults)
+		}
+	}))
+	defer server.Close()
+	client := dammv1.NewClient(httpclient.New(server.URL, nil))
+	client.SetChunkSize(2)
+	five := []string{"a", "b", "c", "d", "e"}
+	poolType := "dynamic"
+
+	s.Run("should split ListPools addresses and dedupe pools", func() {
+		// Act
+		pools, err := client.ListPools(context.Background(), &dammv1.ListPoolsParams{Address: five, PoolType: &poolType})
+
+		// Assert
+		s.NoError(err)
+		s.Equal(3, requests["/pools"])
+		var addresses []string
+		for _, p := range pools {
+			addresses = append(addresses, p.PoolAddress)
+		}
+		s.Equal([]string{"shared", "a", "b", "c", "d", "e"}, addresses)
+	})
+
+	s.Run("should reject SearchPools mints over the chunk size", func() {
+		// Act
+		result, err := client.SearchPools(context.Background(), &dammv1.SearchParams{Page: 1, Size: 10, IncludeTokenMints: five})
+
+		// Assert
+		s.EqualError(err, "dammv1.SearchPools: 5 include token mints exceed the chunk size of 2")
+		s.Nil(result)
+		s.Zero(requests["/pools/search"])
+	})
+
+	s.Run("should send SearchPools mints within the chunk size in one request", func() {
+		// Act
+		result, err := client.SearchPools(context.Background(), &dammv1.SearchParams{Page: 1, Size: 10, IncludeTokenMints: five[:2]})
+
+		// Assert
+		s.NoError(err)
+		s.Equal(1, requests["/pools/search"])
+		s.Len(result.Data, 3)
+		s.Equal(1, result.Page)
+		s.Equal(13, result.TotalCount)
+	})
+
+	s.Run("should request every combination of ListAlphaVaults chunks", func() {
+		// Act
+		vaults, err := client.ListAlphaVaults(context.Background(), &dammv1.AlphaVaultParams{
+			VaultAddress: []string{"v1", "v2", "v3"},
+			BaseMint:     []string{"m1", "m2", "m3"},
+		})
+
+		// Assert
+		s.NoError(err)
+		s.Equal(4, requests["/alpha-vault"])
+		s.Len(vaults, 3)
+	})
+}
@@ -0,0 +1,235 @@
+package dammv1
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/ua1984/meteora-go/decimal"
+)
+
+// ErrMisalignedTokens is returned by Pool.Tokens when the per-token slices of
+// a pool have different lengths.
+var ErrMisalignedTokens = errors.New("misaligned pool token slices")
+
+// DefaultDepegThreshold is the deviation from its target weight, in
+// percentage points, beyond which a token counts as depegged.
+const DefaultDepegThreshold = 10
+
+// PoolToken is one token of a pool, zipped from the pool's per-token slices.
+type PoolToken struct {
+	// Index is the position of the token in the pool.
+	Index int `json:"index"`
+
+	// Mint is the token mint address.
+	Mint string `json:"mint"`
+
+	// Amount is the amount of the token in the pool.
+	Amount decimal.Decimal `json:"amount"`
+
+	// USDAmount is the USD value of Amount.
+	USDAmount float64 `json:"usd_amount"`
+
+	// Vault is the dynamic vault holding the token.
+	Vault string `json:"vault"`
+
+	// VaultLP is the vault LP token held by the pool.
+	VaultLP string `json:"vault_lp"`
+}
+
+// Tokens zips PoolTokenMints, PoolTokenAmounts, PoolTokenUSDAmounts, Vaults
+// and VaultLPs into one entry per token. It returns an error wrapping
+// ErrMisalignedTokens when the slices have different lengths. Empty amounts
+// parse as zero.
+func (p *Pool) Tokens() ([]PoolToken, error) {
+	n := len(p.PoolTokenMints)
+	if len(p.PoolTokenAmounts) != n || len(p.PoolTokenUSDAmounts) != n || len(p.Vaults) != n || len(p.VaultLPs) != n {
+		return nil, fmt.Errorf("dammv1.Pool.Tokens: %s: %w: %d mints, %d amounts, %d USD amounts, %d vaults, %d vault LPs",
+			p.PoolAddress, ErrMisalignedTokens, n, len(p.PoolTokenAmounts), len(p.PoolTokenUSDAmounts), len(p.Vaults), len(p.VaultLPs))
+	}
+
+	tokens := make([]PoolToken, n)
+	for i := range tokens {
+		t := PoolToken{Index: i, Mint: p.PoolTokenMints[i], Vault: p.Vaults[i], VaultLP: p.VaultLPs[i]}
+		if s := p.PoolTokenAmounts[i]; s != "" {
+			amount, err := decimal.Parse(s)
+			if err != nil {
+				return nil, fmt.Errorf("dammv1.Pool.Tokens: %s: token %d amount: %w", p.PoolAddress, i, err)
+			}
+			t.Amount = amount
+		}
+		if s := p.PoolTokenUSDAmounts[i]; s != "" {
+			usd, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("dammv1.Pool.Tokens: %s: token %d USD amount: %w", p.PoolAddress, i, err)
+			}
+			t.USDAmount = usd
+		}
+		tokens[i] = t
+	}
+	return tokens, nil
+}
+
+// IsMultitoken reports whether the pool holds more than two tokens.
+func (p *Pool) IsMultitoken() bool {
+	return len(p.PoolTokenMints) > 2
+}
+
+// PegGroup is the peg a pool's tokens are expected to hold.
+type PegGroup string
+
+const (
+	// PegGroupLST is a pool of liquid staking tokens, pegged to SOL.
+	PegGroupLST PegGroup = "lst"
+
+	// PegGroupForex is a pool of stablecoins, pegged to a fiat currency.
+	PegGroupForex PegGroup = "forex"
+
+	// PegGroupNone is a pool whose tokens hold no common peg.
+	PegGroupNone PegGroup = "none"
+)
+
+// PegGroup returns the pool's peg group from IsLST and IsForex. A pool with
+// both set is grouped as LST.
+func (p *Pool) PegGroup() PegGroup {
+	switch {
+	case p.IsLST:
+		return PegGroupLST
+	case p.IsForex:
+		return PegGroupForex
+	}
+	return PegGroupNone
+}
+
+// GroupByPeg groups pools by PegGroup, keeping their order within each group.
+func GroupByPeg(pools []Pool) map[PegGroup][]Pool {
+	groups := map[PegGroup][]Pool{}
+	for _, p := range pools {
+		g := p.PegGroup()
+		groups[g] = append(groups[g], p)
+	}
+	return groups
+}
+
+// DepegOptions are optional parameters for Composition and ScanDepegs.
+type DepegOptions struct {
+	// Targets are the target weights of tokens in percent, keyed by mint.
+	// Tokens without a target are expected to hold an equal share of the
+	// pool.
+	Targets map[string]float64
+
+	// Threshold is the deviation from the target weight, in percentage
+	// points, beyond which a token counts as depegged.
+	// Default: DefaultDepegThreshold.
+	Threshold float64
+}
+
+// TokenWeight is the share of a pool's value held by one token.
+type TokenWeight struct {
+	PoolToken
+
+	// Weight is the token's USD value as a percentage of the pool's.
+	Weight float64 `json:"weight"`
+
+	// Target is the weight the token is expected to hold.
+	Target float64 `json:"target"`
+
+	// Deviation is Weight minus Target, in percentage points.
+	Deviation float64 `json:"deviation"`
+
+	// Depegged reports whether the absolute Deviation exceeds the threshold.
+	Depegged bool `json:"depegged"`
+}
+
+// Composition is the split of a pool's value between its tokens.
+type Composition struct {
+	// Pool is the pool address.
+	Pool string `json:"pool"`
+
+	// Name is the pool name.
+	Name string `json:"name"`
+
+	// PegGroup is the pool's peg group.
+	PegGroup PegGroup `json:"peg_group"`
+
+	// TotalUSD is the USD value of all tokens.
+	TotalUSD float64 `json:"total_usd"`
+
+	// Tokens holds one entry per token, in pool order.
+	Tokens []TokenWeight `json:"tokens"`
+
+	// MaxDeviation is the largest absolute Deviation of any token.
+	MaxDeviation float64 `json:"max_deviation"`
+
+	// Depegged reports whether any token is depegged.
+	Depegged bool `json:"depegged"`
+
+	// Err is the error returned for the pool by ScanDepegs.
+	Err error `json:"-"`
+}
+
+// Composition returns the weight of each token in the pool and flags tokens
+// whose weight deviates from its target. A stable or multitoken pool is
+// balanced while its tokens hold their peg; when one depegs, swaps drain the
+// others and its share of the pool's value grows. Weights are zero for a pool
+// without USD value. opts may be nil.
+func (p *Pool) Composition(opts *DepegOptions) (*Composition, error) {
+	tokens, err := p.Tokens()
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &DepegOptions{}
+	}
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultDepegThreshold
+	}
+
+	c := &Composition{Pool: p.PoolAddress, Name: p.PoolName, PegGroup: p.PegGroup(), Tokens: make([]TokenWeight, len(tokens))}
+	for _, t := range tokens {
+		c.TotalUSD += t.USDAmount
+	}
+	for i, t := range tokens {
+		w := TokenWeight{PoolToken: t, Target: 100 / float64(len(tokens))}
+		if target, ok := opts.Targets[t.Mint]; ok {
+			w.Target = target
+		}
+		if c.TotalUSD > 0 {
+			w.Weight = t.USDAmount / c.TotalUSD * 100
+			w.Deviation = w.Weight - w.Target
+			w.Depegged = math.Abs(w.Deviation) > threshold
+		}
+		c.Tokens[i] = w
+		c.MaxDeviation = max(c.MaxDeviation, math.Abs(w.Deviation))
+		c.Depegged = c.Depegged || w.Depegged
+	}
+	return c, nil
+}
+
+// ScanDepegs computes the Composition of every LST and forex pool, most
+// deviated first. A pool whose tokens cannot be read is listed last with Err
+// set. opts may be nil.
+func ScanDepegs(pools []Pool, opts *DepegOptions) []Composition {
+	var comps []Composition
+	for i := range pools {
+		p := &pools[i]
+		if p.PegGroup() == PegGroupNone {
+			continue
+		}
+		c, err := p.Composition(opts)
+		if err != nil {
+			c = &Composition{Pool: p.PoolAddress, Name: p.PoolName, PegGroup: p.PegGroup(), Err: err}
+		}
+		comps = append(comps, *c)
+	}
+	sort.SliceStable(comps, func(i, j int) bool {
+		if (comps[i].Err == nil) != (comps[j].Err == nil) {
+			return comps[i].Err == nil
+		}
+		return comps[i].MaxDeviation > comps[j].MaxDeviation
+	})
+	return comps
+}
@@ -0,0 +1,173 @@
+package dammv1_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/decimal"
+)
+
+type TokensTestSuite struct {
+	suite.Suite
+}
+
+func TestTokens(t *testing.T) {
+	suite.Run(t, new(TokensTestSuite))
+}
+
+func stablePool(address string, usd ...string) dammv1.Pool {
+	p := dammv1.Pool{PoolAddress: address, IsForex: true}
+	for i, u := range usd {
+		p.PoolTokenMints = append(p.PoolTokenMints, string(rune('a'+i)))
+		p.PoolTokenAmounts = append(p.PoolTokenAmounts, u)
+		p.PoolTokenUSDAmounts = append(p.PoolTokenUSDAmounts, u)
+		p.Vaults = append(p.Vaults, "vault-"+string(rune('a'+i)))
+		p.VaultLPs = append(p.VaultLPs, "lp-"+string(rune('a'+i)))
+	}
+	return p
+}
+
+func (s *TokensTestSuite) TestTokens() {
+	tests := []struct {
+		name    string
+		pool    dammv1.Pool
+		wantLen int
+		wantErr string
+	}{
+		{
+			name:    "should zip the per-token slices",
+			pool:    stablePool("pool1", "100.5", "200", "300"),
+			wantLen: 3,
+		},
+		{
+			name:    "should reject misaligned slices",
+			pool:    dammv1.Pool{PoolAddress: "pool1", PoolTokenMints: []string{"a", "b"}, PoolTokenAmounts: []string{"1"}},
+			wantErr: "dammv1.Pool.Tokens: pool1: misaligned pool token slices: 2 mints, 1 amounts",
+		},
+		{
+			name: "should reject invalid amounts",
+			pool: dammv1.Pool{
+				PoolAddress: "pool1", PoolTokenMints: []string{"a"}, PoolTokenAmounts: []string{"x"},
+				PoolTokenUSDAmounts: []string{"1"}, Vaults: []string{"v"}, VaultLPs: []string{"l"},
+			},
+			wantErr: "dammv1.Pool.Tokens: pool1: token 0 amount",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			tokens, err := tt.pool.Tokens()
+
+			// Assert
+			if tt.wantErr != "" {
+				s.ErrorContains(err, tt.wantErr)
+				return
+			}
+			s.Require().NoError(err)
+			s.Require().Len(tokens, tt.wantLen)
+			s.Equal(1, tokens[1].Index)
+			s.Equal("b", tokens[1].Mint)
+			s.Equal(0, tokens[0].Amount.Cmp(decimal.MustParse("100.5")))
+			s.InDelta(200, tokens[1].USDAmount, 1e-9)
+			s.Equal("vault-c", tokens[2].Vault)
+			s.Equal("lp-c", tokens[2].VaultLP)
+		})
+	}
+}
+
+func (s *TokensTestSuite) TestMisalignedIsErrMisalignedTokens() {
+	// Arrange
+	pool := dammv1.Pool{PoolTokenMints: []string{"a"}}
+
+	// Act
+	_, err := pool.Tokens()
+
+	// Assert
+	s.ErrorIs(err, dammv1.ErrMisalignedTokens)
+}
+
+func (s *TokensTestSuite) TestComposition() {
+	tests := []struct {
+		name           string
+		pool           dammv1.Pool
+		opts           *dammv1.DepegOptions
+		wantWeights    []float64
+		wantMax        float64
+		wantDepegged   bool
+		wantMultitoken bool
+	}{
+		{
+			name:        "should report a balanced pool",
+			pool:        stablePool("pool1", "500", "500"),
+			wantWeights: []float64{50, 50},
+		},
+		{
+			name:         "should flag a depegged token",
+			pool:         stablePool("pool1", "800", "200"),
+			wantWeights:  []float64{80, 20},
+			wantMax:      30,
+			wantDepegged: true,
+		},
+		{
+			name:        "should use the threshold option",
+			pool:        stablePool("pool1", "800", "200"),
+			opts:        &dammv1.DepegOptions{Threshold: 40},
+			wantWeights: []float64{80, 20},
+			wantMax:     30,
+		},
+		{
+			name:           "should use target weights",
+			pool:           stablePool("pool1", "600", "200", "200"),
+			opts:           &dammv1.DepegOptions{Targets: map[string]float64{"a": 60, "b": 20, "c": 20}},
+			wantWeights:    []float64{60, 20, 20},
+			wantMultitoken: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			c, err := tt.pool.Composition(tt.opts)
+
+			// Assert
+			s.Require().NoError(err)
+			s.Require().Len(c.Tokens, len(tt.wantWeights))
+			for i, w := range tt.wantWeights {
+				s.InDelta(w, c.Tokens[i].Weight, 1e-9)
+			}
+			s.InDelta(tt.wantMax, c.MaxDeviation, 1e-9)
+			s.Equal(tt.wantDepegged, c.Depegged)
+			s.Equal(tt.wantMultitoken, tt.pool.IsMultitoken())
+			s.Equal(dammv1.PegGroupForex, c.PegGroup)
+		})
+	}
+}
+
+func (s *TokensTestSuite) TestGroupAndScan() {
+	// Arrange
+	lst := stablePool("lst", "900", "100")
+	lst.IsLST, lst.IsForex = true, false
+	balanced := stablePool("balanced", "500", "500")
+	drifting := stablePool("drifting", "650", "350")
+	broken := dammv1.Pool{PoolAddress: "broken", IsForex: true, PoolTokenMints: []string{"a"}}
+	volatile := dammv1.Pool{PoolAddress: "volatile"}
+	pools := []dammv1.Pool{balanced, volatile, broken, drifting, lst}
+
+	// Act
+	groups := dammv1.GroupByPeg(pools)
+	comps := dammv1.ScanDepegs(pools, nil)
+
+	// Assert
+	s.Len(groups[dammv1.PegGroupForex], 3)
+	s.Len(groups[dammv1.PegGroupLST], 1)
+	s.Equal("volatile", groups[dammv1.PegGroupNone][0].PoolAddress)
+
+	s.Require().Len(comps, 4)
+	s.Equal([]string{"lst", "drifting", "balanced", "broken"}, []string{comps[0].Pool, comps[1].Pool, comps[2].Pool, comps[3].Pool})
+	s.True(comps[0].Depegged)
+	s.True(comps[1].Depegged)
+	s.False(comps[2].Depegged)
+	s.ErrorIs(comps[3].Err, dammv1.ErrMisalignedTokens)
+}
@@ -0,0 +1,41 @@
+package dammv2
+
+import "context"
+
+// API is the set of DAMM v2 requests implemented by Client. Code that depends
+// on API instead of *Client can substitute a fake, such as the ones in the
+// meteoratest package.
+type API interface {
+	// ListPools returns a paginated list of DAMM v2 pools.
+	ListPools(ctx context.Context, params *ListPoolsParams) (*PaginatedResponse[Pool], error)
+
+	// ListGroups returns a paginated list of pool groups.
+	ListGroups(ctx context.Context, params *ListGroupsParams) (*PaginatedResponse[PoolGroup], error)
+
+	// GetGroup returns pools within a specific token pair group.
+	GetGroup(ctx context.Context, lexicalOrderMints string, params *GetGroupParams) (*PaginatedResponse[Pool], error)
+
+	// GetPool returns a single pool by address.
+	GetPool(ctx context.Context, address string) (*Pool, error)
+
+	// GetOHLCV returns OHLCV candlestick data for a pool.
+	GetOHLCV(ctx context.Context, address string, params *OHLCVParams) (*OHLCVResponse, error)
+
+	// GetVolumeHistory returns volume history for a pool.
+	GetVolumeHistory(ctx context.Context, address string, params *VolumeHistoryParams) (*VolumeHistoryResponse, error)
+
+	// GetClosedPositions returns a cursor-paginated list of closed positions for a
+	// wallet.
+	GetClosedPositions(ctx context.Context, wallet string, params *GetClosedPositionsParams) (*CursorPaginatedResponse[ClosedPosition], error)
+
+	// GetOpenPositions returns all open positions grouped by pool for a wallet.
+	GetOpenPositions(ctx context.Context, wallet string, params *GetOpenPositionsParams) (*OpenPositionsResponse, error)
+
+	// GetProtocolMetrics returns protocol-wide metrics.
+	GetProtocolMetrics(ctx context.Context) (*ProtocolMetrics, error)
+
+	// GetPools fetches multiple pools concurrently with GetPool.
+	GetPools(ctx context.Context, addresses []string, opts *BatchOptions) []PoolResult
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,47 @@
+package dammv2
+
+import (
+	"context"
+
+	"github.com/ua1984/meteora-go/internal/batch"
+)
+
+// BatchOptions are optional parameters for batch methods such as GetPools.
+type BatchOptions struct {
+	// Concurrency is the maximum number of requests in flight. Default: 8.
+	// Requests are additionally throttled by the client's rate limit, if set.
+	Concurrency int
+}
+
+// PoolResult is the outcome of fetching a single pool in a batch.
+type PoolResult struct {
+	// Address is the requested pool address.
+	Address string
+
+	// Pool is the fetched pool, or nil if Err is set.
+	Pool *Pool
+
+	// Err is the error returned for this address, if any.
+	Err error
+}
+
+// GetPools fetches multiple pools concurrently with GetPool. It returns one
+// result per unique address, in order of first appearance. Failures are
+// reported per address so that a partial result is always returned.
+func (c *Client) GetPools(ctx context.Context, addresses []string, opts *BatchOptions) []PoolResult {
+	unique := batch.Unique(addresses)
+	results := make([]PoolResult, len(unique))
+	batch.Run(ctx, len(unique), opts.concurrency(), func(ctx context.Context, i int) {
+		pool, err := c.GetPool(ctx, unique[i])
+		results[i] = PoolResult{Address: unique[i], Pool: pool, Err: err}
+	})
+
+	return results
+}
+
+func (o *BatchOptions) concurrency() int {
+	if o == nil {
+		return 0
+	}
+	return o.Concurrency
+}
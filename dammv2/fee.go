@@ -0,0 +1,132 @@
+package dammv2
+
+import (
+	"math"
+	"time"
+)
+
+// FeeScheduler holds the on-chain fee scheduler parameters of a pool whose
+// BaseFeeMode is BaseFeeModeLinear or BaseFeeModeExponential. The API does
+// not report them, so they must be read from the pool account.
+type FeeScheduler struct {
+	// CliffFeePct is the base fee percentage at activation.
+	CliffFeePct float64
+
+	// NumberOfPeriods is the number of periods after which the fee stops
+	// decaying.
+	NumberOfPeriods int
+
+	// PeriodFrequency is the length of one period.
+	PeriodFrequency time.Duration
+
+	// Reduction is the decay per period. For BaseFeeModeLinear it is the
+	// percentage points subtracted from the fee each period; for
+	// BaseFeeModeExponential it is the basis points of the fee removed each
+	// period.
+	Reduction float64
+}
+
+// FeeOptions are optional parameters for EffectiveFee.
+type FeeOptions struct {
+	// Scheduler holds the fee scheduler parameters. When nil, the base fee is
+	// the BaseFeePct reported by the API.
+	Scheduler *FeeScheduler
+
+	// Activation overrides the pool's activation time, for pools with
+	// slot-based activation. Default: Pool.ActivationTime.
+	Activation time.Time
+
+	// DynamicFeePct is the current dynamic fee percentage. It is added to the
+	// base fee when the pool's dynamic fee is initialized.
+	DynamicFeePct float64
+
+	// Referral reports whether the swap has a referrer, who then receives
+	// ReferralFeePct of the protocol fee.
+	Referral bool
+}
+
+// Fee is the effective swap fee of a pool at a point in time and how it is
+// split. All values are percentages of the swap amount.
+type Fee struct {
+	// Mode is the pool's base fee mode.
+	Mode BaseFeeMode `json:"mode"`
+
+	// Period is the number of elapsed fee scheduler periods. It is zero when
+	// no scheduler applies.
+	Period int `json:"period"`
+
+	// BaseFeePct is the base fee after scheduler decay.
+	BaseFeePct float64 `json:"base_fee_pct"`
+
+	// DynamicFeePct is the dynamic fee added to the base fee.
+	DynamicFeePct float64 `json:"dynamic_fee_pct"`
+
+	// TotalPct is BaseFeePct plus DynamicFeePct: the fee a swap pays.
+	TotalPct float64 `json:"total_pct"`
+
+	// LPPct is the part of TotalPct earned by liquidity providers.
+	LPPct float64 `json:"lp_pct"`
+
+	// ProtocolPct is the part of TotalPct kept by the protocol.
+	ProtocolPct float64 `json:"protocol_pct"`
+
+	// PartnerPct is the part of TotalPct paid to the partner.
+	PartnerPct float64 `json:"partner_pct"`
+
+	// ReferralPct is the part of TotalPct paid to the referrer.
+	ReferralPct float64 `json:"referral_pct"`
+}
+
+// EffectiveFee returns the swap fee of the pool at t and its split. opts may
+// be nil.
+//
+// With a fee scheduler, the base fee starts at CliffFeePct at activation and
+// decays once per elapsed period until NumberOfPeriods; before activation, or
+// when the activation time is unknown, it is CliffFeePct. Rate limiter pools
+// charge more for large swaps, which is not modeled: their base fee is
+// BaseFeePct.
+//
+// The split follows the program: ProtocolFeePct of the fee goes to the
+// protocol side and the rest to LPs; for referred swaps ReferralFeePct of the
+// protocol side goes to the referrer first, then PartnerFeePct of the
+// remainder to the partner.
+func (p *Pool) EffectiveFee(t time.Time, opts *FeeOptions) Fee {
+	if opts == nil {
+		opts = &FeeOptions{}
+	}
+	cfg := p.PoolConfig
+	f := Fee{Mode: cfg.BaseFeeMode, BaseFeePct: cfg.BaseFeePct}
+
+	if s := opts.Scheduler; s != nil && (cfg.BaseFeeMode == BaseFeeModeLinear || cfg.BaseFeeMode == BaseFeeModeExponential) {
+		activation := opts.Activation
+		if activation.IsZero() {
+			activation = p.ActivationTime()
+		}
+		if !activation.IsZero() && t.After(activation) && s.PeriodFrequency > 0 {
+			f.Period = min(int(t.Sub(activation)/s.PeriodFrequency), s.NumberOfPeriods)
+		}
+		f.BaseFeePct = s.feeAt(cfg.BaseFeeMode, f.Period)
+	}
+	if cfg.DynamicFeeInitialized {
+		f.DynamicFeePct = opts.DynamicFeePct
+	}
+	f.TotalPct = f.BaseFeePct + f.DynamicFeePct
+
+	protocol := f.TotalPct * cfg.ProtocolFeePct / 100
+	f.LPPct = f.TotalPct - protocol
+	if opts.Referral {
+		f.ReferralPct = protocol * cfg.ReferralFeePct / 100
+		protocol -= f.ReferralPct
+	}
+	f.PartnerPct = protocol * cfg.PartnerFeePct / 100
+	protocol -= f.PartnerPct
+	f.ProtocolPct = protocol
+	return f
+}
+
+func (s *FeeScheduler) feeAt(mode BaseFeeMode, period int) float64 {
+	if mode == BaseFeeModeExponential {
+		return s.CliffFeePct * math.Pow(1-s.Reduction/10_000, float64(period))
+	}
+	return max(s.CliffFeePct-s.Reduction*float64(period), 0)
+}
@@ -0,0 +1,190 @@
+package dammv2_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dammv2"
+)
+
+type FeeTestSuite struct {
+	suite.Suite
+	activation time.Time
+	pool       dammv2.Pool
+}
+
+func TestFee(t *testing.T) {
+	suite.Run(t, new(FeeTestSuite))
+}
+
+func (s *FeeTestSuite) SetupTest() {
+	s.activation = time.Unix(1_700_000_000, 0)
+	s.pool = dammv2.Pool{PoolConfig: dammv2.PoolConfig{
+		BaseFeeMode:     dammv2.BaseFeeModeLinear,
+		BaseFeePct:      0.25,
+		ProtocolFeePct:  20,
+		PartnerFeePct:   50,
+		ReferralFeePct:  20,
+		ActivationType:  dammv2.ActivationTypeTimestamp,
+		ActivationPoint: s.activation.Unix(),
+	}}
+}
+
+func (s *FeeTestSuite) TestModes() {
+	// Arrange
+	var cfg dammv2.PoolConfig
+
+	// Act
+	err := json.Unmarshal([]byte(`{"base_fee_mode":1,"collect_fee_mode":1}`), &cfg)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(dammv2.BaseFeeModeExponential, cfg.BaseFeeMode)
+	s.Equal("exponential", cfg.BaseFeeMode.String())
+	s.Equal(dammv2.CollectFeeModeQuote, cfg.CollectFeeMode)
+	s.Equal("quote", cfg.CollectFeeMode.String())
+	s.False(dammv2.BaseFeeMode(3).IsValid())
+	s.Equal("unknown", dammv2.CollectFeeMode(2).String())
+}
+
+func (s *FeeTestSuite) TestEffectiveFee() {
+	scheduler := &dammv2.FeeScheduler{CliffFeePct: 50, NumberOfPeriods: 10, PeriodFrequency: time.Minute, Reduction: 4}
+
+	tests := []struct {
+		name       string
+		mode       dammv2.BaseFeeMode
+		at         time.Duration
+		opts       *dammv2.FeeOptions
+		wantPeriod int
+		wantBase   float64
+	}{
+		{
+			name:     "should use the reported base fee without a scheduler",
+			at:       time.Hour,
+			wantBase: 0.25,
+		},
+		{
+			name:     "should charge the cliff fee before activation",
+			at:       -time.Minute,
+			opts:     &dammv2.FeeOptions{Scheduler: scheduler},
+			wantBase: 50,
+		},
+		{
+			name:       "should decay linearly",
+			at:         150 * time.Second,
+			opts:       &dammv2.FeeOptions{Scheduler: scheduler},
+			wantPeriod: 2,
+			wantBase:   42,
+		},
+		{
+			name:       "should stop decaying after the last period",
+			at:         time.Hour,
+			opts:       &dammv2.FeeOptions{Scheduler: scheduler},
+			wantPeriod: 10,
+			wantBase:   10,
+		},
+		{
+			name:       "should decay exponentially",
+			mode:       dammv2.BaseFeeModeExponential,
+			at:         2 * time.Minute,
+			opts:       &dammv2.FeeOptions{Scheduler: &dammv2.FeeScheduler{CliffFeePct: 50, NumberOfPeriods: 10, PeriodFrequency: time.Minute, Reduction: 5000}},
+			wantPeriod: 2,
+			wantBase:   12.5,
+		},
+		{
+			name:     "should ignore the scheduler for rate limiter pools",
+			mode:     dammv2.BaseFeeModeRateLimiter,
+			at:       time.Hour,
+			opts:     &dammv2.FeeOptions{Scheduler: scheduler},
+			wantBase: 0.25,
+		},
+		{
+			name:       "should use the activation override",
+			at:         time.Hour,
+			opts:       &dammv2.FeeOptions{Scheduler: scheduler, Activation: s.activation.Add(59 * time.Minute)},
+			wantPeriod: 1,
+			wantBase:   46,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Arrange
+			s.pool.PoolConfig.BaseFeeMode = tt.mode
+
+			// Act
+			f := s.pool.EffectiveFee(s.activation.Add(tt.at), tt.opts)
+
+			// Assert
+			s.Equal(tt.wantPeriod, f.Period)
+			s.InDelta(tt.wantBase, f.BaseFeePct, 1e-9)
+			s.InDelta(tt.wantBase, f.TotalPct, 1e-9)
+		})
+	}
+}
+
+func (s *FeeTestSuite) TestEffectiveFeeSplit() {
+	tests := []struct {
+		name         string
+		dynamic      bool
+		opts         *dammv2.FeeOptions
+		wantTotal    float64
+		wantLP       float64
+		wantProtocol float64
+		wantPartner  float64
+		wantReferral float64
+	}{
+		{
+			name:         "should split between LP, protocol and partner",
+			wantTotal:    0.25,
+			wantLP:       0.2,
+			wantProtocol: 0.025,
+			wantPartner:  0.025,
+		},
+		{
+			name:         "should pay the referrer from the protocol fee",
+			opts:         &dammv2.FeeOptions{Referral: true},
+			wantTotal:    0.25,
+			wantLP:       0.2,
+			wantProtocol: 0.02,
+			wantPartner:  0.02,
+			wantReferral: 0.01,
+		},
+		{
+			name:         "should add the dynamic fee when initialized",
+			dynamic:      true,
+			opts:         &dammv2.FeeOptions{DynamicFeePct: 0.25},
+			wantTotal:    0.5,
+			wantLP:       0.4,
+			wantProtocol: 0.05,
+			wantPartner:  0.05,
+		},
+		{
+			name:         "should ignore the dynamic fee when not initialized",
+			opts:         &dammv2.FeeOptions{DynamicFeePct: 0.25},
+			wantTotal:    0.25,
+			wantLP:       0.2,
+			wantProtocol: 0.025,
+			wantPartner:  0.025,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Arrange
+			s.pool.PoolConfig.DynamicFeeInitialized = tt.dynamic
+
+			// Act
+			f := s.pool.EffectiveFee(s.activation, tt.opts)
+
+			// Assert
+			s.InDelta(tt.wantTotal, f.TotalPct, 1e-9)
+			s.InDelta(tt.wantLP, f.LPPct, 1e-9)
+			s.InDelta(tt.wantProtocol, f.ProtocolPct, 1e-9)
+			s.InDelta(tt.wantPartner, f.PartnerPct, 1e-9)
+			s.InDelta(tt.wantReferral, f.ReferralPct, 1e-9)
+		})
+	}
+}
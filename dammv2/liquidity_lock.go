@@ -0,0 +1,174 @@
+package dammv2
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultLaunchWindow is how long after activation a pool's locked liquidity
+// still counts as locked at launch.
+const DefaultLaunchWindow = 24 * time.Hour
+
+// Weights of each kind of locked liquidity in LiquidityLock.Score.
+const (
+	permanentLockWeight = 1
+	vested6MonthsWeight = 0.75
+	vested3MonthsWeight = 0.5
+)
+
+// LiquidityLock describes how much of a pool's liquidity is locked or vested.
+// Amounts are in USD and shares are percentages of TVL.
+type LiquidityLock struct {
+	// Address is the pool address.
+	Address string `json:"address"`
+
+	// Name is the pool name.
+	Name string `json:"name"`
+
+	// TVL is the pool's total value locked.
+	TVL float64 `json:"tvl"`
+
+	// PermanentLocked is the permanently locked liquidity.
+	PermanentLocked float64 `json:"permanent_locked"`
+
+	// Vested is the liquidity vesting over 3 or 6 months.
+	Vested float64 `json:"vested"`
+
+	// Locked is PermanentLocked plus Vested, capped at TVL.
+	Locked float64 `json:"locked"`
+
+	// Withdrawable is the liquidity LPs can withdraw now: TVL minus Locked.
+	Withdrawable float64 `json:"withdrawable"`
+
+	// PermanentShare is PermanentLocked as a share of TVL.
+	PermanentShare float64 `json:"permanent_share"`
+
+	// VestedShare is Vested as a share of TVL.
+	VestedShare float64 `json:"vested_share"`
+
+	// LockedShare is Locked as a share of TVL.
+	LockedShare float64 `json:"locked_share"`
+
+	// WithdrawableShare is Withdrawable as a share of TVL.
+	WithdrawableShare float64 `json:"withdrawable_share"`
+
+	// Score rates rug-pull resistance from 0 to 100: the share of TVL that is
+	// locked, with permanently locked liquidity counting in full, 6-month
+	// vesting at 75% and 3-month vesting at 50%.
+	Score float64 `json:"score"`
+
+	// Activation is when the pool started or starts trading. It is zero for
+	// slot-based activation, whose time the API does not report.
+	Activation time.Time `json:"activation"`
+
+	// Activated reports whether the pool was trading at the time of the
+	// analysis. It is false when Activation is unknown.
+	Activated bool `json:"activated"`
+
+	// LaunchObserved reports whether the analysis was made before activation
+	// or within the launch window after it. The API only reports current lock
+	// amounts, so whether liquidity was locked at launch can only be told for
+	// pools observed around their launch.
+	LaunchObserved bool `json:"launch_observed"`
+
+	// LockedAtLaunch reports whether the pool had locked liquidity when it was
+	// observed around its launch. It is false when LaunchObserved is false.
+	LockedAtLaunch bool `json:"locked_at_launch"`
+}
+
+// ActivationTime returns when the pool started or starts trading: its creation
+// time for immediate activation and ActivationPoint for timestamp activation.
+// It returns the zero time for slot-based activation, whose time the API does
+// not report.
+func (p *Pool) ActivationTime() time.Time {
+	switch p.PoolConfig.ActivationType {
+	case ActivationTypeImmediate:
+		return time.Unix(p.CreatedAt, 0)
+	case ActivationTypeTimestamp:
+		return time.Unix(p.PoolConfig.ActivationPoint, 0)
+	}
+	return time.Time{}
+}
+
+// LockOptions are optional parameters for AnalyzeLiquidityLock and
+// RankByLockedLiquidity.
+type LockOptions struct {
+	// Now is the time of the analysis. Default: time.Now().
+	Now time.Time
+
+	// LaunchWindow is how long after activation a pool still counts as
+	// observed at launch. Default: DefaultLaunchWindow.
+	LaunchWindow time.Duration
+}
+
+// AnalyzeLiquidityLock computes the locked, vested and withdrawable liquidity
+// of p. opts may be nil.
+func AnalyzeLiquidityLock(p *Pool, opts *LockOptions) LiquidityLock {
+	l := LiquidityLock{
+		Address:         p.Address,
+		Name:            p.Name,
+		TVL:             p.TVL,
+		PermanentLocked: p.PermanentLockLiquidity,
+		Vested:          p.VestedLiquidity.Months3 + p.VestedLiquidity.Months6,
+	}
+	l.Locked = min(l.PermanentLocked+l.Vested, max(l.TVL, 0))
+	l.Withdrawable = max(l.TVL-l.Locked, 0)
+	if l.TVL > 0 {
+		l.PermanentShare = l.PermanentLocked / l.TVL * 100
+		l.VestedShare = l.Vested / l.TVL * 100
+		l.LockedShare = l.Locked / l.TVL * 100
+		l.WithdrawableShare = l.Withdrawable / l.TVL * 100
+		weighted := permanentLockWeight*p.PermanentLockLiquidity +
+			vested6MonthsWeight*p.VestedLiquidity.Months6 +
+			vested3MonthsWeight*p.VestedLiquidity.Months3
+		l.Score = min(weighted/l.TVL*100, 100)
+	}
+
+	l.Activation = p.ActivationTime()
+	if !l.Activation.IsZero() {
+		now := opts.now()
+		l.Activated = !now.Before(l.Activation)
+		l.LaunchObserved = now.Before(l.Activation.Add(opts.launchWindow()))
+		l.LockedAtLaunch = l.LaunchObserved && l.Locked > 0
+	}
+	return l
+}
+
+// RankByLockedLiquidity analyzes pools with AnalyzeLiquidityLock and ranks
+// them by rug-pull resistance, highest Score first and larger TVL first among
+// equal scores. opts may be nil.
+func RankByLockedLiquidity(pools []Pool, opts *LockOptions) []LiquidityLock {
+	if opts == nil || opts.Now.IsZero() {
+		o := LockOptions{Now: time.Now()}
+		if opts != nil {
+			o.LaunchWindow = opts.LaunchWindow
+		}
+		opts = &o
+	}
+
+	locks := make([]LiquidityLock, len(pools))
+	for i := range pools {
+		locks[i] = AnalyzeLiquidityLock(&pools[i], opts)
+	}
+	sort.SliceStable(locks, func(i, j int) bool {
+		if locks[i].Score != locks[j].Score {
+			return locks[i].Score > locks[j].Score
+		}
+		return locks[i].TVL > locks[j].TVL
+	})
+	return locks
+}
+
+func (o *LockOptions) now() time.Time {
+	if o == nil || o.Now.IsZero() {
+		return time.Now()
+	}
+	return o.Now
+}
+
+func (o *LockOptions) launchWindow() time.Duration {
+	if o == nil || o.LaunchWindow <= 0 {
+		return DefaultLaunchWindow
+	}
+	return o.LaunchWindow
+}
@@ -0,0 +1,150 @@
+package dammv2_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dammv2"
+)
+
+type LiquidityLockTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestLiquidityLock(t *testing.T) {
+	suite.Run(t, new(LiquidityLockTestSuite))
+}
+
+func (s *LiquidityLockTestSuite) SetupTest() {
+	s.now = time.Unix(1_700_000_000, 0)
+}
+
+func (s *LiquidityLockTestSuite) TestAnalyzeLiquidityLock() {
+	// Arrange
+	pool := dammv2.Pool{
+		Address:                "pool1",
+		TVL:                    1000,
+		PermanentLockLiquidity: 400,
+		VestedLiquidity:        dammv2.VestedLiquidity{Months3: 100, Months6: 200},
+	}
+
+	// Act
+	l := dammv2.AnalyzeLiquidityLock(&pool, &dammv2.LockOptions{Now: s.now})
+
+	// Assert
+	s.Equal("pool1", l.Address)
+	s.Equal(300.0, l.Vested)
+	s.Equal(700.0, l.Locked)
+	s.Equal(300.0, l.Withdrawable)
+	s.InDelta(40, l.PermanentShare, 1e-9)
+	s.InDelta(30, l.VestedShare, 1e-9)
+	s.InDelta(70, l.LockedShare, 1e-9)
+	s.InDelta(30, l.WithdrawableShare, 1e-9)
+	s.InDelta(60, l.Score, 1e-9)
+}
+
+func (s *LiquidityLockTestSuite) TestAnalyzeLiquidityLockCapsAtTVL() {
+	// Arrange
+	pool := dammv2.Pool{TVL: 100, PermanentLockLiquidity: 150}
+
+	// Act
+	l := dammv2.AnalyzeLiquidityLock(&pool, nil)
+
+	// Assert
+	s.Equal(100.0, l.Locked)
+	s.Zero(l.Withdrawable)
+	s.Equal(100.0, l.Score)
+}
+
+func (s *LiquidityLockTestSuite) TestActivationType() {
+	// Arrange
+	var cfg dammv2.PoolConfig
+
+	// Act
+	err := json.Unmarshal([]byte(`{"activation_type": 1}`), &cfg)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(dammv2.ActivationTypeSlot, cfg.ActivationType)
+	s.Equal("slot", cfg.ActivationType.String())
+	s.True(cfg.ActivationType.IsValid())
+	s.False(dammv2.ActivationType(3).IsValid())
+	s.Equal("unknown", dammv2.ActivationType(3).String())
+}
+
+func (s *LiquidityLockTestSuite) TestLaunch() {
+	tests := []struct {
+		name               string
+		config             dammv2.PoolConfig
+		createdAt          int64
+		locked             float64
+		wantActivated      bool
+		wantLaunchObserved bool
+		wantLockedAtLaunch bool
+	}{
+		{
+			name:               "should detect liquidity locked before activation",
+			config:             dammv2.PoolConfig{ActivationType: dammv2.ActivationTypeTimestamp, ActivationPoint: s.now.Unix() + 3600},
+			locked:             10,
+			wantLaunchObserved: true,
+			wantLockedAtLaunch: true,
+		},
+		{
+			name:               "should observe the launch within the window",
+			config:             dammv2.PoolConfig{ActivationType: dammv2.ActivationTypeTimestamp, ActivationPoint: s.now.Unix() - 3600},
+			wantActivated:      true,
+			wantLaunchObserved: true,
+		},
+		{
+			name:          "should use the creation time for immediate activation",
+			config:        dammv2.PoolConfig{ActivationType: dammv2.ActivationTypeImmediate},
+			createdAt:     s.now.Unix() - 48*3600,
+			locked:        10,
+			wantActivated: true,
+		},
+		{
+			name:   "should not tell for slot-based activation",
+			config: dammv2.PoolConfig{ActivationType: dammv2.ActivationTypeSlot, ActivationPoint: 1},
+			locked: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Arrange
+			pool := dammv2.Pool{TVL: 100, PermanentLockLiquidity: tt.locked, CreatedAt: tt.createdAt, PoolConfig: tt.config}
+
+			// Act
+			l := dammv2.AnalyzeLiquidityLock(&pool, &dammv2.LockOptions{Now: s.now})
+
+			// Assert
+			s.Equal(tt.wantActivated, l.Activated)
+			s.Equal(tt.wantLaunchObserved, l.LaunchObserved)
+			s.Equal(tt.wantLockedAtLaunch, l.LockedAtLaunch)
+		})
+	}
+}
+
+func (s *LiquidityLockTestSuite) TestRankByLockedLiquidity() {
+	// Arrange
+	pools := []dammv2.Pool{
+		{Address: "unlocked", TVL: 5000},
+		{Address: "vested", TVL: 100, VestedLiquidity: dammv2.VestedLiquidity{Months6: 100}},
+		{Address: "permanent-small", TVL: 100, PermanentLockLiquidity: 100},
+		{Address: "permanent-large", TVL: 200, PermanentLockLiquidity: 200},
+	}
+
+	// Act
+	ranks := dammv2.RankByLockedLiquidity(pools, nil)
+
+	// Assert
+	s.Require().Len(ranks, 4)
+	s.Equal("permanent-large", ranks[0].Address)
+	s.Equal("permanent-small", ranks[1].Address)
+	s.Equal("vested", ranks[2].Address)
+	s.InDelta(75, ranks[2].Score, 1e-9)
+	s.Equal("unlocked", ranks[3].Address)
+}
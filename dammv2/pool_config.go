@@ -1,14 +1,109 @@
 package dammv2
 
+// CollectFeeMode determines in which tokens a pool collects swap fees.
+type CollectFeeMode int
+
+const (
+	// CollectFeeModeBoth collects fees in the input token of each swap.
+	CollectFeeModeBoth CollectFeeMode = 0
+
+	// CollectFeeModeQuote collects fees in the quote token (token Y) only.
+	CollectFeeModeQuote CollectFeeMode = 1
+)
+
+// String returns the name of m, or "unknown" for an unknown mode.
+func (m CollectFeeMode) String() string {
+	switch m {
+	case CollectFeeModeBoth:
+		return "both"
+	case CollectFeeModeQuote:
+		return "quote"
+	}
+	return "unknown"
+}
+
+// IsValid reports whether m is one of the known collect fee modes.
+func (m CollectFeeMode) IsValid() bool {
+	return m == CollectFeeModeBoth || m == CollectFeeModeQuote
+}
+
+// BaseFeeMode determines how a pool's base fee changes over time.
+type BaseFeeMode int
+
+const (
+	// BaseFeeModeLinear is a fee scheduler that lowers the fee by a fixed
+	// amount each period after activation.
+	BaseFeeModeLinear BaseFeeMode = 0
+
+	// BaseFeeModeExponential is a fee scheduler that lowers the fee by a fixed
+	// fraction each period after activation.
+	BaseFeeModeExponential BaseFeeMode = 1
+
+	// BaseFeeModeRateLimiter raises the fee with the size of a swap instead of
+	// decaying over time.
+	BaseFeeModeRateLimiter BaseFeeMode = 2
+)
+
+// String returns the name of m, or "unknown" for an unknown mode.
+func (m BaseFeeMode) String() string {
+	switch m {
+	case BaseFeeModeLinear:
+		return "linear"
+	case BaseFeeModeExponential:
+		return "exponential"
+	case BaseFeeModeRateLimiter:
+		return "rate_limiter"
+	}
+	return "unknown"
+}
+
+// IsValid reports whether m is one of the known base fee modes.
+func (m BaseFeeMode) IsValid() bool {
+	return m >= BaseFeeModeLinear && m <= BaseFeeModeRateLimiter
+}
+
+// ActivationType determines when a pool starts trading.
+type ActivationType int
+
+const (
+	// ActivationTypeImmediate pools trade as soon as they are created.
+	ActivationTypeImmediate ActivationType = 0
+
+	// ActivationTypeSlot pools start trading at the slot in ActivationPoint.
+	ActivationTypeSlot ActivationType = 1
+
+	// ActivationTypeTimestamp pools start trading at the Unix timestamp in
+	// ActivationPoint.
+	ActivationTypeTimestamp ActivationType = 2
+)
+
+// String returns the name of t, or "unknown" for an unknown type.
+func (t ActivationType) String() string {
+	switch t {
+	case ActivationTypeImmediate:
+		return "immediate"
+	case ActivationTypeSlot:
+		return "slot"
+	case ActivationTypeTimestamp:
+		return "timestamp"
+	}
+	return "unknown"
+}
+
+// IsValid reports whether t is one of the known activation types.
+func (t ActivationType) IsValid() bool {
+	return t >= ActivationTypeImmediate && t <= ActivationTypeTimestamp
+}
+
 // PoolConfig holds the configuration parameters for a DAMM v2 pool.
 // DAMM v2 pools have more configuration options than DLMM, including
 // concentrated liquidity settings and activation controls.
 type PoolConfig struct {
-	// CollectFeeMode determines how fees are collected (0 = standard, 1 = quote only).
-	CollectFeeMode int `json:"collect_fee_mode"`
+	// CollectFeeMode determines in which tokens fees are collected.
+	CollectFeeMode CollectFeeMode `json:"collect_fee_mode"`
 
 	// BaseFeeMode determines the base fee calculation method.
-	BaseFeeMode int `json:"base_fee_mode"`
+	BaseFeeMode BaseFeeMode `json:"base_fee_mode"`
 
 	// BaseFeePct is the base swap fee percentage.
 	BaseFeePct float64 `json:"base_fee_pct"`
@@ -37,8 +132,8 @@ type PoolConfig struct {
 	// MaxPrice is the maximum price bound for concentrated liquidity pools.
 	MaxPrice float64 `json:"max_price"`
 
-	// ActivationType determines when trading begins (0 = immediate, 1 = slot-based, 2 = time-based).
-	ActivationType int `json:"activation_type"`
+	// ActivationType determines when trading begins.
+	ActivationType ActivationType `json:"activation_type"`
 
 	// ActivationPoint is the slot number or Unix timestamp when trading activates,
 	// depending on ActivationType.
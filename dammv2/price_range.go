@@ -0,0 +1,255 @@
+package dammv2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ua1984/meteora-go/internal/batch"
+)
+
+// RangeStatus is where a price lies relative to a price range.
+type RangeStatus string
+
+const (
+	// RangeStatusIn means the price is within the range, bounds included.
+	RangeStatusIn RangeStatus = "in_range"
+
+	// RangeStatusBelow means the price is below the minimum price.
+	RangeStatusBelow RangeStatus = "below"
+
+	// RangeStatusAbove means the price is above the maximum price.
+	RangeStatusAbove RangeStatus = "above"
+)
+
+// PriceRange is the price range of a concentrated liquidity pool.
+type PriceRange struct {
+	// Min is the minimum price.
+	Min float64 `json:"min"`
+
+	// Max is the maximum price.
+	Max float64 `json:"max"`
+}
+
+// Valid reports whether 0 < Min < Max.
+func (r PriceRange) Valid() bool {
+	return r.Min > 0 && r.Max > r.Min
+}
+
+// RangePosition describes where a price lies within a price range.
+type RangePosition struct {
+	// Range is the price range.
+	Range PriceRange `json:"range"`
+
+	// Price is the price located in Range.
+	Price float64 `json:"price"`
+
+	// Status is where Price lies relative to Range.
+	Status RangeStatus `json:"status"`
+
+	// DistanceToMin is how far the price must fall to reach Min, as a
+	// percentage of Price. It is negative when the price is below Min.
+	DistanceToMin float64 `json:"distance_to_min"`
+
+	// DistanceToMax is how far the price must rise to reach Max, as a
+	// percentage of Price. It is negative when the price is above Max.
+	DistanceToMax float64 `json:"distance_to_max"`
+
+	// CapitalEfficiency is how many times more liquidity the range provides
+	// at Price than a full-range pool holding the same capital. It is zero
+	// outside the range, where the liquidity does not trade.
+	CapitalEfficiency float64 `json:"capital_efficiency"`
+}
+
+// InRange reports whether the price is within the range.
+func (p RangePosition) InRange() bool {
+	return p.Status == RangeStatusIn
+}
+
+// Locate returns the position of price within r. r must be valid and price
+// positive.
+func (r PriceRange) Locate(price float64) RangePosition {
+	p := RangePosition{
+		Range:         r,
+		Price:         price,
+		Status:        r.status(price, price),
+		DistanceToMin: (price - r.Min) / price * 100,
+		DistanceToMax: (r.Max - price) / price * 100,
+	}
+	if p.InRange() {
+		p.CapitalEfficiency = r.capitalEfficiency(price)
+	}
+	return p
+}
+
+// status returns where a candle with the given low and high prices lies: in
+// range if any part of it overlaps r.
+func (r PriceRange) status(low, high float64) RangeStatus {
+	switch {
+	case high < r.Min:
+		return RangeStatusBelow
+	case low > r.Max:
+		return RangeStatusAbove
+	default:
+		return RangeStatusIn
+	}
+}
+
+// capitalEfficiency compares the capital a constant-product position needs
+// for liquidity L over [Min, Max] at price, L(2√P - √Min - P/√Max), with the
+// 2L√P needed over the full range.
+func (r PriceRange) capitalEfficiency(price float64) float64 {
+	sqrtP := math.Sqrt(price)
+	concentrated := 2*sqrtP - math.Sqrt(r.Min) - price/math.Sqrt(r.Max)
+	if concentrated <= 0 {
+		return 0
+	}
+	return 2 * sqrtP / concentrated
+}
+
+// PriceRange returns the pool's price range. It reports false for pools
+// without concentrated liquidity or with an invalid range.
+func (p *Pool) PriceRange() (PriceRange, bool) {
+	r := PriceRange{Min: p.PoolConfig.MinPrice, Max: p.PoolConfig.MaxPrice}
+	return r, p.PoolConfig.ConcentratedLiquidity && r.Valid()
+}
+
+// RangePosition locates the pool's CurrentPrice in its price range. It
+// reports false when PriceRange does or the price is not positive.
+func (p *Pool) RangePosition() (RangePosition, bool) {
+	r, ok := p.PriceRange()
+	if !ok || p.CurrentPrice <= 0 {
+		return RangePosition{}, false
+	}
+	return r.Locate(p.CurrentPrice), true
+}
+
+// RangePosition locates PoolPrice in the pool's price range. It reports false
+// when PoolPrice is missing or not positive, or the range is invalid.
+func (p *PositionsByPool) RangePosition() (RangePosition, bool) {
+	r := PriceRange{Min: p.MinPrice, Max: p.MaxPrice}
+	if !r.Valid() || p.PoolPrice == nil || *p.PoolPrice <= 0 {
+		return RangePosition{}, false
+	}
+	return r.Locate(*p.PoolPrice), true
+}
+
+// RangeTrackOptions are optional parameters for TrackPriceRanges.
+type RangeTrackOptions struct {
+	// Timeframe is the OHLCV candle interval. Default: "1h".
+	Timeframe string
+
+	// Lookback is how far back OHLCV history is fetched. Default: 7 days.
+	Lookback time.Duration
+
+	// Now is the time of the analysis. Default: time.Now().
+	Now time.Time
+
+	// Concurrency is the maximum number of out-of-range pools whose OHLCV
+	// history is fetched at once. Default: 8.
+	Concurrency int
+}
+
+// RangeTrackResult is the range status of a single pool in TrackPriceRanges.
+type RangeTrackResult struct {
+	// Address is the pool address.
+	Address string
+
+	// Name is the pool name.
+	Name string
+
+	// Concentrated reports whether the pool has a valid concentrated range
+	// and a current price. The other fields are only set when it is true.
+	Concentrated bool
+
+	// Position locates the pool's current price in its range.
+	Position RangePosition
+
+	// OutOfRange reports whether the current price is outside the range.
+	OutOfRange bool
+
+	// OutsideSince is the start of the first candle of the trailing run of
+	// candles that traded entirely outside the range. It is the time of the
+	// analysis when the latest candle still touched the range, and zero when
+	// the pool is in range.
+	OutsideSince time.Time
+
+	// OutsideFor is the time from OutsideSince to the time of the analysis.
+	OutsideFor time.Duration
+
+	// AtLeast reports whether every candle in the lookback was outside the
+	// range, so the pool has been outside for at least OutsideFor.
+	// It is false when there was no candle in the lookback.
+	AtLeast bool
+
+	// Err is the error returned when fetching the pool's OHLCV history.
+	Err error
+}
+
+// TrackPriceRanges flags concentrated pools whose current price has left their
+// range and uses GetOHLCV history from api to tell how long they have been
+// outside. It returns one result per pool, in order. OHLCV history is fetched
+// concurrently, and only for pools that are out of range; a failed request
+// sets Err on its pool only. opts may be nil.
+func TrackPriceRanges(ctx context.Context, api API, pools []Pool, opts *RangeTrackOptions) []RangeTrackResult {
+	if opts == nil {
+		opts = &RangeTrackOptions{}
+	}
+	timeframe, lookback, now := opts.Timeframe, opts.Lookback, opts.Now
+	if timeframe == "" {
+		timeframe = "1h"
+	}
+	if lookback <= 0 {
+		lookback = 7 * 24 * time.Hour
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	results := make([]RangeTrackResult, len(pools))
+	var outside []int
+	for i := range pools {
+		r := &results[i]
+		r.Address, r.Name = pools[i].Address, pools[i].Name
+		r.Position, r.Concentrated = pools[i].RangePosition()
+		r.OutOfRange = r.Concentrated && !r.Position.InRange()
+		if r.OutOfRange {
+			outside = append(outside, i)
+		}
+	}
+
+	start, end := now.Add(-lookback).Unix(), now.Unix()
+	batch.Run(ctx, len(outside), opts.Concurrency, func(ctx context.Context, j int) {
+		r := &results[outside[j]]
+		resp, err := api.GetOHLCV(ctx, r.Address, &OHLCVParams{Timeframe: &timeframe, StartTime: &start, EndTime: &end})
+		if err != nil {
+			r.Err = fmt.Errorf("dammv2.TrackPriceRanges: %s: %w", r.Address, err)
+			return
+		}
+		r.trackOutside(resp.Data, now)
+	})
+
+	return results
+}
+
+// trackOutside walks candles back from the latest and finds where the
+// trailing run of candles outside the range, on the same side as the current
+// price, began.
+func (r *RangeTrackResult) trackOutside(candles []OHLCV, now time.Time) {
+	sorted := append([]OHLCV(nil), candles...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp > sorted[j].Timestamp })
+
+	since := now.Unix()
+	r.AtLeast = len(sorted) > 0
+	for _, c := range sorted {
+		if r.Position.Range.status(c.Low, c.High) != r.Position.Status {
+			r.AtLeast = false
+			break
+		}
+		since = c.Timestamp
+	}
+	r.OutsideSince = time.Unix(since, 0)
+	r.OutsideFor = now.Sub(r.OutsideSince)
+}
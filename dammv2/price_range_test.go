@@ -0,0 +1,169 @@
+package dammv2_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/meteoratest"
+)
+
+type PriceRangeTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestPriceRange(t *testing.T) {
+	suite.Run(t, new(PriceRangeTestSuite))
+}
+
+func (s *PriceRangeTestSuite) SetupTest() {
+	s.now = time.Unix(1_700_000_000, 0)
+}
+
+func concentratedPool(address string, price float64) dammv2.Pool {
+	return dammv2.Pool{
+		Address:      address,
+		CurrentPrice: price,
+		PoolConfig:   dammv2.PoolConfig{ConcentratedLiquidity: true, MinPrice: 1, MaxPrice: 16},
+	}
+}
+
+func (s *PriceRangeTestSuite) TestLocate() {
+	tests := []struct {
+		name           string
+		price          float64
+		wantStatus     dammv2.RangeStatus
+		wantToMin      float64
+		wantToMax      float64
+		wantEfficiency float64
+	}{
+		{
+			name:           "should locate a price in the middle of the range",
+			price:          4,
+			wantStatus:     dammv2.RangeStatusIn,
+			wantToMin:      75,
+			wantToMax:      300,
+			wantEfficiency: 2,
+		},
+		{
+			name:       "should locate a price below the range",
+			price:      0.5,
+			wantStatus: dammv2.RangeStatusBelow,
+			wantToMin:  -100,
+			wantToMax:  3100,
+		},
+		{
+			name:       "should locate a price above the range",
+			price:      32,
+			wantStatus: dammv2.RangeStatusAbove,
+			wantToMin:  96.875,
+			wantToMax:  -50,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Arrange
+			pool := concentratedPool("pool1", tt.price)
+
+			// Act
+			p, ok := pool.RangePosition()
+
+			// Assert
+			s.Require().True(ok)
+			s.Equal(tt.wantStatus, p.Status)
+			s.InDelta(tt.wantToMin, p.DistanceToMin, 1e-9)
+			s.InDelta(tt.wantToMax, p.DistanceToMax, 1e-9)
+			s.InDelta(tt.wantEfficiency, p.CapitalEfficiency, 1e-9)
+		})
+	}
+}
+
+func (s *PriceRangeTestSuite) TestRangePositionUnavailable() {
+	// Arrange
+	fullRange := dammv2.Pool{CurrentPrice: 4, PoolConfig: dammv2.PoolConfig{MinPrice: 1, MaxPrice: 16}}
+	noPrice := dammv2.PositionsByPool{MinPrice: 1, MaxPrice: 16}
+	price := 4.0
+	withPrice := dammv2.PositionsByPool{MinPrice: 1, MaxPrice: 16, PoolPrice: &price}
+
+	// Act
+	_, fullRangeOK := fullRange.RangePosition()
+	_, noPriceOK := noPrice.RangePosition()
+	p, withPriceOK := withPrice.RangePosition()
+
+	// Assert
+	s.False(fullRangeOK)
+	s.False(noPriceOK)
+	s.True(withPriceOK)
+	s.True(p.InRange())
+}
+
+func (s *PriceRangeTestSuite) TestTrackPriceRanges() {
+	// Arrange
+	hour := int64(3600)
+	now := s.now.Unix()
+	pools := []dammv2.Pool{
+		concentratedPool("in", 4),
+		concentratedPool("below", 0.5),
+		concentratedPool("above", 32),
+		{Address: "full-range", CurrentPrice: 4},
+	}
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.Seed(func(d *meteoratest.Data) {
+		d.DAMMv2.Pools = pools
+		d.DAMMv2.OHLCV["below"] = []dammv2.OHLCV{
+			{Timestamp: now - hour, Low: 0.5, High: 0.9},
+			{Timestamp: now - 3*hour, Low: 0.9, High: 1.2},
+			{Timestamp: now - 2*hour, Low: 0.7, High: 0.95},
+		}
+		d.DAMMv2.OHLCV["above"] = []dammv2.OHLCV{
+			{Timestamp: now - 2*hour, Low: 20, High: 30},
+			{Timestamp: now - hour, Low: 20, High: 30},
+		}
+	})
+	client := srv.Client().DAMMv2
+
+	// Act
+	results := dammv2.TrackPriceRanges(context.Background(), client, pools, &dammv2.RangeTrackOptions{Now: s.now})
+
+	// Assert
+	s.Require().Len(results, 4)
+	s.Require().NoError(results[1].Err)
+	s.False(results[0].OutOfRange)
+	s.True(results[0].OutsideSince.IsZero())
+
+	s.True(results[1].OutOfRange)
+	s.Equal(dammv2.RangeStatusBelow, results[1].Position.Status)
+	s.Equal(time.Unix(now-2*hour, 0), results[1].OutsideSince)
+	s.Equal(2*time.Hour, results[1].OutsideFor)
+	s.False(results[1].AtLeast)
+
+	s.Equal(2*time.Hour, results[2].OutsideFor)
+	s.True(results[2].AtLeast)
+
+	s.False(results[3].Concentrated)
+	s.False(results[3].OutOfRange)
+
+	s.Len(srv.Requests(), 2)
+	s.Equal("1h", srv.Requests()[0].Query.Get("timeframe"))
+}
+
+func (s *PriceRangeTestSuite) TestTrackPriceRangesError() {
+	// Arrange
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.InjectFault(meteoratest.Fault{Path: meteoratest.DAMMv2Prefix, Status: http.StatusBadRequest})
+	client := srv.Client().DAMMv2
+
+	// Act
+	results := dammv2.TrackPriceRanges(context.Background(), client, []dammv2.Pool{concentratedPool("below", 0.5)}, &dammv2.RangeTrackOptions{Now: s.now})
+
+	// Assert
+	s.ErrorContains(results[0].Err, "dammv2.TrackPriceRanges: below")
+	s.True(results[0].OutOfRange)
+}
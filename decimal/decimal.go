@@ -0,0 +1,200 @@
+// Package decimal provides an exact decimal number type for the string-encoded
+// amounts returned by the Meteora APIs.
+//
+// Many Meteora endpoints return token amounts, USD values and prices as JSON
+// strings to preserve precision. Decimal parses these strings without the
+// rounding introduced by float64 and supports exact addition, subtraction,
+// multiplication and division.
+//
+//	amount, err := decimal.Parse("1234.567890123456789")
+//	if err != nil {
+//	    return err
+//	}
+//	total := amount.Add(decimal.MustParse("0.000000000000000011"))
+//	fmt.Println(total) // 1234.567890123456789011
+//
+// Values are held exactly, but a quotient such as 2/3 has no finite decimal
+// expansion: String and MarshalJSON round it to 36 fractional digits.
+package decimal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// maxScale is the number of fractional digits used to format values whose
+// denominator is not a power of ten. Parsed values never need it.
+const maxScale = 36
+
+var (
+	bigTwo  = big.NewInt(2)
+	bigFive = big.NewInt(5)
+)
+
+// Decimal is an exact decimal number. The zero value is 0.
+type Decimal struct {
+	rat *big.Rat
+}
+
+// Zero is the decimal value 0.
+var Zero = Decimal{}
+
+// Parse parses a decimal string such as "12", "-0.0015" or "1.5e-9".
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsRune(s, '/') {
+		return Decimal{}, fmt.Errorf("decimal: invalid number %q", s)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("decimal: invalid number %q", s)
+	}
+
+	return Decimal{rat: r}, nil
+}
+
+// MustParse is like Parse but panics if s is not a valid decimal.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewFromInt returns the decimal value of i.
+func NewFromInt(i int64) Decimal {
+	return Decimal{rat: new(big.Rat).SetInt64(i)}
+}
+
+func (d Decimal) value() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+	return d.rat
+}
+
+// Add returns d + other.
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Add(d.value(), other.value())}
+}
+
+// Sub returns d - other.
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Sub(d.value(), other.value())}
+}
+
+// Mul returns d * other.
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Mul(d.value(), other.value())}
+}
+
+// Quo returns d / other. It panics if other is 0. The quotient is exact, but
+// String rounds it to 36 fractional digits if it does not terminate.
+func (d Decimal) Quo(other Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Quo(d.value(), other.value())}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{rat: new(big.Rat).Neg(d.value())}
+}
+
+// Cmp compares d and other and returns -1, 0 or +1.
+func (d Decimal) Cmp(other Decimal) int {
+	return d.value().Cmp(other.value())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := d.value().Float64()
+	return f
+}
+
+// Rat returns a copy of d as a big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).Set(d.value())
+}
+
+// String returns the decimal representation of d without exponent. It is
+// exact unless d has no finite decimal expansion, in which case it is rounded
+// to 36 fractional digits.
+func (d Decimal) String() string {
+	r := d.value()
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	return r.FloatString(scale(r.Denom()))
+}
+
+// MarshalJSON encodes d as a JSON string to preserve precision.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes d from a JSON string or number. A JSON null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("decimal: %w", err)
+		}
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// scale returns the number of fractional digits needed to print a fraction with
+// the given denominator exactly, or maxScale if it has no finite decimal expansion.
+func scale(denom *big.Int) int {
+	n := new(big.Int).Set(denom)
+	var twos, fives int
+	mod := new(big.Int)
+	for {
+		q, m := new(big.Int).QuoRem(n, bigTwo, mod)
+		if m.Sign() != 0 {
+			break
+		}
+		n = q
+		twos++
+	}
+	for {
+		q, m := new(big.Int).QuoRem(n, bigFive, mod)
+		if m.Sign() != 0 {
+			break
+		}
+		n = q
+		fives++
+	}
+	if n.Cmp(big.NewInt(1)) != 0 {
+		return maxScale
+	}
+	if twos > fives {
+		return twos
+	}
+	return fives
+}
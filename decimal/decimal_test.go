@@ -0,0 +1,101 @@
+package decimal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/decimal"
+)
+
+type DecimalTestSuite struct {
+	suite.Suite
+}
+
+func TestDecimal(t *testing.T) {
+	suite.Run(t, new(DecimalTestSuite))
+}
+
+func (s *DecimalTestSuite) TestParse() {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "should parse integer", input: "42", want: "42"},
+		{name: "should parse negative fraction", input: "-0.0015", want: "-0.0015"},
+		{name: "should keep full precision", input: "123456789.123456789123456789", want: "123456789.123456789123456789"},
+		{name: "should parse exponent", input: "1.5e-9", want: "0.0000000015"},
+		{name: "should trim surrounding spaces", input: " 7.25 ", want: "7.25"},
+		{name: "should reject empty string", input: "", wantErr: true},
+		{name: "should reject fraction syntax", input: "1/3", wantErr: true},
+		{name: "should reject garbage", input: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			d, err := decimal.Parse(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				s.Error(err)
+			} else {
+				s.NoError(err)
+				s.Equal(tt.want, d.String())
+			}
+		})
+	}
+}
+
+func (s *DecimalTestSuite) TestArithmetic() {
+	// Arrange
+	a := decimal.MustParse("0.1")
+	b := decimal.MustParse("0.2")
+
+	// Act & Assert
+	s.Equal("0.3", a.Add(b).String())
+	s.Equal("-0.1", a.Sub(b).String())
+	s.Equal("0.02", a.Mul(b).String())
+	s.Equal("0.5", a.Quo(b).String())
+	s.Equal("0.666666666666666666666666666666666667", b.Quo(decimal.MustParse("0.3")).String())
+	s.Equal("1234.567890123456789011", decimal.MustParse("1234.567890123456789").Add(decimal.MustParse("0.000000000000000011")).String())
+	s.Equal("-0.1", a.Neg().String())
+	s.Equal(-1, a.Cmp(b))
+	s.Equal(1, a.Sign())
+	s.True(decimal.Zero.IsZero())
+	s.Equal("0", decimal.Zero.String())
+	s.InDelta(0.3, a.Add(b).Float64(), 1e-15)
+}
+
+func (s *DecimalTestSuite) TestJSON() {
+	// Arrange
+	var v struct {
+		A decimal.Decimal `json:"a"`
+		B decimal.Decimal `json:"b"`
+		C decimal.Decimal `json:"c"`
+	}
+
+	// Act
+	err := json.Unmarshal([]byte(`{"a":"1.25","b":3.5,"c":null}`), &v)
+
+	// Assert
+	s.NoError(err)
+	s.Equal("1.25", v.A.String())
+	s.Equal("3.5", v.B.String())
+	s.True(v.C.IsZero())
+
+	// Act
+	out, err := json.Marshal(v)
+
+	// Assert
+	s.NoError(err)
+	s.JSONEq(`{"a":"1.25","b":"3.5","c":"0"}`, string(out))
+
+	// Act
+	err = json.Unmarshal([]byte(`{"a":"x"}`), &v)
+
+	// Assert
+	s.Error(err)
+}
@@ -0,0 +1,65 @@
+package dlmm
+
+import "context"
+
+// API is the set of DLMM requests implemented by Client. Code that depends
+// on API instead of *Client can substitute a fake, such as the ones in the
+// meteoratest package.
+type API interface {
+	// ListPools returns a paginated list of pools.
+	ListPools(ctx context.Context, params *ListPoolsParams) (*PaginatedResponse[Pool], error)
+
+	// ListGroups returns a paginated list of pool groups.
+	ListGroups(ctx context.Context, params *ListGroupsParams) (*PaginatedResponse[PoolGroup], error)
+
+	// GetGroup returns a paginated list of pools that belong to a specific pool
+	// group.
+	GetGroup(ctx context.Context, lexicalOrderMints string, params *GetGroupParams) (*PaginatedResponse[Pool], error)
+
+	// GetPool returns metadata and current state for a single pool.
+	GetPool(ctx context.Context, address string) (*Pool, error)
+
+	// GetOHLCV returns OHLCV candles for a single pool over a time range.
+	GetOHLCV(ctx context.Context, address string, params *OHLCVParams) (*OHLCVResponse, error)
+
+	// GetVolumeHistory returns historical volume for a pool aggregated into time
+	// buckets.
+	GetVolumeHistory(ctx context.Context, address string, params *VolumeHistoryParams) (*VolumeHistoryResponse, error)
+
+	// GetProtocolMetrics returns aggregated protocol-level metrics across all pools.
+	GetProtocolMetrics(ctx context.Context) (*ProtocolMetrics, error)
+
+	// GetClosedPositions returns a paginated list of closed positions for a given
+	// wallet.
+	GetClosedPositions(ctx context.Context, wallet string, params *GetClosedPositionsParams) (*ClosedPositionsCursorResponse, error)
+
+	// GetOpenPositions returns all open positions grouped by pool for a given
+	// wallet.
+	GetOpenPositions(ctx context.Context, wallet string, params *GetOpenPositionsParams) (*OpenPositionsResponse, error)
+
+	// GetPositionHistoricalEvents returns the historical events for a position.
+	GetPositionHistoricalEvents(ctx context.Context, address string, params *GetPositionHistoricalEventsParams) (*GetPositionHistoricalEventsResponse, error)
+
+	// GetPositionTotalClaimFees returns the total claim fees for a position.
+	GetPositionTotalClaimFees(ctx context.Context, address string) ([]PositionTotalClaimFees, error)
+
+	// GetPoolPositionPnL returns positions for a specific pool and user with
+	// calculated PnL values.
+	GetPoolPositionPnL(ctx context.Context, poolAddress string, params *GetPoolPositionPnLParams) (*GetPoolPositionPnLResponse, error)
+
+	// GetPortfolio returns the user's portfolio with pool metadata and aggregated
+	// PnL.
+	GetPortfolio(ctx context.Context, params *GetPortfolioParams) (*GetPortfolioResponse, error)
+
+	// GetOpenPortfolio returns the user's open portfolio with pool metadata,
+	// balances, and total metrics.
+	GetOpenPortfolio(ctx context.Context, params *GetOpenPortfolioParams) (*GetOpenPortfolioResponse, error)
+
+	// GetPortfolioTotal returns the all-time total PnL across all user's pools.
+	GetPortfolioTotal(ctx context.Context, user string) (*PortfolioTotalResponse, error)
+
+	// GetPools fetches multiple pools concurrently with GetPool.
+	GetPools(ctx context.Context, addresses []string, opts *BatchOptions) []PoolResult
+}
+
+var _ API = (*Client)(nil)
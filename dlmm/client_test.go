@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -912,3 +914,37 @@ func (s *DLMMClientTestSuite) TestGetPortfolioTotal() {
 		})
 	}
 }
+
+func (s *DLMMClientTestSuite) TestGetPools() {
+	// Arrange
+	var mu sync.Mutex
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		address := strings.TrimPrefix(r.URL.Path, "/pools/")
+		mu.Lock()
+		calls[address]++
+		mu.Unlock()
+		if address == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"pool not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(dlmm.Pool{Address: address})
+	}))
+	defer server.Close()
+	client := dlmm.NewClient(httpclient.New(server.URL, nil))
+
+	// Act
+	results := client.GetPools(context.Background(), []string{"pool1", "missing", "pool2", "pool1"}, &dlmm.BatchOptions{Concurrency: 2})
+
+	// Assert
+	s.Require().Len(results, 3)
+	s.Equal("pool1", results[0].Address)
+	s.NoError(results[0].Err)
+	s.Equal("pool1", results[0].Pool.Address)
+	s.Equal("missing", results[1].Address)
+	s.Error(results[1].Err)
+	s.Nil(results[1].Pool)
+	s.Equal("pool2", results[2].Pool.Address)
+	s.Equal(map[string]int{"pool1": 1, "missing": 1, "pool2": 1}, calls)
+}
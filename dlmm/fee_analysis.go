@@ -0,0 +1,273 @@
+package dlmm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ua1984/meteora-go/internal/batch"
+)
+
+// maxFeeTolerance is how close, as a fraction of MaxFeePct, a fee rate must be
+// to MaxFeePct to count as at the maximum fee.
+const maxFeeTolerance = 0.01
+
+// FeeBucket is the fee rate a pool charged during one volume history bucket.
+// Rates are percentages of the volume.
+type FeeBucket struct {
+	// Timestamp is the Unix timestamp in seconds of the bucket start.
+	Timestamp int64 `json:"timestamp"`
+
+	// Volume is the trading volume during the bucket in USD.
+	Volume float64 `json:"volume"`
+
+	// Fees is the trading fees collected during the bucket in USD.
+	Fees float64 `json:"fees"`
+
+	// ProtocolFees is the protocol's share of Fees in USD.
+	ProtocolFees float64 `json:"protocol_fees"`
+
+	// FeeRatePct is Fees as a percentage of Volume: the average fee a swap
+	// paid. It is zero when there was no volume.
+	FeeRatePct float64 `json:"fee_rate_pct"`
+
+	// SpreadPct is FeeRatePct minus the pool's BaseFeePct: the average dynamic
+	// fee on top of the base fee.
+	SpreadPct float64 `json:"spread_pct"`
+
+	// HasCandle reports whether an OHLCV candle starts at Timestamp. Volatility
+	// is only set when it is true.
+	HasCandle bool `json:"has_candle"`
+
+	// Volatility is the candle's high-low range as a percentage of its open.
+	Volatility float64 `json:"volatility"`
+
+	// AtMaxFee reports whether FeeRatePct is within 1% of the pool's MaxFeePct.
+	AtMaxFee bool `json:"at_max_fee"`
+}
+
+// FeeAnalysis summarizes the fees a pool charged over its volume history.
+// Rates are percentages of the volume.
+type FeeAnalysis struct {
+	// Address is the pool address.
+	Address string `json:"address"`
+
+	// Name is the pool name.
+	Name string `json:"name"`
+
+	// BinStep is the pool's bin step in basis points.
+	BinStep int `json:"bin_step"`
+
+	// BaseFeePct is the pool's base fee percentage.
+	BaseFeePct float64 `json:"base_fee_pct"`
+
+	// MaxFeePct is the pool's maximum fee percentage.
+	MaxFeePct float64 `json:"max_fee_pct"`
+
+	// CurrentFeePct is the pool's current dynamic fee percentage.
+	CurrentFeePct float64 `json:"current_fee_pct"`
+
+	// AtMaxFeeNow reports whether CurrentFeePct is within 1% of MaxFeePct.
+	AtMaxFeeNow bool `json:"at_max_fee_now"`
+
+	// TVL is the pool's total value locked in USD.
+	TVL float64 `json:"tvl"`
+
+	// Buckets holds one entry per volume history bucket, in the order returned.
+	Buckets []FeeBucket `json:"buckets"`
+
+	// Volume is the total volume over Buckets in USD.
+	Volume float64 `json:"volume"`
+
+	// Fees is the total fees over Buckets in USD.
+	Fees float64 `json:"fees"`
+
+	// ProtocolFees is the total protocol fees over Buckets in USD.
+	ProtocolFees float64 `json:"protocol_fees"`
+
+	// FeeRatePct is Fees as a percentage of Volume: the volume-weighted
+	// average fee rate.
+	FeeRatePct float64 `json:"fee_rate_pct"`
+
+	// SpreadPct is FeeRatePct minus BaseFeePct.
+	SpreadPct float64 `json:"spread_pct"`
+
+	// FeesPerTVL is Fees as a percentage of TVL: what LPs earned, before the
+	// protocol's share, per dollar of liquidity over the period.
+	FeesPerTVL float64 `json:"fees_per_tvl"`
+
+	// VolatilityCorrelation is the Pearson correlation between the fee rate
+	// and the volatility of buckets with volume and a candle. It is zero when
+	// there are fewer than two such buckets or either series is constant.
+	VolatilityCorrelation float64 `json:"volatility_correlation"`
+
+	// CorrelationSamples is the number of buckets VolatilityCorrelation was
+	// computed from.
+	CorrelationSamples int `json:"correlation_samples"`
+
+	// TradedBuckets is the number of buckets with volume.
+	TradedBuckets int `json:"traded_buckets"`
+
+	// AtMaxFeeShare is the percentage of TradedBuckets at the maximum fee.
+	AtMaxFeeShare float64 `json:"at_max_fee_share"`
+
+	// Err is the error returned when fetching the pool's history in
+	// AnalyzeFees.
+	Err error `json:"-"`
+}
+
+// AnalyzeFeeHistory computes the fee rate of each volume history bucket of p,
+// joins it with the OHLCV candle starting at the same timestamp and
+// summarizes the pool's fees over the period. candles may be nil.
+func AnalyzeFeeHistory(p *Pool, history []VolumeHistory, candles []OHLCV) FeeAnalysis {
+	cfg := p.PoolConfig
+	a := FeeAnalysis{
+		Address:       p.Address,
+		Name:          p.Name,
+		BinStep:       cfg.BinStep,
+		BaseFeePct:    cfg.BaseFeePct,
+		MaxFeePct:     cfg.MaxFeePct,
+		CurrentFeePct: p.DynamicFeePct,
+		AtMaxFeeNow:   atMaxFee(p.DynamicFeePct, cfg.MaxFeePct),
+		TVL:           p.TVL,
+		Buckets:       make([]FeeBucket, len(history)),
+	}
+
+	volatility := make(map[int64]float64, len(candles))
+	for _, c := range candles {
+		if c.Open > 0 {
+			volatility[c.Timestamp] = (c.High - c.Low) / c.Open * 100
+		}
+	}
+
+	var rates, vols []float64
+	atMax := 0
+	for i, h := range history {
+		b := FeeBucket{Timestamp: h.Timestamp, Volume: h.Volume, Fees: h.Fees, ProtocolFees: h.ProtocolFees}
+		b.Volatility, b.HasCandle = volatility[h.Timestamp]
+		if h.Volume > 0 {
+			b.FeeRatePct = h.Fees / h.Volume * 100
+			b.AtMaxFee = atMaxFee(b.FeeRatePct, cfg.MaxFeePct)
+			a.TradedBuckets++
+			if b.AtMaxFee {
+				atMax++
+			}
+			if b.HasCandle {
+				rates = append(rates, b.FeeRatePct)
+				vols = append(vols, b.Volatility)
+			}
+		}
+		b.SpreadPct = b.FeeRatePct - cfg.BaseFeePct
+		a.Buckets[i] = b
+
+		a.Volume += h.Volume
+		a.Fees += h.Fees
+		a.ProtocolFees += h.ProtocolFees
+	}
+
+	if a.Volume > 0 {
+		a.FeeRatePct = a.Fees / a.Volume * 100
+	}
+	a.SpreadPct = a.FeeRatePct - cfg.BaseFeePct
+	if a.TVL > 0 {
+		a.FeesPerTVL = a.Fees / a.TVL * 100
+	}
+	if a.TradedBuckets > 0 {
+		a.AtMaxFeeShare = float64(atMax) / float64(a.TradedBuckets) * 100
+	}
+	a.CorrelationSamples = len(rates)
+	a.VolatilityCorrelation = correlation(rates, vols)
+	return a
+}
+
+// FeeAnalysisOptions are optional parameters for AnalyzeFees.
+type FeeAnalysisOptions struct {
+	// Timeframe is the volume history and OHLCV bucket interval.
+	// Default: "1h".
+	Timeframe string
+
+	// Lookback is how far back history is fetched. Default: 7 days.
+	Lookback time.Duration
+
+	// Now is the end of the analyzed period. Default: time.Now().
+	Now time.Time
+
+	// Concurrency is the maximum number of pools analyzed at once. Each pool
+	// makes a volume history request followed by an OHLCV request.
+	// Default: 8.
+	Concurrency int
+}
+
+// AnalyzeFees fetches the volume history and OHLCV candles of each pool from
+// api and analyzes them with AnalyzeFeeHistory. Passing the pools of a group,
+// as returned by GetGroup, compares the fees each bin step and fee tier of a
+// pair earned. It returns one analysis per pool, in order; a failed request
+// sets Err on its pool only. opts may be nil.
+func AnalyzeFees(ctx context.Context, api API, pools []Pool, opts *FeeAnalysisOptions) []FeeAnalysis {
+	if opts == nil {
+		opts = &FeeAnalysisOptions{}
+	}
+	timeframe, lookback, now := opts.Timeframe, opts.Lookback, opts.Now
+	if timeframe == "" {
+		timeframe = "1h"
+	}
+	if lookback <= 0 {
+		lookback = 7 * 24 * time.Hour
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	start, end := now.Add(-lookback).Unix(), now.Unix()
+	window := TimeframeBasedParams{Timeframe: &timeframe, StartTime: &start, EndTime: &end}
+
+	results := make([]FeeAnalysis, len(pools))
+	batch.Run(ctx, len(pools), opts.Concurrency, func(ctx context.Context, i int) {
+		p := &pools[i]
+		history, err := api.GetVolumeHistory(ctx, p.Address, &VolumeHistoryParams{TimeframeBasedParams: window})
+		if err != nil {
+			results[i] = FeeAnalysis{Address: p.Address, Name: p.Name, Err: fmt.Errorf("dlmm.AnalyzeFees: %s: %w", p.Address, err)}
+			return
+		}
+		candles, err := api.GetOHLCV(ctx, p.Address, &OHLCVParams{TimeframeBasedParams: window})
+		if err != nil {
+			results[i] = FeeAnalysis{Address: p.Address, Name: p.Name, Err: fmt.Errorf("dlmm.AnalyzeFees: %s: %w", p.Address, err)}
+			return
+		}
+		results[i] = AnalyzeFeeHistory(p, history.Data, candles.Data)
+	})
+
+	return results
+}
+
+func atMaxFee(feePct, maxFeePct float64) bool {
+	return maxFeePct > 0 && feePct >= maxFeePct*(1-maxFeeTolerance)
+}
+
+// correlation returns the Pearson correlation coefficient of x and y, or zero
+// when it is undefined.
+func correlation(x, y []float64) float64 {
+	n := float64(len(x))
+	if len(x) < 2 {
+		return 0
+	}
+	var meanX, meanY float64
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
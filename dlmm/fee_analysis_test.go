@@ -0,0 +1,123 @@
+package dlmm_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/meteoratest"
+)
+
+type FeeAnalysisTestSuite struct {
+	suite.Suite
+	pool dlmm.Pool
+}
+
+func TestFeeAnalysis(t *testing.T) {
+	suite.Run(t, new(FeeAnalysisTestSuite))
+}
+
+func (s *FeeAnalysisTestSuite) SetupTest() {
+	s.pool = dlmm.Pool{
+		Address:       "pool1",
+		Name:          "SOL-USDC",
+		TVL:           10_000,
+		DynamicFeePct: 2,
+		PoolConfig:    dlmm.PoolConfig{BinStep: 20, BaseFeePct: 0.2, MaxFeePct: 2},
+	}
+}
+
+func (s *FeeAnalysisTestSuite) TestAnalyzeFeeHistory() {
+	// Arrange
+	history := []dlmm.VolumeHistory{
+		{Timestamp: 100, Volume: 1000, Fees: 2, ProtocolFees: 0.1},
+		{Timestamp: 200, Volume: 1000, Fees: 10},
+		{Timestamp: 300, Volume: 1000, Fees: 20},
+		{Timestamp: 400},
+	}
+	candles := []dlmm.OHLCV{
+		{Timestamp: 100, Open: 100, High: 101, Low: 100},
+		{Timestamp: 200, Open: 100, High: 103, Low: 100},
+		{Timestamp: 300, Open: 100, High: 105, Low: 100},
+	}
+
+	// Act
+	a := dlmm.AnalyzeFeeHistory(&s.pool, history, candles)
+
+	// Assert
+	s.Require().Len(a.Buckets, 4)
+	s.InDelta(0.2, a.Buckets[0].FeeRatePct, 1e-9)
+	s.InDelta(0, a.Buckets[0].SpreadPct, 1e-9)
+	s.InDelta(1, a.Buckets[0].Volatility, 1e-9)
+	s.InDelta(0.8, a.Buckets[1].SpreadPct, 1e-9)
+	s.True(a.Buckets[2].AtMaxFee)
+	s.False(a.Buckets[3].HasCandle)
+
+	s.InDelta(3000, a.Volume, 1e-9)
+	s.InDelta(32, a.Fees, 1e-9)
+	s.InDelta(32.0/3000*100, a.FeeRatePct, 1e-9)
+	s.InDelta(32.0/3000*100-0.2, a.SpreadPct, 1e-9)
+	s.InDelta(0.32, a.FeesPerTVL, 1e-9)
+	s.Equal(3, a.TradedBuckets)
+	s.Equal(3, a.CorrelationSamples)
+	s.InDelta(100.0/3, a.AtMaxFeeShare, 1e-9)
+	s.InDelta(0.99, a.VolatilityCorrelation, 0.01)
+	s.True(a.AtMaxFeeNow)
+}
+
+func (s *FeeAnalysisTestSuite) TestAnalyzeFeeHistoryWithoutCandles() {
+	// Arrange
+	history := []dlmm.VolumeHistory{{Timestamp: 100, Volume: 1000, Fees: 2}}
+
+	// Act
+	a := dlmm.AnalyzeFeeHistory(&s.pool, history, nil)
+
+	// Assert
+	s.Zero(a.CorrelationSamples)
+	s.Zero(a.VolatilityCorrelation)
+	s.Zero(a.AtMaxFeeShare)
+}
+
+func (s *FeeAnalysisTestSuite) TestAnalyzeFees() {
+	// Arrange
+	now := time.Unix(1_700_000_000, 0)
+	ts := now.Add(-time.Hour).Unix()
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.Seed(func(d *meteoratest.Data) {
+		d.DLMM.Pools = []dlmm.Pool{s.pool}
+		d.DLMM.VolumeHistory["pool1"] = []dlmm.VolumeHistory{{Timestamp: ts, Volume: 1000, Fees: 5}}
+		d.DLMM.OHLCV["pool1"] = []dlmm.OHLCV{{Timestamp: ts, Open: 100, High: 102, Low: 99}}
+	})
+	client := srv.Client().DLMM
+
+	// Act
+	results := dlmm.AnalyzeFees(context.Background(), client, []dlmm.Pool{s.pool}, &dlmm.FeeAnalysisOptions{Now: now, Lookback: 24 * time.Hour})
+
+	// Assert
+	s.Require().Len(results, 1)
+	s.Require().NoError(results[0].Err)
+	s.InDelta(0.5, results[0].FeeRatePct, 1e-9)
+	s.Require().Len(results[0].Buckets, 1)
+	s.InDelta(3, results[0].Buckets[0].Volatility, 1e-9)
+	s.Len(srv.Requests(), 2)
+	s.Equal("1h", srv.Requests()[0].Query.Get("timeframe"))
+}
+
+func (s *FeeAnalysisTestSuite) TestAnalyzeFeesError() {
+	// Arrange
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.InjectFault(meteoratest.Fault{Path: meteoratest.DLMMPrefix, Status: http.StatusBadRequest})
+	client := srv.Client().DLMM
+
+	// Act
+	results := dlmm.AnalyzeFees(context.Background(), client, []dlmm.Pool{s.pool}, nil)
+
+	// Assert
+	s.ErrorContains(results[0].Err, "dlmm.AnalyzeFees: pool1")
+	s.Equal("SOL-USDC", results[0].Name)
+}
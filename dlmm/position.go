@@ -4,10 +4,10 @@ package dlmm
 type PositionEventType string
 
 const (
-	PositionEventTypeAdd          PositionEventType = "add"
-	PositionEventTypeRemove       PositionEventType = "remove"
-	PositionEventTypeClaimFee     PositionEventType = "claim_fee"
-	PositionEventTypeClaimReward  PositionEventType = "claim_reward"
+	PositionEventTypeAdd         PositionEventType = "add"
+	PositionEventTypeRemove      PositionEventType = "remove"
+	PositionEventTypeClaimFee    PositionEventType = "claim_fee"
+	PositionEventTypeClaimReward PositionEventType = "claim_reward"
 )
 
 // PositionEventOrderDirection represents the sort order for position events.
@@ -0,0 +1,218 @@
+package dlmm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ua1984/meteora-go/decimal"
+)
+
+// base58Alphabet is the Bitcoin base58 alphabet used by Solana.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// signatureLength is the length in bytes of a decoded Solana transaction signature.
+const signatureLength = 64
+
+// ErrUnknownPositionEventType is returned when a position event has an event type
+// that is not one of the PositionEventType constants.
+var ErrUnknownPositionEventType = errors.New("unknown position event type")
+
+// ErrInvalidSignature is returned when a transaction signature is not a base58
+// encoded 64-byte value.
+var ErrInvalidSignature = errors.New("invalid transaction signature")
+
+// IsValid reports whether t is one of the known position event types.
+func (t PositionEventType) IsValid() bool {
+	switch t {
+	case PositionEventTypeAdd, PositionEventTypeRemove, PositionEventTypeClaimFee, PositionEventTypeClaimReward:
+		return true
+	}
+	return false
+}
+
+// Signature is a base58-encoded Solana transaction signature.
+type Signature string
+
+// Validate checks that s is a base58 string decoding to 64 bytes.
+func (s Signature) Validate() error {
+	b, err := decodeBase58(string(s))
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidSignature, string(s), err)
+	}
+	if len(b) != signatureLength {
+		return fmt.Errorf("%w %q: decoded length %d, want %d", ErrInvalidSignature, string(s), len(b), signatureLength)
+	}
+	return nil
+}
+
+// Bytes returns the decoded signature bytes.
+func (s Signature) Bytes() ([]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return decodeBase58(string(s))
+}
+
+// String returns the base58 encoded signature.
+func (s Signature) String() string {
+	return string(s)
+}
+
+// ParsedPositionEvent is a typed view of a PositionEvent with a validated event
+// type and signature, exact decimal amounts and parsed timestamps.
+type ParsedPositionEvent struct {
+	// Signature is the transaction signature of the event.
+	Signature Signature
+
+	// IxIndex is the index of the instruction within the transaction.
+	IxIndex int64
+
+	// EventType is the type of the event.
+	EventType PositionEventType
+
+	// PositionAddress is the position the event belongs to.
+	PositionAddress string
+
+	// PoolAddress is the pool the position belongs to.
+	PoolAddress string
+
+	// UserAddress is the owner of the position.
+	UserAddress string
+
+	// BlockTime is the block time of the transaction.
+	BlockTime time.Time
+
+	// Slot is the slot of the transaction.
+	Slot int64
+
+	// TokenX is the mint address of token X.
+	TokenX string
+
+	// TokenY is the mint address of token Y.
+	TokenY string
+
+	// AmountX is the amount of token X in token units.
+	AmountX decimal.Decimal
+
+	// AmountXUSD is the USD value of AmountX at the time of the event.
+	AmountXUSD decimal.Decimal
+
+	// AmountY is the amount of token Y in token units.
+	AmountY decimal.Decimal
+
+	// AmountYUSD is the USD value of AmountY at the time of the event.
+	AmountYUSD decimal.Decimal
+
+	// TotalUSD is the total USD value of the event.
+	TotalUSD decimal.Decimal
+
+	// CreatedAt is the time the event was indexed. Zero if not reported.
+	CreatedAt time.Time
+}
+
+// Parse returns the typed view of the event. It returns an error wrapping
+// ErrUnknownPositionEventType for unknown event types, ErrInvalidSignature for
+// malformed signatures, and an error for amounts or timestamps that cannot be parsed.
+func (e PositionEvent) Parse() (ParsedPositionEvent, error) {
+	eventType := PositionEventType(e.EventType)
+	if !eventType.IsValid() {
+		return ParsedPositionEvent{}, fmt.Errorf("dlmm: event %s: %w %q", e.Signature, ErrUnknownPositionEventType, e.EventType)
+	}
+
+	sig := Signature(e.Signature)
+	if err := sig.Validate(); err != nil {
+		return ParsedPositionEvent{}, fmt.Errorf("dlmm: %w", err)
+	}
+
+	parsed := ParsedPositionEvent{
+		Signature:       sig,
+		IxIndex:         e.IxIndex,
+		EventType:       eventType,
+		PositionAddress: e.PositionAddress,
+		PoolAddress:     e.PoolAddress,
+		UserAddress:     e.UserAddress,
+		BlockTime:       time.Unix(e.BlockTime, 0).UTC(),
+		Slot:            e.Slot,
+		TokenX:          e.TokenX,
+		TokenY:          e.TokenY,
+	}
+
+	amounts := []struct {
+		name  string
+		value string
+		dst   *decimal.Decimal
+	}{
+		{"amountX", e.AmountX, &parsed.AmountX},
+		{"amountXUsd", e.AmountXUsd, &parsed.AmountXUSD},
+		{"amountY", e.AmountY, &parsed.AmountY},
+		{"amountYUsd", e.AmountYUsd, &parsed.AmountYUSD},
+		{"totalUsd", e.TotalUsd, &parsed.TotalUSD},
+	}
+	for _, a := range amounts {
+		if a.value == "" {
+			continue
+		}
+		d, err := decimal.Parse(a.value)
+		if err != nil {
+			return ParsedPositionEvent{}, fmt.Errorf("dlmm: event %s: %s: %w", e.Signature, a.name, err)
+		}
+		*a.dst = d
+	}
+
+	if e.CreatedAt != "" {
+		t, err := time.Parse(time.RFC3339, e.CreatedAt)
+		if err != nil {
+			return ParsedPositionEvent{}, fmt.Errorf("dlmm: event %s: createdAt: %w", e.Signature, err)
+		}
+		parsed.CreatedAt = t
+	}
+
+	return parsed, nil
+}
+
+// ParseEvents returns the typed view of every event in the response.
+// It stops at the first event that fails to parse.
+func (r *GetPositionHistoricalEventsResponse) ParseEvents() ([]ParsedPositionEvent, error) {
+	events := make([]ParsedPositionEvent, 0, len(r.Events))
+	for _, e := range r.Events {
+		parsed, err := e.Parse()
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, parsed)
+	}
+	return events, nil
+}
+
+// decodeBase58 decodes a base58 string, preserving leading zero bytes.
+func decodeBase58(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty string")
+	}
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i, c := range s {
+		idx := -1
+		for j, a := range base58Alphabet {
+			if a == c {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q at position %d", c, i)
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(idx)))
+	}
+
+	var leadingZeros int
+	for leadingZeros < len(s) && s[leadingZeros] == base58Alphabet[0] {
+		leadingZeros++
+	}
+
+	return append(make([]byte, leadingZeros), n.Bytes()...), nil
+}
@@ -0,0 +1,139 @@
+package dlmm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/decimal"
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+const validSignature = "4HxKByB8vHF8pGdUzn6Bhf2959NQC5abfGSF13o8Ndv39kQMkqmcC1C6byRJVV5d8w2rLvwCZmdfj8Qr1tk3LvYq"
+
+type PositionEventTestSuite struct {
+	suite.Suite
+}
+
+func TestPositionEvent(t *testing.T) {
+	suite.Run(t, new(PositionEventTestSuite))
+}
+
+func (s *PositionEventTestSuite) TestParse() {
+	valid := dlmm.PositionEvent{
+		Signature:       validSignature,
+		IxIndex:         2,
+		EventType:       string(dlmm.PositionEventTypeClaimFee),
+		PositionAddress: "pos1",
+		PoolAddress:     "pool1",
+		UserAddress:     "user1",
+		BlockTime:       1705320000,
+		Slot:            42,
+		TokenX:          "mintX",
+		TokenY:          "mintY",
+		AmountX:         "0.123456789012345678",
+		AmountXUsd:      "12.5",
+		AmountY:         "3",
+		AmountYUsd:      "3.0001",
+		TotalUsd:        "15.5001",
+		CreatedAt:       "2024-01-15T12:00:00Z",
+	}
+
+	tests := []struct {
+		name       string
+		mutate     func(e *dlmm.PositionEvent)
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:   "should parse a valid event",
+			mutate: func(e *dlmm.PositionEvent) {},
+		},
+		{
+			name:    "should reject unknown event type",
+			mutate:  func(e *dlmm.PositionEvent) { e.EventType = "swap" },
+			wantErr: dlmm.ErrUnknownPositionEventType,
+		},
+		{
+			name:    "should reject signature with invalid base58 characters",
+			mutate:  func(e *dlmm.PositionEvent) { e.Signature = "0OIl" },
+			wantErr: dlmm.ErrInvalidSignature,
+		},
+		{
+			name:    "should reject signature with wrong length",
+			mutate:  func(e *dlmm.PositionEvent) { e.Signature = "3yZe7d" },
+			wantErr: dlmm.ErrInvalidSignature,
+		},
+		{
+			name:       "should reject malformed amount",
+			mutate:     func(e *dlmm.PositionEvent) { e.AmountYUsd = "1,5" },
+			wantAnyErr: true,
+		},
+		{
+			name:       "should reject malformed createdAt",
+			mutate:     func(e *dlmm.PositionEvent) { e.CreatedAt = "yesterday" },
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Arrange
+			ev := valid
+			tt.mutate(&ev)
+
+			// Act
+			parsed, err := ev.Parse()
+
+			// Assert
+			switch {
+			case tt.wantErr != nil:
+				s.True(errors.Is(err, tt.wantErr), "got %v", err)
+			case tt.wantAnyErr:
+				s.Error(err)
+			default:
+				s.Require().NoError(err)
+				s.Equal(dlmm.Signature(validSignature), parsed.Signature)
+				s.Equal(dlmm.PositionEventTypeClaimFee, parsed.EventType)
+				s.Equal(time.Unix(1705320000, 0).UTC(), parsed.BlockTime)
+				s.Equal(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), parsed.CreatedAt)
+				s.Equal("0.123456789012345678", parsed.AmountX.String())
+				s.Equal(0, parsed.TotalUSD.Cmp(parsed.AmountXUSD.Add(parsed.AmountYUSD)))
+				s.True(parsed.AmountY.Cmp(decimal.NewFromInt(3)) == 0)
+			}
+		})
+	}
+}
+
+func (s *PositionEventTestSuite) TestParseEvents() {
+	// Arrange
+	resp := &dlmm.GetPositionHistoricalEventsResponse{Events: []dlmm.PositionEvent{
+		{Signature: validSignature, EventType: string(dlmm.PositionEventTypeAdd), AmountX: "1"},
+		{Signature: validSignature, EventType: "unknown"},
+	}}
+
+	// Act
+	events, err := resp.ParseEvents()
+
+	// Assert
+	s.ErrorIs(err, dlmm.ErrUnknownPositionEventType)
+	s.Nil(events)
+
+	// Act
+	resp.Events = resp.Events[:1]
+	events, err = resp.ParseEvents()
+
+	// Assert
+	s.NoError(err)
+	s.Len(events, 1)
+}
+
+func (s *PositionEventTestSuite) TestSignatureBytes() {
+	// Act
+	b, err := dlmm.Signature(validSignature).Bytes()
+
+	// Assert
+	s.NoError(err)
+	s.Len(b, 64)
+}
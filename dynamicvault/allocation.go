@@ -0,0 +1,220 @@
+package dynamicvault
+
+import (
+	"context"
+	"fmt"
+)
+
+// APYHorizon selects one of the time horizons of an APYState.
+type APYHorizon string
+
+const (
+	// APYClosest selects the short-term APY.
+	APYClosest APYHorizon = "closest"
+
+	// APYAverage selects the medium-term average APY.
+	APYAverage APYHorizon = "average"
+
+	// APYLong selects the long-term APY.
+	APYLong APYHorizon = "long"
+)
+
+// Breakdown returns the per-strategy APYs of horizon, or nil for an unknown
+// horizon. An empty horizon selects APYClosest.
+func (s *APYState) Breakdown(h APYHorizon) []APYBreakdown {
+	switch h {
+	case APYClosest, "":
+		return s.ClosestAPY
+	case APYAverage:
+		return s.AverageAPY
+	case APYLong:
+		return s.LongAPY
+	default:
+		return nil
+	}
+}
+
+// valid reports whether h is one of the APYHorizon constants or empty.
+func (h APYHorizon) valid() bool {
+	switch h {
+	case APYClosest, APYAverage, APYLong, "":
+		return true
+	}
+	return false
+}
+
+// StrategyAllocation is the share of a vault's funds allocated to one
+// strategy.
+type StrategyAllocation struct {
+	// Strategy is the strategy as reported by the vault.
+	Strategy Strategy `json:"strategy"`
+
+	// Share is the percentage of the vault's total amount allocated to the
+	// strategy.
+	Share float64 `json:"share"`
+
+	// Capped reports whether the strategy has a MaxAllocation. A
+	// MaxAllocation of zero is treated as no limit.
+	Capped bool `json:"capped"`
+
+	// Headroom is the amount in native units that can still be allocated to
+	// the strategy before it reaches MaxAllocation. For uncapped strategies
+	// it is the vault amount not yet allocated to the strategy. Disabled
+	// strategies have no headroom.
+	Headroom int64 `json:"headroom"`
+
+	// HeadroomShare is Headroom as a percentage of the vault's total amount.
+	HeadroomShare float64 `json:"headroom_share"`
+
+	// OverAllocated reports whether Share exceeds MaxAllocation.
+	OverAllocated bool `json:"over_allocated"`
+
+	// APY is the strategy APY for the selected horizon.
+	APY float64 `json:"apy"`
+
+	// HasAPY reports whether the APY state listed the strategy. Strategies
+	// without an APY count as earning nothing in the blended APY.
+	HasAPY bool `json:"has_apy"`
+
+	// DisabledWithLiquidity reports whether the strategy is disabled but
+	// still holds liquidity that has not been withdrawn.
+	DisabledWithLiquidity bool `json:"disabled_with_liquidity"`
+}
+
+// Allocation describes how a vault's funds are spread across its strategies.
+type Allocation struct {
+	// Vault is the vault address.
+	Vault string `json:"vault"`
+
+	// Symbol is the symbol of the vault token.
+	Symbol string `json:"symbol"`
+
+	// TokenAddress is the mint of the vault token.
+	TokenAddress string `json:"token_address"`
+
+	// Horizon is the APY horizon used for APY and BlendedAPY.
+	Horizon APYHorizon `json:"horizon"`
+
+	// TotalAmount is the vault's total amount in native units. Shares are
+	// relative to it.
+	TotalAmount int64 `json:"total_amount"`
+
+	// Idle is the amount held by the vault itself rather than a strategy.
+	Idle int64 `json:"idle"`
+
+	// IdleShare is Idle as a percentage of TotalAmount.
+	IdleShare float64 `json:"idle_share"`
+
+	// Strategies holds one entry per strategy, in the order of the vault.
+	Strategies []StrategyAllocation `json:"strategies"`
+
+	// BlendedAPY is the APY of the whole vault: strategy APYs weighted by
+	// liquidity, with idle funds earning nothing.
+	BlendedAPY float64 `json:"blended_apy"`
+}
+
+// DisabledWithLiquidity returns the strategies that are disabled but still
+// hold liquidity.
+func (a *Allocation) DisabledWithLiquidity() []StrategyAllocation {
+	var out []StrategyAllocation
+	for _, s := range a.Strategies {
+		if s.DisabledWithLiquidity {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Analyze computes the allocation of info across its strategies, using the
+// APYs of horizon from apy. An empty horizon selects APYClosest; an unknown
+// one gives no strategy an APY, as does a nil apy. GetAllocation rejects
+// unknown horizons.
+//
+// Shares are relative to TotalAmount, or to the sum of the strategy
+// liquidity and the idle amount when TotalAmount is zero. MaxAllocation is
+// read as a percentage of the vault's funds.
+func Analyze(info *VaultInfo, apy *APYState, horizon APYHorizon) *Allocation {
+	if horizon == "" {
+		horizon = APYClosest
+	}
+	a := &Allocation{
+		Vault:        info.Pubkey,
+		Symbol:       info.Symbol,
+		TokenAddress: info.TokenAddress,
+		Horizon:      horizon,
+		TotalAmount:  info.TotalAmount,
+		Idle:         info.TokenAmount,
+		Strategies:   make([]StrategyAllocation, len(info.Strategies)),
+	}
+	if a.TotalAmount <= 0 {
+		a.TotalAmount = info.TokenAmount
+		for _, s := range info.Strategies {
+			a.TotalAmount += s.Liquidity
+		}
+	}
+	total := float64(a.TotalAmount)
+	share := func(amount int64) float64 {
+		if total <= 0 {
+			return 0
+		}
+		return float64(amount) / total * 100
+	}
+
+	var breakdown []APYBreakdown
+	if apy != nil {
+		breakdown = apy.Breakdown(horizon)
+	}
+
+	a.IdleShare = share(a.Idle)
+	var weighted float64
+	for i, s := range info.Strategies {
+		sa := StrategyAllocation{
+			Strategy:              s,
+			Share:                 share(s.Liquidity),
+			Capped:                s.MaxAllocation > 0,
+			DisabledWithLiquidity: s.Disabled && s.Liquidity > 0,
+		}
+
+		limit := a.TotalAmount
+		if sa.Capped {
+			limit = int64(s.MaxAllocation / 100 * total)
+			sa.OverAllocated = sa.Share > s.MaxAllocation
+		}
+		if !s.Disabled && limit > s.Liquidity {
+			sa.Headroom = limit - s.Liquidity
+		}
+		sa.HeadroomShare = share(sa.Headroom)
+
+		for _, b := range breakdown {
+			if b.Strategy == s.Pubkey || (b.Strategy == "" && b.StrategyName == s.StrategyName) {
+				sa.APY, sa.HasAPY = b.APY, true
+				break
+			}
+		}
+		weighted += float64(s.Liquidity) * sa.APY
+		a.Strategies[i] = sa
+	}
+	if total > 0 {
+		a.BlendedAPY = weighted / total
+	}
+	return a
+}
+
+// GetAllocation fetches the state and APY state of the vault identified by
+// token mint from api and analyzes its allocation with Analyze. An empty
+// horizon selects APYClosest; an unknown one is an error.
+func GetAllocation(ctx context.Context, api API, tokenMint string, horizon APYHorizon) (*Allocation, error) {
+	if !horizon.valid() {
+		return nil, fmt.Errorf("dynamicvault.GetAllocation: unknown APY horizon %q", horizon)
+	}
+	state, err := api.GetVaultState(ctx, tokenMint)
+	if err != nil {
+		return nil, fmt.Errorf("dynamicvault.GetAllocation: %w", err)
+	}
+	apy, err := api.GetAPYState(ctx, tokenMint)
+	if err != nil {
+		return nil, fmt.Errorf("dynamicvault.GetAllocation: %w", err)
+	}
+
+	return Analyze(state, apy, horizon), nil
+}
@@ -0,0 +1,190 @@
+package dynamicvault_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dynamicvault"
+	"github.com/ua1984/meteora-go/meteoratest"
+)
+
+type AllocationTestSuite struct {
+	suite.Suite
+	vault dynamicvault.VaultInfo
+	apy   dynamicvault.APYState
+}
+
+func TestAllocation(t *testing.T) {
+	suite.Run(t, new(AllocationTestSuite))
+}
+
+func (s *AllocationTestSuite) SetupTest() {
+	s.vault = dynamicvault.VaultInfo{
+		Pubkey:       "vault1",
+		Symbol:       "USDC",
+		TokenAddress: "usdc-mint",
+		TotalAmount:  1000,
+		TokenAmount:  100,
+		Strategies: []dynamicvault.Strategy{
+			{Pubkey: "s1", StrategyName: "Solend", Liquidity: 500, MaxAllocation: 60},
+			{Pubkey: "s2", StrategyName: "Kamino", Liquidity: 300, MaxAllocation: 25},
+			{Pubkey: "s3", StrategyName: "Marginfi", Liquidity: 100},
+			{Pubkey: "s4", StrategyName: "Mango", Liquidity: 0, MaxAllocation: 50, Disabled: true},
+		},
+	}
+	s.apy = dynamicvault.APYState{
+		ClosestAPY: []dynamicvault.APYBreakdown{
+			{Strategy: "s1", StrategyName: "Solend", APY: 10},
+			{Strategy: "s2", StrategyName: "Kamino", APY: 5},
+		},
+		LongAPY: []dynamicvault.APYBreakdown{
+			{StrategyName: "Marginfi", APY: 8},
+		},
+	}
+}
+
+func (s *AllocationTestSuite) TestAnalyze() {
+	// Act
+	a := dynamicvault.Analyze(&s.vault, &s.apy, dynamicvault.APYClosest)
+
+	// Assert
+	s.Equal("vault1", a.Vault)
+	s.Equal(dynamicvault.APYClosest, a.Horizon)
+	s.Equal(10.0, a.IdleShare)
+	s.Require().Len(a.Strategies, 4)
+
+	solend := a.Strategies[0]
+	s.Equal(50.0, solend.Share)
+	s.True(solend.Capped)
+	s.Equal(int64(100), solend.Headroom)
+	s.Equal(10.0, solend.HeadroomShare)
+	s.False(solend.OverAllocated)
+	s.True(solend.HasAPY)
+	s.Equal(10.0, solend.APY)
+
+	kamino := a.Strategies[1]
+	s.Equal(30.0, kamino.Share)
+	s.Zero(kamino.Headroom)
+	s.True(kamino.OverAllocated)
+
+	marginfi := a.Strategies[2]
+	s.False(marginfi.Capped)
+	s.Equal(int64(900), marginfi.Headroom)
+	s.False(marginfi.HasAPY)
+
+	mango := a.Strategies[3]
+	s.Zero(mango.Headroom)
+	s.False(mango.DisabledWithLiquidity)
+
+	s.Equal(6.5, a.BlendedAPY)
+	s.Empty(a.DisabledWithLiquidity())
+}
+
+func (s *AllocationTestSuite) TestAnalyzeHorizons() {
+	tests := []struct {
+		name        string
+		horizon     dynamicvault.APYHorizon
+		wantBlended float64
+	}{
+		{
+			name:        "should match strategies by name when the address is missing",
+			horizon:     dynamicvault.APYLong,
+			wantBlended: 0.8,
+		},
+		{
+			name:        "should treat a horizon without APYs as earning nothing",
+			horizon:     dynamicvault.APYAverage,
+			wantBlended: 0,
+		},
+		{
+			name:        "should default an empty horizon to the closest APY",
+			horizon:     "",
+			wantBlended: 6.5,
+		},
+		{
+			name:        "should treat an unknown horizon as earning nothing",
+			horizon:     "weekly",
+			wantBlended: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			a := dynamicvault.Analyze(&s.vault, &s.apy, tt.horizon)
+
+			// Assert
+			s.InDelta(tt.wantBlended, a.BlendedAPY, 1e-9)
+		})
+	}
+}
+
+func (s *AllocationTestSuite) TestAnalyzeDisabledWithLiquidity() {
+	// Arrange
+	s.vault.Strategies[0].Disabled = true
+
+	// Act
+	a := dynamicvault.Analyze(&s.vault, nil, dynamicvault.APYClosest)
+
+	// Assert
+	flagged := a.DisabledWithLiquidity()
+	s.Require().Len(flagged, 1)
+	s.Equal("s1", flagged[0].Strategy.Pubkey)
+	s.Zero(flagged[0].Headroom)
+	s.Zero(a.BlendedAPY)
+}
+
+func (s *AllocationTestSuite) TestAnalyzeWithoutTotalAmount() {
+	// Arrange
+	s.vault.TotalAmount = 0
+
+	// Act
+	a := dynamicvault.Analyze(&s.vault, nil, dynamicvault.APYClosest)
+
+	// Assert
+	s.Equal(int64(1000), a.TotalAmount)
+	s.Equal(50.0, a.Strategies[0].Share)
+}
+
+func (s *AllocationTestSuite) TestGetAllocation() {
+	// Arrange
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.Seed(func(d *meteoratest.Data) {
+		d.DynamicVault.Vaults = []dynamicvault.VaultInfo{s.vault}
+		d.DynamicVault.APYStates["usdc-mint"] = s.apy
+	})
+	client := srv.Client().DynamicVault
+
+	s.Run("should analyze the fetched vault", func() {
+		// Act
+		a, err := dynamicvault.GetAllocation(context.Background(), client, "usdc-mint", dynamicvault.APYClosest)
+
+		// Assert
+		s.Require().NoError(err)
+		s.Equal(6.5, a.BlendedAPY)
+	})
+
+	s.Run("should reject an unknown horizon", func() {
+		// Act
+		a, err := dynamicvault.GetAllocation(context.Background(), client, "usdc-mint", "weekly")
+
+		// Assert
+		s.EqualError(err, `dynamicvault.GetAllocation: unknown APY horizon "weekly"`)
+		s.Nil(a)
+	})
+
+	s.Run("should return error on API failure", func() {
+		// Arrange
+		srv.InjectFault(meteoratest.Fault{Path: meteoratest.DynamicVaultPrefix, Status: http.StatusBadRequest})
+
+		// Act
+		a, err := dynamicvault.GetAllocation(context.Background(), client, "usdc-mint", dynamicvault.APYClosest)
+
+		// Assert
+		s.ErrorContains(err, "dynamicvault.GetAllocation")
+		s.Nil(a)
+	})
+}
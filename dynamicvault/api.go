@@ -0,0 +1,33 @@
+package dynamicvault
+
+import "context"
+
+// API is the set of Dynamic Vault requests implemented by Client. Code that depends
+// on API instead of *Client can substitute a fake, such as the ones in the
+// meteoratest package.
+type API interface {
+	// ListVaultInfo returns information for all vaults.
+	ListVaultInfo(ctx context.Context) ([]VaultInfo, error)
+
+	// ListVaultAddresses returns addresses for all vaults.
+	ListVaultAddresses(ctx context.Context) ([]VaultAddress, error)
+
+	// GetVaultState returns the current state for a vault identified by token mint.
+	GetVaultState(ctx context.Context, tokenMint string) (*VaultState, error)
+
+	// GetAPYState returns the current APY state for a vault identified by token
+	// mint.
+	GetAPYState(ctx context.Context, tokenMint string) (*APYState, error)
+
+	// GetAPYByTimeRange returns APY entries within a time range for a vault.
+	GetAPYByTimeRange(ctx context.Context, tokenMint string, start, end int64) ([]APYEntry, error)
+
+	// GetVirtualPrice returns virtual price data for a vault and strategy.
+	GetVirtualPrice(ctx context.Context, tokenMint string, strategy string) ([]VirtualPrice, error)
+
+	// GetVaultStates fetches the state of multiple vaults concurrently with
+	// GetVaultState.
+	GetVaultStates(ctx context.Context, tokenMints []string, opts *BatchOptions) []VaultStateResult
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,48 @@
+package dynamicvault
+
+import (
+	"context"
+
+	"github.com/ua1984/meteora-go/internal/batch"
+)
+
+// BatchOptions are optional parameters for batch methods such as GetVaultStates.
+type BatchOptions struct {
+	// Concurrency is the maximum number of requests in flight. Default: 8.
+	// Requests are additionally throttled by the client's rate limit, if set.
+	Concurrency int
+}
+
+// VaultStateResult is the outcome of fetching a single vault state in a batch.
+type VaultStateResult struct {
+	// TokenMint is the requested token mint.
+	TokenMint string
+
+	// State is the fetched vault state, or nil if Err is set.
+	State *VaultState
+
+	// Err is the error returned for this token mint, if any.
+	Err error
+}
+
+// GetVaultStates fetches the state of multiple vaults concurrently with
+// GetVaultState. It returns one result per unique token mint, in order of first
+// appearance. Failures are reported per token mint so that a partial result is
+// always returned.
+func (c *Client) GetVaultStates(ctx context.Context, tokenMints []string, opts *BatchOptions) []VaultStateResult {
+	unique := batch.Unique(tokenMints)
+	results := make([]VaultStateResult, len(unique))
+	batch.Run(ctx, len(unique), opts.concurrency(), func(ctx context.Context, i int) {
+		state, err := c.GetVaultState(ctx, unique[i])
+		results[i] = VaultStateResult{TokenMint: unique[i], State: state, Err: err}
+	})
+
+	return results
+}
+
+func (o *BatchOptions) concurrency() int {
+	if o == nil {
+		return 0
+	}
+	return o.Concurrency
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -312,3 +313,27 @@ func (s *DynamicVaultClientTestSuite) TestGetVirtualPrice() {
 		})
 	}
 }
+
+func (s *DynamicVaultClientTestSuite) TestGetVaultStates() {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mint := strings.TrimPrefix(r.URL.Path, "/vault_state/")
+		if mint == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(dynamicvault.VaultState{TokenAddress: mint})
+	}))
+	defer server.Close()
+	client := dynamicvault.NewClient(httpclient.New(server.URL, nil))
+
+	// Act
+	results := client.GetVaultStates(context.TODO(), []string{"mint1", "mint1", "missing", "mint2"}, nil)
+
+	// Assert
+	s.Require().Len(results, 3)
+	s.Equal("mint1", results[0].State.TokenAddress)
+	s.Equal("missing", results[1].TokenMint)
+	s.Error(results[1].Err)
+	s.Equal("mint2", results[2].State.TokenAddress)
+}
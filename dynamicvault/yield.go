@@ -0,0 +1,167 @@
+package dynamicvault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/ua1984/meteora-go/decimal"
+)
+
+// ErrInsufficientPrices is returned when a virtual price series has fewer than
+// two points within the requested time range.
+var ErrInsufficientPrices = errors.New("fewer than two virtual prices in range")
+
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// LPValue is the value of an amount of vault LP tokens.
+type LPValue struct {
+	// LPAmount is the LP token amount in native units.
+	LPAmount int64 `json:"lp_amount"`
+
+	// Amount is the underlying token amount in native units that the LP tokens
+	// can be withdrawn for, rounded down.
+	Amount int64 `json:"amount"`
+
+	// USD is Amount valued at the vault's USDRate.
+	USD float64 `json:"usd"`
+}
+
+// ValueLP values lpAmount LP tokens of the vault in underlying tokens and in
+// USD. The underlying amount is the LP tokens' share of TotalAmount; decimals
+// is the number of decimals of the underlying token, which the vault does not
+// report. A vault without LP supply is valued at one underlying unit per LP
+// unit.
+func (v *VaultInfo) ValueLP(lpAmount int64, decimals int) LPValue {
+	amount := lpAmount
+	if v.LPSupply > 0 {
+		n := new(big.Int).Mul(big.NewInt(lpAmount), big.NewInt(v.TotalAmount))
+		amount = n.Quo(n, big.NewInt(v.LPSupply)).Int64()
+	}
+	return LPValue{
+		LPAmount: lpAmount,
+		Amount:   amount,
+		USD:      float64(amount) / math.Pow10(decimals) * v.USDRate,
+	}
+}
+
+// Yield is the yield realized by vault LPs between two virtual price points.
+type Yield struct {
+	// Start is the first virtual price at or after the requested start.
+	Start VirtualPrice `json:"start"`
+
+	// End is the last virtual price at or before the requested end.
+	End VirtualPrice `json:"end"`
+
+	// Return is the percentage change of the virtual price from Start to End.
+	Return float64 `json:"return"`
+
+	// APY is Return compounded to a yearly percentage.
+	APY float64 `json:"apy"`
+}
+
+// RealizedYield computes the yield between start and end (Unix timestamps)
+// from a virtual price series, which need not be sorted. An end of zero means
+// no upper bound. It returns ErrInsufficientPrices unless at least two points
+// with different timestamps fall within the range.
+func RealizedYield(prices []VirtualPrice, start, end int64) (*Yield, error) {
+	var in []VirtualPrice
+	for _, p := range prices {
+		if p.Timestamp >= start && (end == 0 || p.Timestamp <= end) {
+			in = append(in, p)
+		}
+	}
+	sort.SliceStable(in, func(i, j int) bool { return in[i].Timestamp < in[j].Timestamp })
+	if len(in) < 2 || in[0].Timestamp == in[len(in)-1].Timestamp {
+		return nil, fmt.Errorf("dynamicvault.RealizedYield: %w", ErrInsufficientPrices)
+	}
+
+	y := &Yield{Start: in[0], End: in[len(in)-1]}
+	from, err := decimal.Parse(y.Start.Price)
+	if err != nil {
+		return nil, fmt.Errorf("dynamicvault.RealizedYield: price at %d: %w", y.Start.Timestamp, err)
+	}
+	to, err := decimal.Parse(y.End.Price)
+	if err != nil {
+		return nil, fmt.Errorf("dynamicvault.RealizedYield: price at %d: %w", y.End.Timestamp, err)
+	}
+	if from.Sign() <= 0 {
+		return nil, fmt.Errorf("dynamicvault.RealizedYield: non-positive price %s at %d", y.Start.Price, y.Start.Timestamp)
+	}
+
+	ratio := to.Float64() / from.Float64()
+	elapsed := float64(y.End.Timestamp - y.Start.Timestamp)
+	y.Return = (ratio - 1) * 100
+	y.APY = (math.Pow(ratio, secondsPerYear/elapsed) - 1) * 100
+	return y, nil
+}
+
+// YieldComparison compares the yield realized by LPs with the APY reported
+// for the same period. Both must describe the same scope: the APY reported by
+// GetAPYByTimeRange is that of the whole vault, so the realized yield must
+// come from the virtual price series of the vault aggregate, not of a single
+// lending strategy.
+type YieldComparison struct {
+	// Realized is the yield computed from the virtual price series.
+	Realized Yield `json:"realized"`
+
+	// ReportedAPY is the mean of the reported APY entries between
+	// Realized.Start and Realized.End, in percent.
+	ReportedAPY float64 `json:"reported_apy"`
+
+	// ReportedEntries is the number of APY entries averaged into ReportedAPY.
+	// It is zero when no entry fell within the period.
+	ReportedEntries int `json:"reported_entries"`
+
+	// Divergence is ReportedAPY minus Realized.APY, in percentage points. A
+	// positive divergence means the reported APY overstated what LPs earned.
+	// It is zero when ReportedEntries is zero.
+	Divergence float64 `json:"divergence"`
+}
+
+// CompareYield compares a realized yield with the APY entries reported over
+// the same period, such as those returned by GetAPYByTimeRange. The entries
+// must cover the same scope as the virtual prices realized was computed from.
+func CompareYield(realized *Yield, reported []APYEntry) *YieldComparison {
+	c := &YieldComparison{Realized: *realized}
+	var sum float64
+	for _, e := range reported {
+		if e.Timestamp >= realized.Start.Timestamp && e.Timestamp <= realized.End.Timestamp {
+			sum += e.APY
+			c.ReportedEntries++
+		}
+	}
+	if c.ReportedEntries > 0 {
+		c.ReportedAPY = sum / float64(c.ReportedEntries)
+		c.Divergence = c.ReportedAPY - realized.APY
+	}
+	return c
+}
+
+// GetYieldComparison fetches the virtual price history of strategy and the APY
+// reported for the vault between start and end from api, and compares the
+// realized yield with the reported APY. The reported APY is vault-wide, so
+// strategy must name the series of the vault aggregate, whose virtual price
+// tracks the vault LP token across every strategy and idle funds. The yield
+// of a single lending strategy differs from the vault's by the other
+// strategies and the idle share, and its Divergence would be meaningless;
+// compare it with that strategy's APY from GetAPYState instead.
+func GetYieldComparison(ctx context.Context, api API, tokenMint, strategy string, start, end int64) (*YieldComparison, error) {
+	prices, err := api.GetVirtualPrice(ctx, tokenMint, strategy)
+	if err != nil {
+		return nil, fmt.Errorf("dynamicvault.GetYieldComparison: %w", err)
+	}
+	realized, err := RealizedYield(prices, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("dynamicvault.GetYieldComparison: %w", err)
+	}
+	reported, err := api.GetAPYByTimeRange(ctx, tokenMint, realized.Start.Timestamp, realized.End.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("dynamicvault.GetYieldComparison: %w", err)
+	}
+
+	return CompareYield(realized, reported), nil
+}
@@ -0,0 +1,172 @@
+package dynamicvault_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dynamicvault"
+	"github.com/ua1984/meteora-go/meteoratest"
+)
+
+const year = int64(365 * 24 * 60 * 60)
+
+type YieldTestSuite struct {
+	suite.Suite
+	prices []dynamicvault.VirtualPrice
+}
+
+func TestYield(t *testing.T) {
+	suite.Run(t, new(YieldTestSuite))
+}
+
+func (s *YieldTestSuite) SetupTest() {
+	s.prices = []dynamicvault.VirtualPrice{
+		{Price: "1.1", Timestamp: year},
+		{Price: "1.0", Timestamp: 0},
+		{Price: "1.05", Timestamp: year / 2},
+	}
+}
+
+func (s *YieldTestSuite) TestValueLP() {
+	tests := []struct {
+		name  string
+		vault dynamicvault.VaultInfo
+		want  dynamicvault.LPValue
+	}{
+		{
+			name:  "should value LP tokens by their share of the vault",
+			vault: dynamicvault.VaultInfo{TotalAmount: 3_000_000, LPSupply: 2_000_000, USDRate: 2},
+			want:  dynamicvault.LPValue{LPAmount: 1_000_001, Amount: 1_500_001, USD: 3.000002},
+		},
+		{
+			name:  "should not overflow for large vaults",
+			vault: dynamicvault.VaultInfo{TotalAmount: 4e18, LPSupply: 2e18, USDRate: 1},
+			want:  dynamicvault.LPValue{LPAmount: 1_000_001, Amount: 2_000_002, USD: 2.000002},
+		},
+		{
+			name:  "should value LP tokens one to one without LP supply",
+			vault: dynamicvault.VaultInfo{USDRate: 1},
+			want:  dynamicvault.LPValue{LPAmount: 1_000_001, Amount: 1_000_001, USD: 1.000001},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			got := tt.vault.ValueLP(1_000_001, 6)
+
+			// Assert
+			s.Equal(tt.want.LPAmount, got.LPAmount)
+			s.Equal(tt.want.Amount, got.Amount)
+			s.InDelta(tt.want.USD, got.USD, 1e-9)
+		})
+	}
+}
+
+func (s *YieldTestSuite) TestRealizedYield() {
+	tests := []struct {
+		name       string
+		start      int64
+		end        int64
+		wantStart  int64
+		wantReturn float64
+		wantAPY    float64
+		wantErr    error
+	}{
+		{
+			name:       "should compute the yield over a year",
+			end:        year,
+			wantReturn: 10,
+			wantAPY:    10,
+		},
+		{
+			name:       "should treat a zero end as unbounded",
+			wantReturn: 10,
+			wantAPY:    10,
+		},
+		{
+			name:       "should compound a half year",
+			end:        year / 2,
+			wantReturn: 5,
+			wantAPY:    10.25,
+		},
+		{
+			name:       "should start at the first price in range",
+			start:      1,
+			wantStart:  year / 2,
+			wantReturn: 100 * (1.1/1.05 - 1),
+			wantAPY:    100 * (1.1*1.1/1.05/1.05 - 1),
+		},
+		{
+			name:    "should return error with a single price in range",
+			start:   year,
+			wantErr: dynamicvault.ErrInsufficientPrices,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			y, err := dynamicvault.RealizedYield(s.prices, tt.start, tt.end)
+
+			// Assert
+			if tt.wantErr != nil {
+				s.ErrorIs(err, tt.wantErr)
+				return
+			}
+			s.Require().NoError(err)
+			s.Equal(tt.wantStart, y.Start.Timestamp)
+			s.InDelta(tt.wantReturn, y.Return, 1e-9)
+			s.InDelta(tt.wantAPY, y.APY, 1e-9)
+		})
+	}
+}
+
+func (s *YieldTestSuite) TestRealizedYieldInvalidPrice() {
+	// Arrange
+	s.prices[1].Price = "n/a"
+
+	// Act
+	_, err := dynamicvault.RealizedYield(s.prices, 0, 0)
+
+	// Assert
+	s.ErrorContains(err, "dynamicvault.RealizedYield: price at 0")
+}
+
+func (s *YieldTestSuite) TestCompareYield() {
+	// Arrange
+	realized, err := dynamicvault.RealizedYield(s.prices, 0, year)
+	s.Require().NoError(err)
+
+	// Act
+	c := dynamicvault.CompareYield(realized, []dynamicvault.APYEntry{
+		{APY: 12, Timestamp: 0},
+		{APY: 14, Timestamp: year},
+		{APY: 100, Timestamp: 2 * year},
+	})
+
+	// Assert
+	s.Equal(2, c.ReportedEntries)
+	s.InDelta(13, c.ReportedAPY, 1e-9)
+	s.InDelta(3, c.Divergence, 1e-9)
+}
+
+func (s *YieldTestSuite) TestGetYieldComparison() {
+	// Arrange
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.Seed(func(d *meteoratest.Data) {
+		d.DynamicVault.VirtualPrices[meteoratest.VaultStrategy{TokenMint: "usdc-mint", Strategy: "vault1"}] = s.prices
+		d.DynamicVault.APYHistory["usdc-mint"] = []dynamicvault.APYEntry{{APY: 8, Timestamp: year / 2}}
+	})
+	client := srv.Client().DynamicVault
+
+	// Act
+	c, err := dynamicvault.GetYieldComparison(context.Background(), client, "usdc-mint", "vault1", 0, 0)
+
+	// Assert
+	s.Require().NoError(err)
+	s.InDelta(-2, c.Divergence, 1e-9)
+	s.Equal(meteoratest.DynamicVaultPrefix+"/apy_filter/usdc-mint/0/31536000", srv.Requests()[1].Path)
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"reflect"
+
+	"github.com/ua1984/meteora-go/internal/flatten"
+)
+
+type csvEncoder struct {
+	cw   *csv.Writer
+	cols []flatten.Column
+	row  []string
+}
+
+func newCSVEncoder(w io.Writer, cols []flatten.Column) (*csvEncoder, error) {
+	e := &csvEncoder{cw: csv.NewWriter(w), cols: cols, row: make([]string, len(cols))}
+	if err := e.cw.Write(flatten.Names(cols)); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) write(rec reflect.Value) error {
+	for i, c := range e.cols {
+		e.row[i] = c.Format(rec)
+	}
+	return e.cw.Write(e.row)
+}
+
+func (e *csvEncoder) close() error {
+	e.cw.Flush()
+	return e.cw.Error()
+}
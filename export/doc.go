@@ -0,0 +1,31 @@
+// Package export writes collections of SDK response types as CSV, NDJSON or
+// Parquet files.
+//
+// Columns are derived from the JSON tags of the item type. Nested structs such
+// as dlmm.TimeBuckets, dlmm.Token and dlmm.PoolConfig are flattened into dotted
+// names ("volume.24h", "token_x.symbol"); types that marshal themselves, such as
+// decimal.Decimal, and slices are written as a single text column. Columns are
+// selected and ordered with WithColumns.
+//
+// Items are written as they are passed to Writer.Write, so large collections
+// can be streamed page by page without holding them in memory. Parquet output
+// buffers one row group at a time (DefaultRowGroupSize rows unless set with
+// WithRowGroupSize) and uses uncompressed PLAIN encoding with optional
+// columns: booleans, integers (as INT64, annotated UINT_64 when unsigned),
+// floats (as DOUBLE) and UTF-8 strings.
+//
+// # Usage
+//
+//	// Write a whole slice.
+//	err := export.Write(os.Stdout, export.CSV, pools,
+//	    export.WithColumns("address", "name", "tvl", "volume.24h"))
+//
+//	// Stream pages into a Parquet file.
+//	w, err := export.NewWriter[dlmm.OHLCV](f, export.Parquet)
+//	for _, page := range pages {
+//	    if err := w.Write(page...); err != nil {
+//	        return err
+//	    }
+//	}
+//	err = w.Close()
+package export
@@ -0,0 +1,158 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/ua1984/meteora-go/internal/flatten"
+)
+
+// Format is an output file format.
+type Format string
+
+const (
+	// CSV writes a header row followed by one row per item. Nil values are
+	// empty cells.
+	CSV Format = "csv"
+
+	// NDJSON writes one JSON object per line. Items are written unchanged
+	// unless columns are selected, in which case each line is a flat object
+	// with the selected dotted names as keys.
+	NDJSON Format = "ndjson"
+
+	// Parquet writes an uncompressed Parquet file with one optional column per
+	// flattened field.
+	Parquet Format = "parquet"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{CSV, NDJSON, Parquet}
+
+// ParseFormat returns the format named s, ignoring case.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("export.ParseFormat: unknown format %q", s)
+}
+
+// DefaultRowGroupSize is the number of rows buffered per Parquet row group.
+const DefaultRowGroupSize = 50000
+
+type options struct {
+	columns      []string
+	rowGroupSize int
+}
+
+// Option configures a Writer.
+type Option func(*options)
+
+// WithColumns selects the columns to write, in order, by dotted JSON name. A
+// name also selects the columns nested below it, so "volume" selects
+// "volume.24h" and its siblings. All columns are written by default.
+func WithColumns(names ...string) Option {
+	return func(o *options) {
+		o.columns = names
+	}
+}
+
+// WithRowGroupSize sets the number of rows buffered in memory before a Parquet
+// row group is written. It has no effect on other formats.
+func WithRowGroupSize(rows int) Option {
+	return func(o *options) {
+		o.rowGroupSize = rows
+	}
+}
+
+// Columns returns the names of the columns written for items of type T.
+func Columns[T any]() []string {
+	return flatten.Names(flatten.Columns(reflect.TypeOf((*T)(nil)).Elem()))
+}
+
+// encoder writes rows in one format.
+type encoder interface {
+	write(rec reflect.Value) error
+	close() error
+}
+
+// Writer streams items of type T to an io.Writer. Items are written as they
+// are passed to Write; only Parquet buffers one row group in memory.
+type Writer[T any] struct {
+	enc    encoder
+	closed bool
+}
+
+// NewWriter returns a Writer writing items of type T to w in format. For CSV
+// the header row is written immediately.
+func NewWriter[T any](w io.Writer, format Format, opts ...Option) (*Writer[T], error) {
+	o := options{rowGroupSize: DefaultRowGroupSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rowGroupSize <= 0 {
+		o.rowGroupSize = DefaultRowGroupSize
+	}
+
+	cols, err := flatten.Select(flatten.Columns(reflect.TypeOf((*T)(nil)).Elem()), o.columns)
+	if err != nil {
+		return nil, fmt.Errorf("export.NewWriter: %w", err)
+	}
+
+	var enc encoder
+	switch format {
+	case CSV:
+		enc, err = newCSVEncoder(w, cols)
+	case NDJSON:
+		enc = newNDJSONEncoder(w, cols, len(o.columns) > 0)
+	case Parquet:
+		enc, err = newParquetEncoder(w, cols, o.rowGroupSize)
+	default:
+		return nil, fmt.Errorf("export.NewWriter: unknown format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("export.NewWriter: %w", err)
+	}
+	return &Writer[T]{enc: enc}, nil
+}
+
+// Write writes items.
+func (w *Writer[T]) Write(items ...T) error {
+	if w.closed {
+		return fmt.Errorf("export.Writer.Write: writer is closed")
+	}
+	for i := range items {
+		if err := w.enc.write(reflect.ValueOf(&items[i]).Elem()); err != nil {
+			return fmt.Errorf("export.Writer.Write: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close flushes buffered rows and, for Parquet, writes the file footer. It
+// does not close the underlying io.Writer.
+func (w *Writer[T]) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.enc.close(); err != nil {
+		return fmt.Errorf("export.Writer.Close: %w", err)
+	}
+	return nil
+}
+
+// Write writes items to w in format.
+func Write[T any](w io.Writer, format Format, items []T, opts ...Option) error {
+	ew, err := NewWriter[T](w, format, opts...)
+	if err != nil {
+		return err
+	}
+	if err := ew.Write(items...); err != nil {
+		return err
+	}
+	return ew.Close()
+}
@@ -0,0 +1,448 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/decimal"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/export"
+)
+
+type ExportTestSuite struct {
+	suite.Suite
+}
+
+func TestExport(t *testing.T) {
+	suite.Run(t, new(ExportTestSuite))
+}
+
+type inner struct {
+	Symbol string `json:"symbol"`
+}
+
+type record struct {
+	Name    string          `json:"name"`
+	Count   int             `json:"count"`
+	Price   float64         `json:"price"`
+	Active  bool            `json:"active"`
+	Amount  decimal.Decimal `json:"amount"`
+	Token   *inner          `json:"token"`
+	Tags    []string        `json:"tags"`
+	Ignored string          `json:"-"`
+}
+
+func records() []record {
+	return []record{
+		{Name: "a", Count: 1, Price: 1.5, Active: true, Amount: decimal.MustParse("0.1"), Token: &inner{Symbol: "SOL"}, Tags: []string{"x", "y"}},
+		{Name: "b", Count: -2, Price: 2.25, Amount: decimal.MustParse("12345678901234567890.5")},
+		{Name: "c", Count: 3, Active: true, Token: &inner{Symbol: "USDC"}},
+	}
+}
+
+type counters struct {
+	Small uint8  `json:"small"`
+	Big   uint64 `json:"big"`
+	Delta int64  `json:"delta"`
+}
+
+func pools() []dlmm.Pool {
+	return []dlmm.Pool{
+		{Address: "pool1", TokenX: dlmm.Token{Symbol: "SOL"}, TVL: 100, Volume: dlmm.TimeBuckets{Hour24: 10, Hour1: 1}},
+		{Address: "pool2", TokenX: dlmm.Token{Symbol: "JUP"}, TVL: 200, Volume: dlmm.TimeBuckets{Hour24: 20}},
+	}
+}
+
+func (s *ExportTestSuite) TestColumns() {
+	// Act
+	cols := export.Columns[dlmm.Pool]()
+
+	// Assert
+	s.Contains(cols, "token_x.symbol")
+	s.Contains(cols, "pool_config.bin_step")
+	s.Contains(cols, "volume.24h")
+	s.Contains(cols, "tags")
+	s.Equal([]string{"name", "count", "price", "active", "amount", "token.symbol", "tags"}, export.Columns[record]())
+}
+
+func (s *ExportTestSuite) TestCSV() {
+	tests := []struct {
+		name    string
+		columns []string
+		want    [][]string
+	}{
+		{
+			name: "should write every column",
+			want: [][]string{
+				{"name", "count", "price", "active", "amount", "token.symbol", "tags"},
+				{"a", "1", "1.5", "true", "0.1", "SOL", "x,y"},
+				{"b", "-2", "2.25", "false", "12345678901234567890.5", "", ""},
+				{"c", "3", "0", "true", "0", "USDC", ""},
+			},
+		},
+		{
+			name:    "should write the selected columns in order",
+			columns: []string{"token", "name"},
+			want: [][]string{
+				{"token.symbol", "name"},
+				{"SOL", "a"},
+				{"", "b"},
+				{"USDC", "c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Arrange
+			var buf bytes.Buffer
+
+			// Act
+			err := export.Write(&buf, export.CSV, records(), export.WithColumns(tt.columns...))
+
+			// Assert
+			s.Require().NoError(err)
+			rows, err := csv.NewReader(&buf).ReadAll()
+			s.Require().NoError(err)
+			s.Equal(tt.want, rows)
+		})
+	}
+}
+
+func (s *ExportTestSuite) TestNDJSON() {
+	s.Run("should write items unchanged without selected columns", func() {
+		// Arrange
+		var buf bytes.Buffer
+
+		// Act
+		err := export.Write(&buf, export.NDJSON, pools())
+
+		// Assert
+		s.Require().NoError(err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		s.Require().Len(lines, 2)
+		var p dlmm.Pool
+		s.Require().NoError(json.Unmarshal([]byte(lines[1]), &p))
+		s.Equal(pools()[1], p)
+	})
+
+	s.Run("should write flat objects with selected columns", func() {
+		// Arrange
+		var buf bytes.Buffer
+
+		// Act
+		err := export.Write(&buf, export.NDJSON, records(), export.WithColumns("token.symbol", "amount", "name"))
+
+		// Assert
+		s.Require().NoError(err)
+		s.Equal(`{"token.symbol":"SOL","amount":"0.1","name":"a"}
+{"token.symbol":null,"amount":"12345678901234567890.5","name":"b"}
+{"token.symbol":"USDC","amount":"0","name":"c"}
+`, buf.String())
+	})
+}
+
+func (s *ExportTestSuite) TestParquet() {
+	// Arrange
+	var buf bytes.Buffer
+	w, err := export.NewWriter[record](&buf, export.Parquet, export.WithRowGroupSize(2))
+	s.Require().NoError(err)
+
+	// Act
+	for _, r := range records() {
+		s.Require().NoError(w.Write(r))
+	}
+	s.Require().NoError(w.Close())
+
+	// Assert
+	file := readParquet(s.T(), buf.Bytes())
+	s.Equal(int64(3), file.rows)
+	s.Equal(2, file.rowGroups)
+	s.Equal([]string{"name", "count", "price", "active", "amount", "token.symbol", "tags"}, file.names)
+	s.Equal(map[string][]any{
+		"name":         {"a", "b", "c"},
+		"count":        {int64(1), int64(-2), int64(3)},
+		"price":        {1.5, 2.25, 0.0},
+		"active":       {true, false, true},
+		"amount":       {"0.1", "12345678901234567890.5", "0"},
+		"token.symbol": {"SOL", nil, "USDC"},
+		"tags":         {"x,y", nil, nil},
+	}, file.columns)
+}
+
+func (s *ExportTestSuite) TestParquetUnsigned() {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := export.Write(&buf, export.Parquet, []counters{{Small: 255, Big: math.MaxUint64, Delta: -1}})
+
+	// Assert
+	s.Require().NoError(err)
+	file := readParquet(s.T(), buf.Bytes())
+	s.Equal([]any{uint64(255)}, file.columns["small"])
+	s.Equal([]any{uint64(math.MaxUint64)}, file.columns["big"])
+	s.Equal([]any{int64(-1)}, file.columns["delta"])
+}
+
+// TestParquetPyArrow reads the output with pyarrow to check that it
+// interoperates with an independent Parquet implementation, beyond the
+// test-local readParquet. It is skipped when pyarrow is not installed.
+func (s *ExportTestSuite) TestParquetPyArrow() {
+	// Arrange
+	python, err := exec.LookPath("python3")
+	if err != nil || exec.Command(python, "-c", "import pyarrow").Run() != nil {
+		s.T().Skip("pyarrow not installed")
+	}
+	path := filepath.Join(s.T().TempDir(), "out.parquet")
+	f, err := os.Create(path)
+	s.Require().NoError(err)
+	w, err := export.NewWriter[record](f, export.Parquet, export.WithRowGroupSize(2))
+	s.Require().NoError(err)
+	s.Require().NoError(w.Write(records()...))
+	s.Require().NoError(w.Close())
+	s.Require().NoError(f.Close())
+	unsigned := filepath.Join(s.T().TempDir(), "unsigned.parquet")
+	f, err = os.Create(unsigned)
+	s.Require().NoError(err)
+	s.Require().NoError(export.Write(f, export.Parquet, []counters{{Small: 255, Big: math.MaxUint64, Delta: -1}}))
+	s.Require().NoError(f.Close())
+
+	// Act
+	script := `
+import json, sys
+import pyarrow.parquet as pq
+for path in sys.argv[1:]:
+    t = pq.read_table(path)
+    print(json.dumps({"types": [str(f.type) for f in t.schema], "rows": t.to_pylist()}))
+`
+	out, err := exec.Command(python, "-c", script, path, unsigned).Output()
+
+	// Assert
+	s.Require().NoError(err)
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	var got struct {
+		Types []string         `json:"types"`
+		Rows  []map[string]any `json:"rows"`
+	}
+	s.Require().NoError(dec.Decode(&got))
+	s.Equal([]string{"string", "int64", "double", "bool", "string", "string", "string"}, got.Types)
+	s.Require().Len(got.Rows, 3)
+	s.Equal(map[string]any{
+		"name": "b", "count": json.Number("-2"), "price": json.Number("2.25"), "active": false,
+		"amount": "12345678901234567890.5", "token.symbol": nil, "tags": nil,
+	}, got.Rows[1])
+	s.Equal("USDC", got.Rows[2]["token.symbol"])
+
+	got.Rows = nil
+	s.Require().NoError(dec.Decode(&got))
+	s.Equal([]string{"uint64", "uint64", "int64"}, got.Types)
+	s.Equal([]map[string]any{{"small": json.Number("255"), "big": json.Number("18446744073709551615"), "delta": json.Number("-1")}}, got.Rows)
+}
+
+func (s *ExportTestSuite) TestParquetEmpty() {
+	// Arrange
+	var buf bytes.Buffer
+
+	// Act
+	err := export.Write(&buf, export.Parquet, []dlmm.OHLCV(nil))
+
+	// Assert
+	s.Require().NoError(err)
+	file := readParquet(s.T(), buf.Bytes())
+	s.Equal(int64(0), file.rows)
+	s.Equal(export.Columns[dlmm.OHLCV](), file.names)
+}
+
+func (s *ExportTestSuite) TestErrors() {
+	s.Run("should reject unknown columns", func() {
+		// Act
+		_, err := export.NewWriter[dlmm.Pool](&bytes.Buffer{}, export.CSV, export.WithColumns("nope"))
+
+		// Assert
+		s.ErrorContains(err, `unknown column "nope"`)
+	})
+
+	s.Run("should reject unknown formats", func() {
+		// Act
+		_, err := export.ParseFormat("xlsx")
+
+		// Assert
+		s.Error(err)
+	})
+
+	s.Run("should reject writes after close", func() {
+		// Arrange
+		w, err := export.NewWriter[record](&bytes.Buffer{}, export.NDJSON)
+		s.Require().NoError(err)
+		s.Require().NoError(w.Close())
+
+		// Act
+		err = w.Write(records()...)
+
+		// Assert
+		s.Error(err)
+	})
+}
+
+// parquetFile is the content of a Parquet file decoded by readParquet.
+type parquetFile struct {
+	rows      int64
+	rowGroups int
+	names     []string
+	columns   map[string][]any
+}
+
+// readParquet decodes the flat, uncompressed, PLAIN-encoded files written by
+// the export package.
+func readParquet(t *testing.T, data []byte) parquetFile {
+	t.Helper()
+	n := len(data)
+	if string(data[:4]) != "PAR1" || string(data[n-4:]) != "PAR1" {
+		t.Fatalf("missing magic")
+	}
+	size := int(binary.LittleEndian.Uint32(data[n-8:]))
+	meta, _ := readStruct(data[n-8-size : n-8])
+
+	file := parquetFile{rows: meta[3].(int64), columns: map[string][]any{}}
+	schema := meta[2].([]any)
+	types := map[string]int64{}
+	unsigned := map[string]bool{}
+	for _, el := range schema[1:] {
+		el := el.(map[int16]any)
+		name := string(el[4].([]byte))
+		file.names = append(file.names, name)
+		types[name] = el[1].(int64)
+		unsigned[name] = el[6] == int64(14)
+	}
+	groups := meta[4].([]any)
+	file.rowGroups = len(groups)
+	for _, g := range groups {
+		for _, chunk := range g.(map[int16]any)[1].([]any) {
+			md := chunk.(map[int16]any)[3].(map[int16]any)
+			name := string(md[3].([]any)[0].([]byte))
+			offset := md[9].(int64)
+			header, hn := readStruct(data[offset:])
+			body := data[int(offset)+hn:][:header[3].(int64)]
+			numValues := int(header[5].(map[int16]any)[1].(int64))
+			file.columns[name] = append(file.columns[name], readPage(body, numValues, types[name], unsigned[name])...)
+		}
+	}
+	return file
+}
+
+func readPage(body []byte, numValues int, typ int64, unsigned bool) []any {
+	levelsLen := int(binary.LittleEndian.Uint32(body))
+	levels := body[4 : 4+levelsLen]
+	values := body[4+levelsLen:]
+	var defs []byte
+	for len(levels) > 0 {
+		h, n := binary.Uvarint(levels)
+		for i := 0; i < int(h>>1); i++ {
+			defs = append(defs, levels[n])
+		}
+		levels = levels[n+1:]
+	}
+
+	out := make([]any, numValues)
+	bit := 0
+	for i := range out {
+		if defs[i] == 0 {
+			continue
+		}
+		switch typ {
+		case 0:
+			out[i] = values[bit/8]&(1<<(bit%8)) != 0
+			bit++
+		case 2:
+			if unsigned {
+				out[i] = binary.LittleEndian.Uint64(values)
+			} else {
+				out[i] = int64(binary.LittleEndian.Uint64(values))
+			}
+			values = values[8:]
+		case 5:
+			out[i] = math.Float64frombits(binary.LittleEndian.Uint64(values))
+			values = values[8:]
+		case 6:
+			l := binary.LittleEndian.Uint32(values)
+			out[i] = string(values[4 : 4+l])
+			values = values[4+l:]
+		}
+	}
+	return out
+}
+
+// readStruct decodes a Thrift compact struct into a map of field ids and
+// returns the number of bytes read.
+func readStruct(data []byte) (map[int16]any, int) {
+	fields := map[int16]any{}
+	pos := 0
+	var last int16
+	for {
+		b := data[pos]
+		pos++
+		if b == 0 {
+			return fields, pos
+		}
+		typ := b & 0x0f
+		if delta := int16(b >> 4); delta != 0 {
+			last += delta
+		} else {
+			v, n := binary.Uvarint(data[pos:])
+			pos += n
+			last = int16(unzigzag(v))
+		}
+		v, n := readValue(data[pos:], typ)
+		pos += n
+		fields[last] = v
+	}
+}
+
+func readValue(data []byte, typ byte) (any, int) {
+	switch typ {
+	case 1, 2:
+		return typ == 1, 0
+	case 3:
+		return int64(int8(data[0])), 1
+	case 5, 6:
+		v, n := binary.Uvarint(data)
+		return unzigzag(v), n
+	case 8:
+		l, n := binary.Uvarint(data)
+		return data[n : n+int(l)], n + int(l)
+	case 9:
+		size := int(data[0] >> 4)
+		elem := data[0] & 0x0f
+		pos := 1
+		if size == 15 {
+			v, n := binary.Uvarint(data[1:])
+			size = int(v)
+			pos += n
+		}
+		list := make([]any, size)
+		for i := range list {
+			v, n := readValue(data[pos:], elem)
+			list[i] = v
+			pos += n
+		}
+		return list, pos
+	case 12:
+		return readStruct(data)
+	}
+	panic("unsupported thrift type")
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
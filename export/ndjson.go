@@ -0,0 +1,61 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/ua1984/meteora-go/internal/flatten"
+)
+
+type ndjsonEncoder struct {
+	w    io.Writer
+	enc  *json.Encoder
+	cols []flatten.Column
+	flat bool
+	buf  bytes.Buffer
+}
+
+func newNDJSONEncoder(w io.Writer, cols []flatten.Column, flat bool) *ndjsonEncoder {
+	return &ndjsonEncoder{w: w, enc: json.NewEncoder(w), cols: cols, flat: flat}
+}
+
+func (e *ndjsonEncoder) write(rec reflect.Value) error {
+	if !e.flat {
+		return e.enc.Encode(rec.Interface())
+	}
+
+	// Build the object by hand to keep the selected column order.
+	e.buf.Reset()
+	e.buf.WriteByte('{')
+	for i, c := range e.cols {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		key, err := json.Marshal(c.Name)
+		if err != nil {
+			return err
+		}
+		e.buf.Write(key)
+		e.buf.WriteByte(':')
+
+		v, ok := c.Value(rec)
+		if !ok {
+			e.buf.WriteString("null")
+			continue
+		}
+		value, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		e.buf.Write(value)
+	}
+	e.buf.WriteString("}\n")
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+func (e *ndjsonEncoder) close() error {
+	return nil
+}
@@ -0,0 +1,313 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+
+	"github.com/ua1984/meteora-go/internal/flatten"
+)
+
+// Parquet physical types.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+)
+
+// Parquet enum values used by the encoder.
+const (
+	encodingPlain      = 0
+	encodingRLE        = 3
+	repetitionOptional = 1
+	convertedUTF8      = 0
+	convertedUint64    = 14
+	pageTypeData       = 0
+	codecUncompressed  = 0
+)
+
+var parquetMagic = []byte("PAR1")
+
+// parquetEncoder buffers up to rowGroupSize rows per column and writes each
+// buffered batch as a row group with one data page per column. Every column is
+// optional; nil values are recorded as a definition level of 0.
+type parquetEncoder struct {
+	w            *countingWriter
+	cols         []*parquetColumn
+	rowGroupSize int
+	rows         int
+	numRows      int64
+	groups       []parquetRowGroup
+}
+
+type parquetColumn struct {
+	flatten.Column
+	physical int32
+
+	// unsigned marks INT64 columns holding unsigned integers. They are
+	// annotated as UINT_64 so values above math.MaxInt64 read back unsigned.
+	unsigned bool
+
+	// defs holds the definition level of each buffered row.
+	defs []byte
+
+	// values holds the PLAIN-encoded non-nil values. Booleans are kept in
+	// bools and bit-packed when the page is written.
+	values bytes.Buffer
+	bools  []bool
+}
+
+type parquetRowGroup struct {
+	rows   int64
+	size   int64
+	chunks []parquetChunk
+}
+
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+func newParquetEncoder(w io.Writer, cols []flatten.Column, rowGroupSize int) (*parquetEncoder, error) {
+	e := &parquetEncoder{w: &countingWriter{w: w}, rowGroupSize: rowGroupSize}
+	for _, c := range cols {
+		e.cols = append(e.cols, &parquetColumn{Column: c, physical: physicalType(c.Type), unsigned: isUnsigned(c.Type)})
+	}
+	if _, err := e.w.Write(parquetMagic); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// physicalType maps Go scalars to their Parquet types. Everything else,
+// including types that marshal themselves, is stored as a UTF-8 string.
+func physicalType(t reflect.Type) int32 {
+	if flatten.IsLeaf(t) {
+		return parquetByteArray
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return parquetBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return parquetInt64
+	case reflect.Float32, reflect.Float64:
+		return parquetDouble
+	default:
+		return parquetByteArray
+	}
+}
+
+// isUnsigned reports whether t is stored as an unsigned INT64.
+func isUnsigned(t reflect.Type) bool {
+	if flatten.IsLeaf(t) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func (e *parquetEncoder) write(rec reflect.Value) error {
+	for _, c := range e.cols {
+		v, ok := c.Value(rec)
+		if !ok {
+			c.defs = append(c.defs, 0)
+			continue
+		}
+		c.defs = append(c.defs, 1)
+		switch c.physical {
+		case parquetBoolean:
+			c.bools = append(c.bools, v.Bool())
+		case parquetInt64:
+			var n uint64
+			if v.CanInt() {
+				n = uint64(v.Int())
+			} else {
+				n = v.Uint()
+			}
+			c.values.Write(binary.LittleEndian.AppendUint64(nil, n))
+		case parquetDouble:
+			c.values.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v.Float())))
+		default:
+			s := flatten.Format(v)
+			c.values.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(s))))
+			c.values.WriteString(s)
+		}
+	}
+	e.rows++
+	if e.rows >= e.rowGroupSize {
+		return e.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows as a row group.
+func (e *parquetEncoder) flush() error {
+	if e.rows == 0 {
+		return nil
+	}
+	group := parquetRowGroup{rows: int64(e.rows)}
+	for _, c := range e.cols {
+		offset := e.w.n
+		if err := c.writePage(e.w, e.rows); err != nil {
+			return err
+		}
+		chunk := parquetChunk{offset: offset, size: e.w.n - offset}
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.size
+	}
+	e.groups = append(e.groups, group)
+	e.numRows += group.rows
+	e.rows = 0
+	return nil
+}
+
+// writePage writes the buffered values of c as a data page and resets the
+// buffers.
+func (c *parquetColumn) writePage(w io.Writer, rows int) error {
+	levels := encodeLevels(c.defs)
+	var body bytes.Buffer
+	body.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(levels))))
+	body.Write(levels)
+	if c.physical == parquetBoolean {
+		body.Write(packBools(c.bools))
+	} else {
+		body.Write(c.values.Bytes())
+	}
+
+	h := newThriftWriter()
+	h.i32(1, pageTypeData)
+	h.i32(2, int32(body.Len()))
+	h.i32(3, int32(body.Len()))
+	h.structField(5)
+	h.i32(1, int32(rows))
+	h.i32(2, encodingPlain)
+	h.i32(3, encodingRLE)
+	h.i32(4, encodingRLE)
+	h.end()
+	h.end()
+
+	if _, err := w.Write(h.buf.Bytes()); err != nil {
+		return err
+	}
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return err
+	}
+
+	c.defs = c.defs[:0]
+	c.values.Reset()
+	c.bools = c.bools[:0]
+	return nil
+}
+
+// encodeLevels encodes definition levels of bit width 1 as RLE runs of the
+// RLE/bit-packing hybrid encoding.
+func encodeLevels(levels []byte) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		out = append(out, levels[i])
+		i = j
+	}
+	return out
+}
+
+// packBools bit-packs booleans least significant bit first.
+func packBools(bs []bool) []byte {
+	out := make([]byte, (len(bs)+7)/8)
+	for i, b := range bs {
+		if b {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
+
+func (e *parquetEncoder) close() error {
+	if err := e.flush(); err != nil {
+		return err
+	}
+
+	m := newThriftWriter()
+	m.i32(1, 1)
+	m.list(2, thriftStruct, len(e.cols)+1)
+	m.begin()
+	m.string(4, "schema")
+	m.i32(5, int32(len(e.cols)))
+	m.end()
+	for _, c := range e.cols {
+		m.begin()
+		m.i32(1, c.physical)
+		m.i32(3, repetitionOptional)
+		m.string(4, c.Name)
+		switch {
+		case c.physical == parquetByteArray:
+			m.i32(6, convertedUTF8)
+		case c.unsigned:
+			// converted_type UINT_64 and logicalType INTEGER(64, unsigned).
+			m.i32(6, convertedUint64)
+			m.structField(10)
+			m.structField(10)
+			m.i8(1, 64)
+			m.bool(2, false)
+			m.end()
+			m.end()
+		}
+		m.end()
+	}
+	m.i64(3, e.numRows)
+	m.list(4, thriftStruct, len(e.groups))
+	for _, g := range e.groups {
+		m.begin()
+		m.list(1, thriftStruct, len(g.chunks))
+		for i, chunk := range g.chunks {
+			c := e.cols[i]
+			m.begin()
+			m.i64(2, chunk.offset)
+			m.structField(3)
+			m.i32(1, c.physical)
+			m.i32List(2, encodingPlain, encodingRLE)
+			m.stringList(3, c.Name)
+			m.i32(4, codecUncompressed)
+			m.i64(5, g.rows)
+			m.i64(6, chunk.size)
+			m.i64(7, chunk.size)
+			m.i64(9, chunk.offset)
+			m.end()
+			m.end()
+		}
+		m.i64(2, g.size)
+		m.i64(3, g.rows)
+		m.end()
+	}
+	m.string(6, "github.com/ua1984/meteora-go/export")
+	m.end()
+
+	footer := m.buf.Bytes()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	footer = append(footer, parquetMagic...)
+	_, err := e.w.Write(footer)
+	return err
+}
+
+// countingWriter tracks the file offset for the column chunk metadata.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
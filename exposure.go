@@ -0,0 +1,212 @@
+package meteora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dynamicvault"
+)
+
+// ErrVaultNotFound is reported for pool tokens whose dynamic vault is not in
+// the vault listing.
+var ErrVaultNotFound = errors.New("dynamic vault not found")
+
+// IdleExposure is the ProtocolExposure.Protocol of funds a vault holds itself
+// rather than lending out.
+const IdleExposure = "idle"
+
+// VaultMatch is how a pool token was resolved to its dynamic vault.
+type VaultMatch string
+
+const (
+	// VaultMatchAddress matched the pool's vault address to VaultInfo.Pubkey.
+	VaultMatchAddress VaultMatch = "address"
+
+	// VaultMatchLPMint matched the pool's vault LP to VaultInfo.LPMint.
+	VaultMatchLPMint VaultMatch = "lp_mint"
+
+	// VaultMatchTokenMint matched the token mint to VaultInfo.TokenAddress.
+	VaultMatchTokenMint VaultMatch = "token_mint"
+)
+
+// VaultExposure is one token of a DAMM v1 pool and the dynamic vault it is
+// deposited in.
+type VaultExposure struct {
+	// Token is the pool token.
+	Token dammv1.PoolToken `json:"token"`
+
+	// Vault is the token's dynamic vault, or nil if Err is set.
+	Vault *dynamicvault.VaultInfo `json:"vault"`
+
+	// Match is how Vault was found.
+	Match VaultMatch `json:"match"`
+
+	// Allocation is how the vault's funds are spread across its strategies,
+	// or nil if Err is set.
+	Allocation *dynamicvault.Allocation `json:"allocation"`
+
+	// PoolShare is the token's USD value as a percentage of the pool's.
+	PoolShare float64 `json:"pool_share"`
+
+	// Err is ErrVaultNotFound when the vault could not be resolved.
+	Err error `json:"-"`
+}
+
+// ProtocolExposure is the part of a pool's value sitting in one lending
+// protocol.
+type ProtocolExposure struct {
+	// Protocol is the strategy name of the lending protocol, or IdleExposure.
+	Protocol string `json:"protocol"`
+
+	// Share is the percentage of the pool's value in the protocol.
+	Share float64 `json:"share"`
+
+	// USD is Share of the pool's USD value.
+	USD float64 `json:"usd"`
+}
+
+// PoolExposure is the look-through exposure of a DAMM v1 pool: where the
+// tokens it deposits in dynamic vaults are lent out.
+type PoolExposure struct {
+	// Pool is the pool address.
+	Pool string `json:"pool"`
+
+	// Name is the pool name.
+	Name string `json:"name"`
+
+	// TotalUSD is the USD value of the pool's tokens.
+	TotalUSD float64 `json:"total_usd"`
+
+	// Vaults holds one entry per pool token, in pool order.
+	Vaults []VaultExposure `json:"vaults"`
+
+	// Protocols holds the pool's exposure to each lending protocol, largest
+	// first. Shares sum to 100 minus Unresolved.
+	Protocols []ProtocolExposure `json:"protocols"`
+
+	// Unresolved is the percentage of the pool's value in tokens whose vault
+	// could not be resolved.
+	Unresolved float64 `json:"unresolved"`
+
+	// Err is the error returned for the pool by GetPoolExposures.
+	Err error `json:"-"`
+}
+
+// Exposure returns the share of each DAMM v1 LP position behind pool that
+// sits in each lending protocol. Each pool token is resolved to its dynamic
+// vault in vaults by vault address, then by LP mint and finally by token
+// mint. Its share of the pool's USD value is then split by the vault's
+// allocation across its strategies and idle funds. A token whose vault is not
+// found has ErrVaultNotFound set and counts as Unresolved. It returns an error
+// when the pool's tokens cannot be read.
+func Exposure(pool *dammv1.Pool, vaults []dynamicvault.VaultInfo) (*PoolExposure, error) {
+	tokens, err := pool.Tokens()
+	if err != nil {
+		return nil, fmt.Errorf("meteora.Exposure: %w", err)
+	}
+
+	e := &PoolExposure{Pool: pool.PoolAddress, Name: pool.PoolName, Vaults: make([]VaultExposure, len(tokens))}
+	for _, t := range tokens {
+		e.TotalUSD += t.USDAmount
+	}
+
+	byProtocol := map[string]float64{}
+	for i, t := range tokens {
+		v := VaultExposure{Token: t}
+		if e.TotalUSD > 0 {
+			v.PoolShare = t.USDAmount / e.TotalUSD * 100
+		}
+		v.Vault, v.Match = resolveVault(t, vaults)
+		if v.Vault == nil {
+			v.Err = fmt.Errorf("meteora.Exposure: %s: token %s: %w", pool.PoolAddress, t.Mint, ErrVaultNotFound)
+			e.Unresolved += v.PoolShare
+			e.Vaults[i] = v
+			continue
+		}
+
+		v.Allocation = dynamicvault.Analyze(v.Vault, nil, dynamicvault.APYClosest)
+		byProtocol[IdleExposure] += v.PoolShare * v.Allocation.IdleShare / 100
+		for _, s := range v.Allocation.Strategies {
+			name := s.Strategy.StrategyName
+			if name == "" {
+				name = s.Strategy.StrategyType
+			}
+			byProtocol[name] += v.PoolShare * s.Share / 100
+		}
+		e.Vaults[i] = v
+	}
+
+	for name, share := range byProtocol {
+		e.Protocols = append(e.Protocols, ProtocolExposure{Protocol: name, Share: share, USD: share / 100 * e.TotalUSD})
+	}
+	sort.Slice(e.Protocols, func(i, j int) bool {
+		if e.Protocols[i].Share != e.Protocols[j].Share {
+			return e.Protocols[i].Share > e.Protocols[j].Share
+		}
+		return e.Protocols[i].Protocol < e.Protocols[j].Protocol
+	})
+	return e, nil
+}
+
+// resolveVault finds the vault of t.
+func resolveVault(t dammv1.PoolToken, vaults []dynamicvault.VaultInfo) (*dynamicvault.VaultInfo, VaultMatch) {
+	matches := []struct {
+		match VaultMatch
+		ok    func(v *dynamicvault.VaultInfo) bool
+	}{
+		{VaultMatchAddress, func(v *dynamicvault.VaultInfo) bool { return t.Vault != "" && v.Pubkey == t.Vault }},
+		{VaultMatchLPMint, func(v *dynamicvault.VaultInfo) bool { return t.VaultLP != "" && v.LPMint == t.VaultLP }},
+		{VaultMatchTokenMint, func(v *dynamicvault.VaultInfo) bool { return t.Mint != "" && v.TokenAddress == t.Mint }},
+	}
+	for _, m := range matches {
+		for i := range vaults {
+			if m.ok(&vaults[i]) {
+				return &vaults[i], m.match
+			}
+		}
+	}
+	return nil, ""
+}
+
+// GetPoolExposures fetches the DAMM v1 pools with the given addresses, with
+// DAMMv1.GetPools, and every dynamic vault, with DynamicVault.ListVaultInfo,
+// and computes the Exposure of each pool. It returns one record per unique
+// address, in order of first appearance; a pool that fails sets Err on its
+// record only. It returns an error when the vaults cannot be listed. opts may
+// be nil.
+func (c *Client) GetPoolExposures(ctx context.Context, addresses []string, opts *dammv1.BatchOptions) ([]PoolExposure, error) {
+	vaults, err := c.DynamicVault.ListVaultInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("meteora.GetPoolExposures: %w", err)
+	}
+
+	results := c.DAMMv1.GetPools(ctx, addresses, opts)
+	out := make([]PoolExposure, len(results))
+	for i, r := range results {
+		if r.Err != nil {
+			out[i] = PoolExposure{Pool: r.Address, Err: fmt.Errorf("meteora.GetPoolExposures: %s: %w", r.Address, r.Err)}
+			continue
+		}
+		e, err := Exposure(r.Pool, vaults)
+		if err != nil {
+			out[i] = PoolExposure{Pool: r.Address, Name: r.Pool.PoolName, Err: fmt.Errorf("meteora.GetPoolExposures: %w", err)}
+			continue
+		}
+		out[i] = *e
+	}
+	return out, nil
+}
+
+// GetVaultPools returns the DAMM v1 pools that deposit into vault, found with
+// DAMMv1.GetPoolsByVaultLP from the vault's LP mint. It is the reverse of the
+// lookup done by Exposure.
+func (c *Client) GetVaultPools(ctx context.Context, vault *dynamicvault.VaultInfo) ([]dammv1.Pool, error) {
+	pools, err := c.DAMMv1.GetPoolsByVaultLP(ctx, vault.LPMint)
+	if err != nil {
+		return nil, fmt.Errorf("meteora.GetVaultPools: %w", err)
+	}
+	return pools, nil
+}
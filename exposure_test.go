@@ -0,0 +1,144 @@
+package meteora_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	meteora "github.com/ua1984/meteora-go"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dynamicvault"
+	"github.com/ua1984/meteora-go/meteoratest"
+)
+
+type ExposureTestSuite struct {
+	suite.Suite
+	srv    *meteoratest.Server
+	client *meteora.Client
+	pools  []dammv1.Pool
+	vaults []dynamicvault.VaultInfo
+}
+
+func TestExposure(t *testing.T) {
+	suite.Run(t, new(ExposureTestSuite))
+}
+
+func (s *ExposureTestSuite) SetupTest() {
+	s.pools = []dammv1.Pool{
+		{
+			PoolAddress: "pool1", PoolName: "USDC-USDT",
+			PoolTokenMints: []string{"usdc", "usdt"}, PoolTokenAmounts: []string{"600", "400"}, PoolTokenUSDAmounts: []string{"600", "400"},
+			Vaults: []string{"vault-usdc", "vault-usdt"}, VaultLPs: []string{"lp-usdc", "lp-usdt"},
+		},
+		{
+			PoolAddress: "pool2", PoolName: "USDC-BONK",
+			PoolTokenMints: []string{"usdc", "bonk"}, PoolTokenAmounts: []string{"100", "1000000"}, PoolTokenUSDAmounts: []string{"100", "100"},
+			Vaults: []string{"unknown-1", "unknown-2"}, VaultLPs: []string{"unknown-lp-1", "unknown-lp-2"},
+		},
+	}
+	s.vaults = []dynamicvault.VaultInfo{
+		{
+			Pubkey: "vault-usdc", TokenAddress: "usdc", LPMint: "lp-usdc", TotalAmount: 1000, TokenAmount: 100,
+			Strategies: []dynamicvault.Strategy{{StrategyName: "Kamino", Liquidity: 600}, {StrategyName: "Solend", Liquidity: 300}},
+		},
+		{
+			Pubkey: "other", TokenAddress: "usdt", LPMint: "lp-usdt", TotalAmount: 1000,
+			Strategies: []dynamicvault.Strategy{{StrategyName: "Kamino", Liquidity: 500}, {StrategyType: "marginfi", Liquidity: 500}},
+		},
+	}
+	s.srv = meteoratest.NewServer()
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DAMMv1.Pools = s.pools
+		d.DynamicVault.Vaults = s.vaults
+	})
+	s.client = s.srv.Client()
+}
+
+func (s *ExposureTestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func (s *ExposureTestSuite) TestExposure() {
+	// Act
+	e, err := meteora.Exposure(&s.pools[0], s.vaults)
+
+	// Assert
+	s.Require().NoError(err)
+	s.InDelta(1000, e.TotalUSD, 1e-9)
+	s.Require().Len(e.Vaults, 2)
+	s.Equal(meteora.VaultMatchAddress, e.Vaults[0].Match)
+	s.Equal(meteora.VaultMatchLPMint, e.Vaults[1].Match)
+	s.Equal("other", e.Vaults[1].Vault.Pubkey)
+	s.InDelta(60, e.Vaults[0].PoolShare, 1e-9)
+
+	want := []meteora.ProtocolExposure{
+		{Protocol: "Kamino", Share: 56, USD: 560},
+		{Protocol: "marginfi", Share: 20, USD: 200},
+		{Protocol: "Solend", Share: 18, USD: 180},
+		{Protocol: meteora.IdleExposure, Share: 6, USD: 60},
+	}
+	s.Require().Len(e.Protocols, len(want))
+	for i, w := range want {
+		s.Equal(w.Protocol, e.Protocols[i].Protocol)
+		s.InDelta(w.Share, e.Protocols[i].Share, 1e-9)
+		s.InDelta(w.USD, e.Protocols[i].USD, 1e-9)
+	}
+	s.Zero(e.Unresolved)
+}
+
+func (s *ExposureTestSuite) TestExposureUnresolved() {
+	// Act
+	e, err := meteora.Exposure(&s.pools[1], s.vaults)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(meteora.VaultMatchTokenMint, e.Vaults[0].Match)
+	s.ErrorIs(e.Vaults[1].Err, meteora.ErrVaultNotFound)
+	s.InDelta(50, e.Unresolved, 1e-9)
+}
+
+func (s *ExposureTestSuite) TestExposureMisaligned() {
+	// Arrange
+	pool := dammv1.Pool{PoolAddress: "pool1", PoolTokenMints: []string{"usdc"}}
+
+	// Act
+	_, err := meteora.Exposure(&pool, s.vaults)
+
+	// Assert
+	s.ErrorIs(err, dammv1.ErrMisalignedTokens)
+}
+
+func (s *ExposureTestSuite) TestGetPoolExposures() {
+	// Act
+	exposures, err := s.client.GetPoolExposures(context.Background(), []string{"pool1", "missing", "pool2"}, nil)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(exposures, 3)
+	s.Require().NoError(exposures[0].Err)
+	s.Equal("Kamino", exposures[0].Protocols[0].Protocol)
+	s.ErrorIs(exposures[1].Err, dammv1.ErrPoolNotFound)
+	s.InDelta(50, exposures[2].Unresolved, 1e-9)
+}
+
+func (s *ExposureTestSuite) TestGetPoolExposuresError() {
+	// Arrange
+	s.srv.InjectFault(meteoratest.Fault{Path: meteoratest.DynamicVaultPrefix, Status: http.StatusBadRequest})
+
+	// Act
+	_, err := s.client.GetPoolExposures(context.Background(), []string{"pool1"}, nil)
+
+	// Assert
+	s.ErrorContains(err, "meteora.GetPoolExposures")
+}
+
+func (s *ExposureTestSuite) TestGetVaultPools() {
+	// Act
+	pools, err := s.client.GetVaultPools(context.Background(), &s.vaults[1])
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(pools, 1)
+	s.Equal("pool1", pools[0].PoolAddress)
+}
@@ -0,0 +1,95 @@
+// Package batch provides helpers for fanning out API calls over many keys with
+// bounded parallelism. Rate limiting is left to the shared httpclient.Client.
+package batch
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is the number of concurrent requests used when none is configured.
+const DefaultConcurrency = 8
+
+// DefaultChunkSize is the number of values sent per request for multi-value
+// query parameters. It matches the documented 100-address limit of the APIs.
+const DefaultChunkSize = 100
+
+// Run calls fn for every index in [0, n) using at most concurrency goroutines
+// and waits for all calls to return. fn is expected to store its result at the
+// given index. A concurrency of zero or less uses DefaultConcurrency.
+func Run(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int)) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(ctx, i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// Unique returns keys without duplicates or empty strings, in order of first appearance.
+func Unique(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	unique := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		unique = append(unique, k)
+	}
+	return unique
+}
+
+// Chunk splits items into consecutive slices of at most size elements.
+// A size of zero or less uses DefaultChunkSize.
+func Chunk(items []string, size int) [][]string {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	var chunks [][]string
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		chunks = append(chunks, items[start:end])
+	}
+	return chunks
+}
+
+// Map calls fn for every index in [0, n) using at most concurrency goroutines
+// and returns the results in index order. If any call fails, Map returns the
+// error of the lowest failing index.
+func Map[T any](ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) (T, error)) ([]T, error) {
+	results := make([]T, n)
+	errs := make([]error, n)
+	Run(ctx, n, concurrency, func(ctx context.Context, i int) {
+		results[i], errs[i] = fn(ctx, i)
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
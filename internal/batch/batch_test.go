@@ -0,0 +1,117 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchTestSuite struct {
+	suite.Suite
+}
+
+func TestBatch(t *testing.T) {
+	suite.Run(t, new(BatchTestSuite))
+}
+
+func (s *BatchTestSuite) TestRun() {
+	// Arrange
+	var running, maxRunning int32
+	results := make([]int, 20)
+
+	// Act
+	Run(context.Background(), len(results), 3, func(ctx context.Context, i int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		results[i] = i * i
+		atomic.AddInt32(&running, -1)
+	})
+
+	// Assert
+	s.LessOrEqual(maxRunning, int32(3))
+	for i, r := range results {
+		s.Equal(i*i, r)
+	}
+}
+
+func (s *BatchTestSuite) TestRunEmpty() {
+	called := false
+	Run(context.Background(), 0, 0, func(ctx context.Context, i int) { called = true })
+	s.False(called)
+}
+
+func (s *BatchTestSuite) TestUnique() {
+	s.Equal([]string{"a", "b", "c"}, Unique([]string{"a", "b", "", "a", "c", "b"}))
+	s.Empty(Unique(nil))
+}
+
+func (s *BatchTestSuite) TestChunk() {
+	tests := []struct {
+		name  string
+		items []string
+		size  int
+		want  [][]string
+	}{
+		{
+			name:  "should split into chunks of size",
+			items: []string{"a", "b", "c", "d", "e"},
+			size:  2,
+			want:  [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		{
+			name:  "should return nil for no items",
+			items: nil,
+			size:  2,
+			want:  nil,
+		},
+		{
+			name:  "should use default chunk size",
+			items: []string{"a", "b"},
+			size:  0,
+			want:  [][]string{{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, Chunk(tt.items, tt.size))
+		})
+	}
+}
+
+func (s *BatchTestSuite) TestMap() {
+	s.Run("should return results in index order", func() {
+		// Act
+		results, err := Map(context.Background(), 5, 2, func(ctx context.Context, i int) (int, error) {
+			return i * 10, nil
+		})
+
+		// Assert
+		s.NoError(err)
+		s.Equal([]int{0, 10, 20, 30, 40}, results)
+	})
+
+	s.Run("should return the error of the lowest failing index", func() {
+		// Act
+		results, err := Map(context.Background(), 5, 2, func(ctx context.Context, i int) (int, error) {
+			if i >= 3 {
+				return 0, fmt.Errorf("failed %d", i)
+			}
+			return i, nil
+		})
+
+		// Assert
+		s.EqualError(err, "failed 3")
+		s.Nil(results)
+	})
+}
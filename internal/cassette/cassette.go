@@ -0,0 +1,329 @@
+// Package cassette records HTTP exchanges to golden files and replays them
+// without network access. A Recorder implements httpclient.Recorder.
+//
+// Each distinct request is stored in its own JSON file, keyed by method, path
+// and query with sorted keys. A file holds one or more interactions: the final
+// attempt in RecordFinal mode, or every attempt of the last request with that
+// key in RecordAll mode. On replay, attempt n is served interaction n, or the
+// last interaction when there are fewer, so recorded retries replay in order.
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder records or replays exchanges.
+type Mode int
+
+const (
+	// Replay serves recorded responses and never touches the network.
+	// Requests without a recording fail with ErrNoRecording.
+	Replay Mode = iota
+
+	// RecordFinal sends requests over the network and records the response of
+	// the final attempt of each request.
+	RecordFinal
+
+	// RecordAll sends requests over the network and records the response of
+	// every attempt, including the ones that were retried.
+	RecordAll
+)
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case Replay:
+		return "replay"
+	case RecordFinal:
+		return "record-final"
+	case RecordAll:
+		return "record-all"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// ErrNoRecording is returned in Replay mode for requests that were never
+// recorded.
+var ErrNoRecording = errors.New("no recording for request")
+
+// DefaultScrubbedHeaders are removed from recorded headers by every Recorder.
+var DefaultScrubbedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie"}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// ScrubHeaders removes the named headers, in addition to
+// DefaultScrubbedHeaders, from recorded request and response headers.
+func ScrubHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		for _, n := range names {
+			r.scrub[http.CanonicalHeaderKey(n)] = true
+		}
+	}
+}
+
+// RedactWallets replaces the given wallet addresses in recorded paths,
+// queries and response bodies with a placeholder derived from the address
+// (see Placeholder). Requests are matched after redaction, so replaying with
+// either the real address or the placeholder finds the same recording.
+func RedactWallets(wallets ...string) Option {
+	return func(r *Recorder) {
+		for _, w := range wallets {
+			if w != "" {
+				r.wallets = append(r.wallets, w)
+			}
+		}
+	}
+}
+
+// Placeholder returns the value that RedactWallets substitutes for wallet.
+// It is stable across runs so recordings and test expectations can refer to
+// it.
+func Placeholder(wallet string) string {
+	sum := sha256.Sum256([]byte(wallet))
+	return "REDACTED-" + hex.EncodeToString(sum[:6])
+}
+
+// Recorder records or replays the exchanges of an httpclient.Client. It is
+// safe for concurrent use.
+type Recorder struct {
+	mode    Mode
+	dir     string
+	scrub   map[string]bool
+	wallets []string
+
+	mu    sync.Mutex
+	cache map[string]*Cassette
+}
+
+// New creates a Recorder storing its files in dir. In record modes dir is
+// created on the first write.
+func New(mode Mode, dir string, opts ...Option) *Recorder {
+	r := &Recorder{
+		mode:  mode,
+		dir:   dir,
+		scrub: make(map[string]bool),
+		cache: make(map[string]*Cassette),
+	}
+	for _, h := range DefaultScrubbedHeaders {
+		r.scrub[h] = true
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	// Redact longer addresses first in case one contains another.
+	sort.Slice(r.wallets, func(i, j int) bool { return len(r.wallets[i]) > len(r.wallets[j]) })
+	return r
+}
+
+// Mode returns the mode of the Recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Cassette is the content of a recording file.
+type Cassette struct {
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+
+	// Path is the redacted request path.
+	Path string `json:"path"`
+
+	// Query is the redacted query string with sorted keys.
+	Query string `json:"query,omitempty"`
+
+	// RequestHeader holds the scrubbed request headers of the last attempt.
+	RequestHeader http.Header `json:"request_header,omitempty"`
+
+	// Interactions holds the recorded responses in attempt order.
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded response.
+type Interaction struct {
+	// Attempt is the attempt number of the request, starting at 1.
+	Attempt int `json:"attempt"`
+
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+
+	// Header holds the scrubbed response headers.
+	Header http.Header `json:"header,omitempty"`
+
+	// Body is the redacted response body when it is valid JSON.
+	Body json.RawMessage `json:"body,omitempty"`
+
+	// BodyText is the redacted response body when it is not valid JSON.
+	BodyText string `json:"body_text,omitempty"`
+}
+
+func (in *Interaction) body() []byte {
+	if in.Body != nil {
+		return in.Body
+	}
+	return []byte(in.BodyText)
+}
+
+// RoundTrip implements httpclient.Recorder.
+func (r *Recorder) RoundTrip(req *http.Request, attempt int, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	// Encode sorts the query by key and keeps the order of repeated values.
+	method, path, query := req.Method, r.redact(req.URL.EscapedPath()), r.redact(req.URL.Query().Encode())
+	name := fileName(method, path, query)
+
+	if r.mode == Replay {
+		c, err := r.load(name)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil || len(c.Interactions) == 0 {
+			return nil, fmt.Errorf("%w: %s %s?%s", ErrNoRecording, method, path, query)
+		}
+		in := c.Interactions[min(attempt, len(c.Interactions))-1]
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			StatusCode:    in.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(in.body())),
+			ContentLength: int64(len(in.body())),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := next(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	in := Interaction{Attempt: attempt, Status: resp.StatusCode, Header: r.scrubHeader(resp.Header)}
+	if redacted := []byte(r.redact(string(body))); json.Valid(redacted) {
+		in.Body = redacted
+	} else {
+		in.BodyText = string(redacted)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.cache[name]
+	if c == nil || r.mode == RecordFinal || attempt == 1 {
+		c = &Cassette{Method: method, Path: path, Query: query}
+		r.cache[name] = c
+	}
+	c.RequestHeader = r.scrubHeader(req.Header)
+	c.Interactions = append(c.Interactions, in)
+	if err := r.write(name, c); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// load returns the cassette stored under name, or nil if there is none.
+func (r *Recorder) load(name string) (*Cassette, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.cache[name]; ok {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		r.cache[name] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", name, err)
+	}
+	r.cache[name] = &c
+	return &c, nil
+}
+
+func (r *Recorder) write(name string, c *Cassette) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return fmt.Errorf("encoding cassette: %w", err)
+	}
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return fmt.Errorf("creating cassette directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, name), buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("writing cassette: %w", err)
+	}
+	return nil
+}
+
+func (r *Recorder) redact(s string) string {
+	for _, w := range r.wallets {
+		s = strings.ReplaceAll(s, w, Placeholder(w))
+	}
+	return s
+}
+
+func (r *Recorder) scrubHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		if !r.scrub[http.CanonicalHeaderKey(k)] {
+			out[k] = append([]string(nil), v...)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// fileName returns the file storing the recording of a request. It starts
+// with a readable form of the method and path and ends with a hash of the full
+// key, so distinct queries never share a file.
+func fileName(method, path, query string) string {
+	sum := sha256.Sum256([]byte(method + " " + path + "?" + query))
+	var b strings.Builder
+	b.WriteString(method)
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if seg == "" {
+			continue
+		}
+		b.WriteByte('_')
+		for _, c := range seg {
+			switch {
+			case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-':
+				b.WriteRune(c)
+			default:
+				b.WriteByte('_')
+			}
+		}
+	}
+	if b.Len() > 120 {
+		s := b.String()[:120]
+		b.Reset()
+		b.WriteString(s)
+	}
+	return b.String() + "_" + hex.EncodeToString(sum[:6]) + ".json"
+}
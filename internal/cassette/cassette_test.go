@@ -0,0 +1,154 @@
+package cassette_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/internal/cassette"
+	"github.com/ua1984/meteora-go/internal/httpclient"
+)
+
+const wallet = "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"
+
+type CassetteTestSuite struct {
+	suite.Suite
+	dir   string
+	calls atomic.Int32
+	srv   *httptest.Server
+}
+
+func TestCassette(t *testing.T) {
+	suite.Run(t, new(CassetteTestSuite))
+}
+
+func (s *CassetteTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.calls.Store(0)
+	// The first request of each test fails with 503, later ones succeed.
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Api-Key", "secret")
+		w.Header().Set("Content-Type", "application/json")
+		if s.calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("unavailable"))
+			return
+		}
+		w.Write([]byte(`{"owner":"` + wallet + `","path":"` + r.URL.Path + `"}`))
+	}))
+}
+
+func (s *CassetteTestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func (s *CassetteTestSuite) client(baseURL string, rec *cassette.Recorder) *httpclient.Client {
+	c := httpclient.NewWithRetryConfig(baseURL, nil, 2, time.Millisecond, time.Millisecond)
+	c.SetRecorder(rec)
+	return c
+}
+
+func (s *CassetteTestSuite) readCassette() string {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	s.Require().NoError(err)
+	s.Require().Len(files, 1)
+	data, err := os.ReadFile(files[0])
+	s.Require().NoError(err)
+	return string(data)
+}
+
+func (s *CassetteTestSuite) TestRecordAndReplay() {
+	tests := []struct {
+		name             string
+		mode             cassette.Mode
+		wantInteractions int
+		wantReplayErr    bool
+	}{
+		{
+			name:             "should record only the final attempt",
+			mode:             cassette.RecordFinal,
+			wantInteractions: 1,
+		},
+		{
+			name:             "should record every attempt and replay them in order",
+			mode:             cassette.RecordAll,
+			wantInteractions: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.SetupTest()
+			defer s.TearDownTest()
+
+			// Arrange
+			query := map[string][]string{"b": {"2"}, "a": {"1"}}
+			var recorded, replayed map[string]string
+
+			// Act
+			err := s.client(s.srv.URL, cassette.New(tt.mode, s.dir)).Get(context.Background(), "/pools", query, &recorded)
+			s.Require().NoError(err)
+			content := s.readCassette()
+
+			replay := s.client("http://127.0.0.1:1", cassette.New(cassette.Replay, s.dir))
+			replayErr := replay.Get(context.Background(), "/pools", query, &replayed)
+
+			// Assert
+			s.Equal(int32(2), s.calls.Load())
+			s.Equal(tt.wantInteractions, strings.Count(content, `"attempt"`))
+			s.Contains(content, `"query": "a=1&b=2"`)
+			s.NoError(replayErr)
+			s.Equal(recorded, replayed)
+		})
+	}
+}
+
+func (s *CassetteTestSuite) TestReplayMissingRecording() {
+	// Arrange
+	rec := cassette.New(cassette.Replay, s.dir)
+	c := httpclient.NewWithRetryConfig(s.srv.URL, nil, 5, time.Second, time.Second)
+	c.SetRecorder(rec)
+
+	// Act
+	start := time.Now()
+	err := c.Get(context.Background(), "/missing", nil, nil)
+
+	// Assert
+	s.ErrorIs(err, cassette.ErrNoRecording)
+	s.Less(time.Since(start), time.Second)
+	s.Equal(int32(0), s.calls.Load())
+}
+
+func (s *CassetteTestSuite) TestScrubAndRedact() {
+	// Arrange
+	s.calls.Store(1)
+	opts := []cassette.Option{cassette.ScrubHeaders("x-api-key"), cassette.RedactWallets(wallet)}
+	var recorded, replayed map[string]string
+
+	// Act
+	err := s.client(s.srv.URL, cassette.New(cassette.RecordFinal, s.dir, opts...)).Get(context.Background(), "/wallets/"+wallet, nil, &recorded)
+	s.Require().NoError(err)
+	content := s.readCassette()
+
+	replay := s.client("http://127.0.0.1:1", cassette.New(cassette.Replay, s.dir, opts...))
+	realErr := replay.Get(context.Background(), "/wallets/"+wallet, nil, &replayed)
+	placeholderErr := replay.Get(context.Background(), "/wallets/"+cassette.Placeholder(wallet), nil, nil)
+
+	// Assert
+	s.Equal(wallet, recorded["owner"])
+	s.NotContains(content, wallet)
+	s.NotContains(content, "secret")
+	s.Contains(content, "Content-Type")
+	s.Contains(content, cassette.Placeholder(wallet))
+	s.NoError(realErr)
+	s.NoError(placeholderErr)
+	s.Equal(cassette.Placeholder(wallet), replayed["owner"])
+}
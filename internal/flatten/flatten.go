@@ -0,0 +1,210 @@
+// Package flatten maps structs to flat columns named after their JSON tags.
+// Nested structs become dotted names such as "volume.24h"; embedded structs
+// without a tag are promoted. Types that marshal themselves (decimal.Decimal,
+// time.Time) are single columns.
+package flatten
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Column is a flattened field.
+type Column struct {
+	// Name is the dotted JSON name of the field, or "value" for non-struct
+	// records.
+	Name string
+
+	// Type is the type of the field with pointers removed.
+	Type reflect.Type
+
+	// path holds the field indexes from the record to the field.
+	path []int
+}
+
+// Columns returns the flattened columns of records of type t. Non-struct
+// records have a single "value" column.
+func Columns(t reflect.Type) []Column {
+	t = deref(t)
+	if t.Kind() != reflect.Struct || IsLeaf(t) {
+		return []Column{{Name: "value", Type: t}}
+	}
+	return structColumns(t, "", nil)
+}
+
+func structColumns(t reflect.Type, prefix string, path []int) []Column {
+	var cols []Column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		ft := deref(f.Type)
+		fieldPath := append(append([]int(nil), path...), i)
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			cols = append(cols, structColumns(ft, prefix, fieldPath)...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if ft.Kind() == reflect.Struct && !IsLeaf(ft) {
+			cols = append(cols, structColumns(ft, prefix+name+".", fieldPath)...)
+			continue
+		}
+		cols = append(cols, Column{Name: prefix + name, Type: ft, path: fieldPath})
+	}
+	return cols
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// IsLeaf reports whether t formats itself and must not be flattened.
+func IsLeaf(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Implements(jsonMarshaler) || t.Implements(textMarshaler) ||
+		pt.Implements(jsonMarshaler) || pt.Implements(textMarshaler)
+}
+
+// Select returns the columns named in names, in that order. A name also
+// selects every column below it, so "volume" selects "volume.24h" and its
+// siblings. All columns are returned if names is empty.
+func Select(cols []Column, names []string) ([]Column, error) {
+	if len(names) == 0 {
+		return cols, nil
+	}
+	var selected []Column
+	for _, n := range names {
+		found := false
+		for _, c := range cols {
+			if c.Name == n || strings.HasPrefix(c.Name, n+".") {
+				selected = append(selected, c)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", n, strings.Join(Names(cols), ", "))
+		}
+	}
+	return selected, nil
+}
+
+// Names returns the names of cols.
+func Names(cols []Column) []string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Value returns the field of c in rec with pointers removed. It returns false
+// if the field or a pointer on the way to it is nil. Nil slices and maps are
+// also reported as missing, matching their JSON encoding as null.
+func (c Column) Value(rec reflect.Value) (reflect.Value, bool) {
+	v := rec
+	for _, i := range c.path {
+		if v = indirect(v); !v.IsValid() {
+			return v, false
+		}
+		v = v.Field(i)
+	}
+	v = indirect(v)
+	if !v.IsValid() {
+		return v, false
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v, !v.IsNil()
+	}
+	return v, true
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// Format returns the text form of the field of c in rec, or "" if it is nil.
+func (c Column) Format(rec reflect.Value) string {
+	v, ok := c.Value(rec)
+	if !ok {
+		return ""
+	}
+	return Format(v)
+}
+
+// Format returns the text form of v. Scalars use their Go formatting, slices
+// of scalars are comma-separated and other values are JSON.
+func Format(v reflect.Value) string {
+	if v = indirect(v); !v.IsValid() {
+		return ""
+	}
+	if IsLeaf(v.Type()) {
+		return marshalString(v.Interface())
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			e := v.Index(i)
+			for e.Kind() == reflect.Pointer && !e.IsNil() {
+				e = e.Elem()
+			}
+			switch e.Kind() {
+			case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Interface, reflect.Pointer:
+				return marshalString(v.Interface())
+			}
+			parts[i] = Format(e)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return marshalString(v.Interface())
+	}
+}
+
+// marshalString formats v as JSON, without the quotes of JSON strings.
+func marshalString(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return s
+	}
+	return string(data)
+}
@@ -3,6 +3,7 @@ package httpclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -24,6 +25,8 @@ type Client struct {
 	maxRetries int
 	baseDelay  time.Duration
 	maxDelay   time.Duration
+	limiter    *rateLimiter
+	recorder   Recorder
 }
 
 // New creates a new Client with the given base URL and default retry configuration.
@@ -70,6 +73,13 @@ func NewWithRetryConfig(baseURL string, httpClient *http.Client, maxRetries int,
 	}
 }
 
+// SetRateLimit limits the client to requestsPerSecond requests per second across
+// all goroutines, including retries. A value of zero or less disables the limit.
+// It must be called before the client is used.
+func (c *Client) SetRateLimit(requestsPerSecond float64) {
+	c.limiter = newRateLimiter(requestsPerSecond)
+}
+
 // Get performs a GET request and decodes the JSON response into result.
 func (c *Client) Get(ctx context.Context, path string, query url.Values, result any) error {
 	u, err := url.JoinPath(c.baseURL, path)
@@ -153,12 +163,21 @@ func (c *Client) do(req *http.Request, result any) error {
 		default:
 		}
 
+		if err := c.limiter.wait(req.Context()); err != nil {
+			return fmt.Errorf("context canceled while waiting for rate limit: %w", err)
+		}
+
 		// Clone the request to avoid modifying the original request.
 		r := req.Clone(req.Context())
 		r.Header.Set("Accept", "application/json")
 		r.Header.Set("User-Agent", "Meteora Go SDK/1.0.0")
-		resp, err := c.httpClient.Do(r)
+		resp, err := c.roundTrip(r, attempt)
 		if err != nil {
+			var recErr *recorderError
+			if errors.As(err, &recErr) {
+				return recErr
+			}
+
 			lastErr = fmt.Errorf("executing request (attempt %d): %w", attempt, err)
 			if c.shouldRetry(err, 0) && attempt <= c.maxRetries {
 				delay := c.calculateDelay(attempt)
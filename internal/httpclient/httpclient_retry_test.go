@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -165,4 +167,55 @@ func (s *HTTPClientRetryTestSuite) TestDefaultRetryConfiguration() {
 	s.Equal(DefaultMaxRetries, client.maxRetries)
 	s.Equal(DefaultBaseDelay, client.baseDelay)
 	s.Equal(DefaultMaxDelay, client.maxDelay)
-}
\ No newline at end of file
+}
+
+func (s *HTTPClientRetryTestSuite) TestRateLimit() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, nil)
+	client.SetRateLimit(50) // one request every 20ms
+
+	// Freeze the clock and record the delays instead of sleeping.
+	var mu sync.Mutex
+	var delays []time.Duration
+	now := time.Unix(1_700_000_000, 0)
+	client.limiter.now = func() time.Time { return now }
+	client.limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delays = append(delays, d)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.NoError(client.Get(context.TODO(), "/test", nil, nil))
+		}()
+	}
+	wg.Wait()
+
+	// The first request starts immediately; the others wait for their slot.
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+	s.Equal([]time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond, 80 * time.Millisecond}, delays)
+}
+
+func (s *HTTPClientRetryTestSuite) TestRateLimitContextCancellation() {
+	client := New("http://example.com", nil)
+	client.SetRateLimit(0.1) // one request every 10s
+	client.limiter.next = time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := client.Get(ctx, "/test", nil, nil)
+
+	s.Error(err)
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
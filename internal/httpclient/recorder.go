@@ -0,0 +1,50 @@
+package httpclient
+
+import "net/http"
+
+// Recorder intercepts the attempts made by a Client, for example to record
+// exchanges or to replay previously recorded ones.
+type Recorder interface {
+	// RoundTrip performs attempt (starting at 1) of r, usually by calling next
+	// to send it over the network. Errors returned by next are retried like
+	// any other network error; other errors end the request immediately.
+	RoundTrip(r *http.Request, attempt int, next func(*http.Request) (*http.Response, error)) (*http.Response, error)
+}
+
+// SetRecorder routes every attempt through rec. It must be called before the
+// client is used.
+func (c *Client) SetRecorder(rec Recorder) {
+	c.recorder = rec
+}
+
+// recorderError is an error produced by the Recorder itself rather than by
+// the network. It is never retried.
+type recorderError struct {
+	err error
+}
+
+func (e *recorderError) Error() string {
+	return "recorder: " + e.err.Error()
+}
+
+func (e *recorderError) Unwrap() error {
+	return e.err
+}
+
+// roundTrip sends attempt of r through the recorder, if any.
+func (c *Client) roundTrip(r *http.Request, attempt int) (*http.Response, error) {
+	if c.recorder == nil {
+		return c.httpClient.Do(r)
+	}
+
+	var nextErr error
+	resp, err := c.recorder.RoundTrip(r, attempt, func(r *http.Request) (*http.Response, error) {
+		resp, err := c.httpClient.Do(r)
+		nextErr = err
+		return resp, err
+	})
+	if err != nil && err != nextErr {
+		return nil, &recorderError{err: err}
+	}
+	return resp, err
+}
@@ -3,11 +3,13 @@ package meteora
 
 import (
 	"net/http"
+	"path/filepath"
 
 	"github.com/ua1984/meteora-go/dammv1"
 	"github.com/ua1984/meteora-go/dammv2"
 	"github.com/ua1984/meteora-go/dlmm"
 	"github.com/ua1984/meteora-go/dynamicvault"
+	"github.com/ua1984/meteora-go/internal/cassette"
 	"github.com/ua1984/meteora-go/internal/httpclient"
 	"github.com/ua1984/meteora-go/stake2earn"
 )
@@ -20,13 +22,25 @@ const (
 	defaultDynamicVaultBaseURL = "https://merv2-api.meteora.ag"
 )
 
+// Documented per-service rate limits in requests per second. Stake2Earn and
+// Dynamic Vault have no published limit.
+const (
+	dlmmRateLimit   = 30
+	dammv2RateLimit = 10
+	dammv1RateLimit = 10
+)
+
 // Client provides access to all Meteora API services.
+//
+// The fields are interfaces implemented by the service clients created by New.
+// Tests can build a Client directly and replace any of them with a fake, such
+// as the ones in the meteoratest package.
 type Client struct {
-	DLMM         *dlmm.Client
-	DAMMv2       *dammv2.Client
-	DAMMv1       *dammv1.Client
-	Stake2Earn   *stake2earn.Client
-	DynamicVault *dynamicvault.Client
+	DLMM         dlmm.API
+	DAMMv2       dammv2.API
+	DAMMv1       dammv1.API
+	Stake2Earn   stake2earn.API
+	DynamicVault dynamicvault.API
 }
 
 // Option configures the Client.
@@ -39,6 +53,15 @@ type options struct {
 	dammv1BaseURL       string
 	stake2earnBaseURL   string
 	dynamicVaultBaseURL string
+	rateLimit           bool
+	chunkSize           int
+	recorder            *recorderConfig
+}
+
+type recorderConfig struct {
+	mode RecorderMode
+	dir  string
+	opts []RecorderOption
 }
 
 // WithHTTPClient sets a custom http.Client for all API requests.
@@ -71,6 +94,32 @@ func WithDynamicVaultBaseURL(u string) Option {
 	return func(o *options) { o.dynamicVaultBaseURL = u }
 }
 
+// WithRateLimit enables client-side rate limiting at the documented API
+// limits: 30 requests per second to DLMM and 10 per second to DAMM v2 and
+// DAMM v1. The limit is shared by all goroutines using the client and also
+// applies to retries. Requests are not limited by default.
+func WithRateLimit() Option {
+	return func(o *options) { o.rateLimit = true }
+}
+
+// WithChunkSize sets the maximum number of values sent per request for
+// multi-value query parameters of the DAMM v1 and Stake2Earn clients. Longer
+// lists are split into several concurrent requests and the responses merged.
+// Default: 100, which is also the maximum for Stake2Earn.
+func WithChunkSize(n int) Option {
+	return func(o *options) { o.chunkSize = n }
+}
+
+// WithRecorder records API exchanges to golden files in dir, or replays them
+// without network access, depending on mode. Each service uses its own
+// subdirectory (dlmm, dammv2, dammv1, stake2earn and dynamicvault) and each
+// request its own file, keyed by method, path and query with sorted keys.
+// Authorization and cookie headers are never recorded; ScrubHeaders and
+// RedactWallets remove more. WithRateLimit has no effect in Replay mode.
+func WithRecorder(mode RecorderMode, dir string, opts ...RecorderOption) Option {
+	return func(o *options) { o.recorder = &recorderConfig{mode: mode, dir: dir, opts: opts} }
+}
+
 // New creates a new Meteora API client with the given options.
 func New(opts ...Option) *Client {
 	o := &options{
@@ -84,13 +133,28 @@ func New(opts ...Option) *Client {
 		opt(o)
 	}
 
-	hc := o.httpClient
+	replaying := o.recorder != nil && o.recorder.mode == Replay
+	newHTTP := func(service, baseURL string, requestsPerSecond float64) *httpclient.Client {
+		c := httpclient.New(baseURL, o.httpClient)
+		if o.rateLimit && !replaying {
+			c.SetRateLimit(requestsPerSecond)
+		}
+		if o.recorder != nil {
+			c.SetRecorder(cassette.New(o.recorder.mode, filepath.Join(o.recorder.dir, service), o.recorder.opts...))
+		}
+		return c
+	}
+
+	dammv1Client := dammv1.NewClient(newHTTP("dammv1", o.dammv1BaseURL, dammv1RateLimit))
+	dammv1Client.SetChunkSize(o.chunkSize)
+	stake2earnClient := stake2earn.NewClient(newHTTP("stake2earn", o.stake2earnBaseURL, 0))
+	stake2earnClient.SetChunkSize(o.chunkSize)
 
 	return &Client{
-		DLMM:         dlmm.NewClient(httpclient.New(o.dlmmBaseURL, hc)),
-		DAMMv2:       dammv2.NewClient(httpclient.New(o.dammv2BaseURL, hc)),
-		DAMMv1:       dammv1.NewClient(httpclient.New(o.dammv1BaseURL, hc)),
-		Stake2Earn:   stake2earn.NewClient(httpclient.New(o.stake2earnBaseURL, hc)),
-		DynamicVault: dynamicvault.NewClient(httpclient.New(o.dynamicVaultBaseURL, hc)),
+		DLMM:         dlmm.NewClient(newHTTP("dlmm", o.dlmmBaseURL, dlmmRateLimit)),
+		DAMMv2:       dammv2.NewClient(newHTTP("dammv2", o.dammv2BaseURL, dammv2RateLimit)),
+		DAMMv1:       dammv1Client,
+		Stake2Earn:   stake2earnClient,
+		DynamicVault: dynamicvault.NewClient(newHTTP("dynamicvault", o.dynamicVaultBaseURL, 0)),
 	}
 }
@@ -0,0 +1,291 @@
+package meteoratest
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ua1984/meteora-go/dammv1"
+)
+
+const (
+	defaultSearchSize = 10
+	maxSearchSize     = 1000
+	defaultFarmSize   = 10
+)
+
+var dammv1Routes = []route{
+	{http.MethodGet, "/pools", func(d *Data, q url.Values, _ []string) (any, error) {
+		pools, err := dammv1FilterPools(d.DAMMv1.Pools, q)
+		if err != nil {
+			return nil, err
+		}
+		if len(q["address"]) > 0 {
+			var matched []dammv1.Pool
+			for _, p := range pools {
+				if contains(q["address"], p.PoolAddress) {
+					matched = append(matched, p)
+				}
+			}
+			pools = matched
+		}
+		return append([]dammv1.Pool{}, pools...), nil
+	}},
+	{http.MethodGet, "/pools/search", dammv1SearchPools},
+	{http.MethodGet, "/pools-metrics", func(d *Data, _ url.Values, _ []string) (any, error) {
+		return dammv1PoolMetrics(&d.DAMMv1), nil
+	}},
+	{http.MethodGet, "/pool-configs", func(d *Data, _ url.Values, _ []string) (any, error) {
+		return append([]dammv1.PoolConfig{}, d.DAMMv1.PoolConfigs...), nil
+	}},
+	{http.MethodGet, "/fee-config/*", func(d *Data, _ url.Values, args []string) (any, error) {
+		configs, ok := d.DAMMv1.FeeConfigs[args[0]]
+		if !ok {
+			return nil, errNotFound("fee config %s", args[0])
+		}
+		return configs, nil
+	}},
+	{http.MethodGet, "/farm", func(d *Data, q url.Values, _ []string) (any, error) {
+		page, err := intParam(q, "page", 1, 1, math.MaxInt)
+		if err != nil {
+			return nil, err
+		}
+		size, err := intParam(q, "size", defaultFarmSize, 1, maxSearchSize)
+		if err != nil {
+			return nil, err
+		}
+		var farms []dammv1.Pool
+		for _, p := range d.DAMMv1.Pools {
+			if p.FarmingPool != nil {
+				farms = append(farms, p)
+			}
+		}
+		start, end := pageBounds(len(farms), page-1, size)
+		return append([]dammv1.Pool{}, farms[start:end]...), nil
+	}},
+	{http.MethodGet, "/alpha-vault", func(d *Data, q url.Values, _ []string) (any, error) {
+		vaults := []dammv1.AlphaVault{}
+		for _, v := range d.DAMMv1.AlphaVaults {
+			if len(q["vault_address"]) > 0 && !contains(q["vault_address"], v.VaultAddress) {
+				continue
+			}
+			if len(q["pool_address"]) > 0 && !contains(q["pool_address"], v.PoolAddress) {
+				continue
+			}
+			if len(q["base_mint"]) > 0 && !contains(q["base_mint"], v.BaseMint) {
+				continue
+			}
+			vaults = append(vaults, v)
+		}
+		return vaults, nil
+	}},
+	{http.MethodGet, "/alpha-vault-configs", func(d *Data, _ url.Values, _ []string) (any, error) {
+		return d.DAMMv1.AlphaVaultConfigs, nil
+	}},
+	{http.MethodPost, "/pools_by_a_vault_lp", func(d *Data, q url.Values, _ []string) (any, error) {
+		if q.Get("address") == "" {
+			return nil, errBadRequest("address is required")
+		}
+		pools := []dammv1.Pool{}
+		for _, p := range d.DAMMv1.Pools {
+			if contains(p.VaultLPs, q.Get("address")) {
+				pools = append(pools, p)
+			}
+		}
+		return pools, nil
+	}},
+}
+
+// dammv1FilterPools applies the filters shared by /pools and /pools/search.
+// The launchpad filter is accepted but not applied because dammv1.Pool does
+// not carry a launchpad.
+func dammv1FilterPools(pools []dammv1.Pool, q url.Values) ([]dammv1.Pool, error) {
+	unknown, hasUnknown, err := boolParam(q, "unknown")
+	if err != nil {
+		return nil, err
+	}
+	monitoring, hasMonitoring, err := boolParam(q, "is_monitoring")
+	if err != nil {
+		return nil, err
+	}
+	hideLowAPR, _, err := boolParam(q, "hide_low_apr")
+	if err != nil {
+		return nil, err
+	}
+	var minTVL float64
+	if s := q.Get("hide_low_tvl"); s != "" {
+		if minTVL, err = strconv.ParseFloat(s, 64); err != nil {
+			return nil, errBadRequest("invalid hide_low_tvl %q", s)
+		}
+	}
+
+	var matched []dammv1.Pool
+	for _, p := range pools {
+		switch {
+		case hasUnknown && !unknown && p.Unknown:
+		case q.Has("pool_type") && p.PoolType != q.Get("pool_type"):
+		case hasMonitoring && p.IsMonitoring != monitoring:
+		case minTVL > 0 && parseFloat(p.PoolTVL) < minTVL:
+		case hideLowAPR && p.APR <= 0:
+		default:
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
+// dammv1SearchPools serves /pools/search. Results are sorted by sort_key
+// ("tvl" by default, "volume", "fee_tvl_ratio" or "l_m" for the farming APY)
+// with pools_to_top moved to the front, and paginated with the 0-based page.
+// include_pool_token_pairs entries are two mints joined by "-".
+func dammv1SearchPools(d *Data, q url.Values, _ []string) (any, error) {
+	page, err := intParam(q, "page", 0, 0, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	size, err := intParam(q, "size", defaultSearchSize, 1, maxSearchSize)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := dammv1FilterPools(d.DAMMv1.Pools, q)
+	if err != nil {
+		return nil, err
+	}
+
+	var key func(p dammv1.Pool) float64
+	switch q.Get("sort_key") {
+	case "", "tvl":
+		key = func(p dammv1.Pool) float64 { return parseFloat(p.PoolTVL) }
+	case "volume":
+		key = func(p dammv1.Pool) float64 { return p.TradingVolume }
+	case "fee_tvl_ratio":
+		key = func(p dammv1.Pool) float64 {
+			if tvl := parseFloat(p.PoolTVL); tvl > 0 {
+				return p.FeeVolume / tvl
+			}
+			return 0
+		}
+	case "l_m":
+		key = func(p dammv1.Pool) float64 { return parseFloat(p.FarmingAPY) }
+	default:
+		return nil, errBadRequest("invalid sort_key %q", q.Get("sort_key"))
+	}
+	asc := false
+	switch q.Get("order_by") {
+	case "", "desc":
+	case "asc":
+		asc = true
+	default:
+		return nil, errBadRequest("invalid order_by %q", q.Get("order_by"))
+	}
+
+	filter := strings.ToLower(q.Get("filter"))
+	var matched []dammv1.Pool
+	for _, p := range pools {
+		if filter != "" && !dammv1MatchesFilter(p, filter) {
+			continue
+		}
+		if mints := q["include_token_mints"]; len(mints) > 0 && !dammv1HasAnyMint(p, mints) {
+			continue
+		}
+		if pairs := q["include_pool_token_pairs"]; len(pairs) > 0 && !dammv1HasAnyPair(p, pairs) {
+			continue
+		}
+		matched = append(matched, p)
+	}
+
+	top := q["pools_to_top"]
+	sort.SliceStable(matched, func(a, b int) bool {
+		ta, tb := contains(top, matched[a].PoolAddress), contains(top, matched[b].PoolAddress)
+		if ta != tb {
+			return ta
+		}
+		if asc {
+			return key(matched[a]) < key(matched[b])
+		}
+		return key(matched[a]) > key(matched[b])
+	})
+
+	start, end := pageBounds(len(matched), page, size)
+	return &dammv1.SearchResult{
+		Data:       append([]dammv1.Pool{}, matched[start:end]...),
+		Page:       page,
+		TotalCount: len(matched),
+	}, nil
+}
+
+func dammv1MatchesFilter(p dammv1.Pool, filter string) bool {
+	for _, s := range append([]string{p.PoolName, p.PoolAddress}, p.PoolTokenMints...) {
+		if strings.Contains(strings.ToLower(s), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+func dammv1HasAnyMint(p dammv1.Pool, mints []string) bool {
+	for _, m := range p.PoolTokenMints {
+		if contains(mints, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func dammv1HasAnyPair(p dammv1.Pool, pairs []string) bool {
+	for _, pair := range pairs {
+		a, b, _ := strings.Cut(pair, "-")
+		if contains(p.PoolTokenMints, a) && contains(p.PoolTokenMints, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// dammv1PoolMetrics returns the seeded metrics, or metrics computed from the
+// pools by pool type. Daily figures use TradingVolume and FeeVolume, totals
+// use the accumulated volumes.
+func dammv1PoolMetrics(d *DAMMv1Data) *dammv1.PoolMetrics {
+	if d.PoolMetrics != nil {
+		return d.PoolMetrics
+	}
+
+	m := &dammv1.PoolMetrics{}
+	for _, p := range d.Pools {
+		var breakdowns []*dammv1.MetricsBreakdown
+		switch p.PoolType {
+		case "dynamic":
+			breakdowns = append(breakdowns, &m.DynamicAMM)
+		case "multitoken":
+			breakdowns = append(breakdowns, &m.Multitokens)
+		}
+		if p.IsLST {
+			breakdowns = append(breakdowns, &m.LST)
+		}
+		if p.FarmingPool != nil {
+			breakdowns = append(breakdowns, &m.Farms)
+		}
+		for _, b := range breakdowns {
+			b.TVL += parseFloat(p.PoolTVL)
+			b.DailyVolume += p.TradingVolume
+			b.TotalVolume += parseFloat(p.AccumulatedTradingVolume)
+			b.DailyFee += p.FeeVolume
+			b.TotalFee += parseFloat(p.AccumulatedFeeVolume)
+		}
+	}
+
+	m.DynamicAMMTVL = m.DynamicAMM.TVL
+	m.DynamicAMMDailyVolume = m.DynamicAMM.DailyVolume
+	m.DynamicAMMTotalVolume = m.DynamicAMM.TotalVolume
+	m.DynamicAMMDailyFee = m.DynamicAMM.DailyFee
+	m.DynamicAMMTotalFee = m.DynamicAMM.TotalFee
+	m.MultitokensTVL = m.Multitokens.TVL
+	m.MultitokensDailyVolume = m.Multitokens.DailyVolume
+	m.MultitokensTotalVolume = m.Multitokens.TotalVolume
+	m.MultitokensDailyFee = m.Multitokens.DailyFee
+	m.MultitokensTotalFee = m.Multitokens.TotalFee
+	return m
+}
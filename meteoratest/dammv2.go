@@ -0,0 +1,64 @@
+package meteoratest
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/ua1984/meteora-go/dammv2"
+)
+
+var dammv2Routes = []route{
+	{http.MethodGet, "/pools", func(d *Data, q url.Values, _ []string) (any, error) {
+		return listPools(d.DAMMv2.Pools, q)
+	}},
+	{http.MethodGet, "/pools/groups", func(d *Data, q url.Values, _ []string) (any, error) {
+		return listGroups(d.DAMMv2.Pools, q)
+	}},
+	{http.MethodGet, "/pools/groups/*", func(d *Data, q url.Values, args []string) (any, error) {
+		return getGroup(d.DAMMv2.Pools, args[0], q)
+	}},
+	{http.MethodGet, "/pools/*", func(d *Data, _ url.Values, args []string) (any, error) {
+		return getPool(d.DAMMv2.Pools, args[0])
+	}},
+	{http.MethodGet, "/pools/*/ohlcv", func(d *Data, q url.Values, args []string) (any, error) {
+		return timeSeries(d.DAMMv2.Pools, args[0], d.DAMMv2.OHLCV[args[0]], func(c dammv2.OHLCV) int64 { return c.Timestamp }, q)
+	}},
+	{http.MethodGet, "/pools/*/volume/history", func(d *Data, q url.Values, args []string) (any, error) {
+		return timeSeries(d.DAMMv2.Pools, args[0], d.DAMMv2.VolumeHistory[args[0]], func(v dammv2.VolumeHistory) int64 { return v.Timestamp }, q)
+	}},
+	{http.MethodGet, "/stats/protocol_metrics", func(d *Data, _ url.Values, _ []string) (any, error) {
+		return dammv2ProtocolMetrics(&d.DAMMv2), nil
+	}},
+	{http.MethodGet, "/wallets/*/closed_positions", func(d *Data, q url.Values, args []string) (any, error) {
+		return closedPositions(d.DAMMv2.ClosedPositions[args[0]],
+			func(p dammv2.ClosedPosition) int64 { return p.ClosedAt },
+			func(p dammv2.ClosedPosition) string { return p.PoolAddress }, q)
+	}},
+	{http.MethodGet, "/wallets/*/open_positions", func(d *Data, q url.Values, args []string) (any, error) {
+		resp := &dammv2.OpenPositionsResponse{Data: []dammv2.PositionsByPool{}}
+		for _, p := range d.DAMMv2.OpenPositions[args[0]] {
+			if q.Has("pool") && p.PoolAddress != q.Get("pool") {
+				continue
+			}
+			resp.Data = append(resp.Data, p)
+			resp.TotalPools++
+			resp.TotalPositions += int64(len(p.Positions))
+		}
+		return resp, nil
+	}},
+}
+
+// dammv2ProtocolMetrics returns the seeded metrics, or metrics computed from
+// the pools. Lifetime totals are not tracked per pool and stay zero.
+func dammv2ProtocolMetrics(d *DAMMv2Data) *dammv2.ProtocolMetrics {
+	if d.ProtocolMetrics != nil {
+		return d.ProtocolMetrics
+	}
+	m := &dammv2.ProtocolMetrics{TotalPools: len(d.Pools)}
+	for _, p := range d.Pools {
+		m.TotalTVL += p.TVL
+		m.Volume24h += p.Volume.Hour24
+		m.Fee24h += p.Fees.Hour24
+	}
+	return m
+}
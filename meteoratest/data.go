@@ -0,0 +1,188 @@
+package meteoratest
+
+import (
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/dynamicvault"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+// Data is the in-memory store served by a Server. Seed it with Server.Seed.
+//
+// Derived responses such as pool groups, protocol metrics and Stake2Earn
+// analytics are computed from the seeded records unless they are seeded
+// explicitly.
+type Data struct {
+	// DLMM holds the records served by the DLMM routes.
+	DLMM DLMMData
+
+	// DAMMv2 holds the records served by the DAMM v2 routes.
+	DAMMv2 DAMMv2Data
+
+	// DAMMv1 holds the records served by the DAMM v1 routes.
+	DAMMv1 DAMMv1Data
+
+	// Stake2Earn holds the records served by the Stake2Earn routes.
+	Stake2Earn Stake2EarnData
+
+	// DynamicVault holds the records served by the Dynamic Vault routes.
+	DynamicVault DynamicVaultData
+}
+
+// PoolUser identifies the positions of a user in a pool.
+type PoolUser struct {
+	// Pool is the pool address.
+	Pool string
+
+	// User is the wallet address.
+	User string
+}
+
+// DLMMData holds the records served by the DLMM routes.
+type DLMMData struct {
+	// Pools are the pools served by /pools, /pools/groups and /pools/{address}.
+	Pools []dlmm.Pool
+
+	// OHLCV holds candles keyed by pool address.
+	OHLCV map[string][]dlmm.OHLCV
+
+	// VolumeHistory holds volume buckets keyed by pool address.
+	VolumeHistory map[string][]dlmm.VolumeHistory
+
+	// ProtocolMetrics overrides the metrics computed from Pools when set.
+	ProtocolMetrics *dlmm.ProtocolMetrics
+
+	// OpenPositions holds open positions grouped by pool, keyed by wallet address.
+	OpenPositions map[string][]dlmm.PositionsByPool
+
+	// ClosedPositions holds closed positions keyed by wallet address.
+	ClosedPositions map[string][]dlmm.ClosedPosition
+
+	// PositionEvents holds historical events keyed by position address.
+	PositionEvents map[string][]dlmm.PositionEvent
+
+	// PositionClaimFees holds total claimed fees keyed by position address.
+	PositionClaimFees map[string][]dlmm.PositionTotalClaimFees
+
+	// PositionPnL holds position PnL records keyed by pool and user.
+	PositionPnL map[PoolUser][]dlmm.PositionPnLData
+
+	// Portfolio holds portfolio items keyed by wallet address.
+	Portfolio map[string][]dlmm.PoolPortfolioItem
+
+	// OpenPortfolio holds open portfolio items keyed by wallet address.
+	OpenPortfolio map[string][]dlmm.PoolOpenPortfolioItem
+
+	// PortfolioTotal holds all-time PnL totals keyed by wallet address.
+	PortfolioTotal map[string]dlmm.PortfolioTotalResponse
+}
+
+// DAMMv2Data holds the records served by the DAMM v2 routes.
+type DAMMv2Data struct {
+	// Pools are the pools served by /pools, /pools/groups and /pools/{address}.
+	Pools []dammv2.Pool
+
+	// OHLCV holds candles keyed by pool address.
+	OHLCV map[string][]dammv2.OHLCV
+
+	// VolumeHistory holds volume buckets keyed by pool address.
+	VolumeHistory map[string][]dammv2.VolumeHistory
+
+	// ProtocolMetrics overrides the metrics computed from Pools when set.
+	ProtocolMetrics *dammv2.ProtocolMetrics
+
+	// OpenPositions holds open positions grouped by pool, keyed by wallet address.
+	OpenPositions map[string][]dammv2.PositionsByPool
+
+	// ClosedPositions holds closed positions keyed by wallet address.
+	ClosedPositions map[string][]dammv2.ClosedPosition
+}
+
+// DAMMv1Data holds the records served by the DAMM v1 routes.
+type DAMMv1Data struct {
+	// Pools are the pools served by /pools, /pools/search, /farm and /pools_by_a_vault_lp.
+	Pools []dammv1.Pool
+
+	// PoolMetrics overrides the metrics computed from Pools when set.
+	PoolMetrics *dammv1.PoolMetrics
+
+	// PoolConfigs are the configurations served by /pool-configs.
+	PoolConfigs []dammv1.PoolConfig
+
+	// FeeConfigs holds fee configurations keyed by config address.
+	FeeConfigs map[string][]dammv1.FeeConfig
+
+	// AlphaVaults are the vaults served by /alpha-vault.
+	AlphaVaults []dammv1.AlphaVault
+
+	// AlphaVaultConfigs is served by /alpha-vault-configs.
+	AlphaVaultConfigs dammv1.AlphaVaultConfigs
+}
+
+// Stake2EarnData holds the records served by the Stake2Earn routes.
+type Stake2EarnData struct {
+	// Vaults are the vaults served by /vault/all, /vault/filter and /vault/{address}.
+	Vaults []stake2earn.Vault
+
+	// Analytics overrides the analytics computed from Vaults when set.
+	Analytics *stake2earn.Analytics
+}
+
+// VaultStrategy identifies the virtual price series of a vault strategy.
+type VaultStrategy struct {
+	// TokenMint is the vault's token mint.
+	TokenMint string
+
+	// Strategy is the strategy address.
+	Strategy string
+}
+
+// DynamicVaultData holds the records served by the Dynamic Vault routes.
+type DynamicVaultData struct {
+	// Vaults are the vaults served by /vault_info and /vault_state/{token_mint}.
+	Vaults []dynamicvault.VaultInfo
+
+	// VaultAddresses overrides the addresses derived from Vaults when set.
+	VaultAddresses []dynamicvault.VaultAddress
+
+	// APYStates holds APY breakdowns keyed by token mint.
+	APYStates map[string]dynamicvault.APYState
+
+	// APYHistory holds APY entries keyed by token mint.
+	APYHistory map[string][]dynamicvault.APYEntry
+
+	// VirtualPrices holds virtual price series keyed by token mint and strategy.
+	VirtualPrices map[VaultStrategy][]dynamicvault.VirtualPrice
+}
+
+func newData() *Data {
+	return &Data{
+		DLMM: DLMMData{
+			OHLCV:             map[string][]dlmm.OHLCV{},
+			VolumeHistory:     map[string][]dlmm.VolumeHistory{},
+			OpenPositions:     map[string][]dlmm.PositionsByPool{},
+			ClosedPositions:   map[string][]dlmm.ClosedPosition{},
+			PositionEvents:    map[string][]dlmm.PositionEvent{},
+			PositionClaimFees: map[string][]dlmm.PositionTotalClaimFees{},
+			PositionPnL:       map[PoolUser][]dlmm.PositionPnLData{},
+			Portfolio:         map[string][]dlmm.PoolPortfolioItem{},
+			OpenPortfolio:     map[string][]dlmm.PoolOpenPortfolioItem{},
+			PortfolioTotal:    map[string]dlmm.PortfolioTotalResponse{},
+		},
+		DAMMv2: DAMMv2Data{
+			OHLCV:           map[string][]dammv2.OHLCV{},
+			VolumeHistory:   map[string][]dammv2.VolumeHistory{},
+			OpenPositions:   map[string][]dammv2.PositionsByPool{},
+			ClosedPositions: map[string][]dammv2.ClosedPosition{},
+		},
+		DAMMv1: DAMMv1Data{
+			FeeConfigs: map[string][]dammv1.FeeConfig{},
+		},
+		DynamicVault: DynamicVaultData{
+			APYStates:     map[string]dynamicvault.APYState{},
+			APYHistory:    map[string][]dynamicvault.APYEntry{},
+			VirtualPrices: map[VaultStrategy][]dynamicvault.VirtualPrice{},
+		},
+	}
+}
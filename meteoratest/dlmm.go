@@ -0,0 +1,241 @@
+package meteoratest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+const (
+	defaultPortfolioPageSize = 20
+	maxPortfolioPageSize     = 100
+	maxOpenPortfolioPageSize = 50
+)
+
+var dlmmRoutes = []route{
+	{http.MethodGet, "/pools", func(d *Data, q url.Values, _ []string) (any, error) {
+		return listPools(d.DLMM.Pools, q)
+	}},
+	{http.MethodGet, "/pools/groups", func(d *Data, q url.Values, _ []string) (any, error) {
+		return listGroups(d.DLMM.Pools, q)
+	}},
+	{http.MethodGet, "/pools/groups/*", func(d *Data, q url.Values, args []string) (any, error) {
+		return getGroup(d.DLMM.Pools, args[0], q)
+	}},
+	{http.MethodGet, "/pools/*", func(d *Data, _ url.Values, args []string) (any, error) {
+		return getPool(d.DLMM.Pools, args[0])
+	}},
+	{http.MethodGet, "/pools/*/ohlcv", func(d *Data, q url.Values, args []string) (any, error) {
+		return timeSeries(d.DLMM.Pools, args[0], d.DLMM.OHLCV[args[0]], func(c dlmm.OHLCV) int64 { return c.Timestamp }, q)
+	}},
+	{http.MethodGet, "/pools/*/volume/history", func(d *Data, q url.Values, args []string) (any, error) {
+		return timeSeries(d.DLMM.Pools, args[0], d.DLMM.VolumeHistory[args[0]], func(v dlmm.VolumeHistory) int64 { return v.Timestamp }, q)
+	}},
+	{http.MethodGet, "/stats/protocol_metrics", func(d *Data, _ url.Values, _ []string) (any, error) {
+		return dlmmProtocolMetrics(&d.DLMM), nil
+	}},
+	{http.MethodGet, "/wallets/*/closed_positions", func(d *Data, q url.Values, args []string) (any, error) {
+		return closedPositions(d.DLMM.ClosedPositions[args[0]],
+			func(p dlmm.ClosedPosition) int64 { return p.ClosedAt },
+			func(p dlmm.ClosedPosition) string { return p.PoolAddress }, q)
+	}},
+	{http.MethodGet, "/wallets/*/open_positions", func(d *Data, q url.Values, args []string) (any, error) {
+		resp := &dlmm.OpenPositionsResponse{Data: []dlmm.PositionsByPool{}}
+		for _, p := range d.DLMM.OpenPositions[args[0]] {
+			if q.Has("pool") && p.PoolAddress != q.Get("pool") {
+				continue
+			}
+			resp.Data = append(resp.Data, p)
+			resp.TotalPools++
+			resp.TotalPositions += int64(len(p.Positions))
+		}
+		return resp, nil
+	}},
+	{http.MethodGet, "/positions/*/historical", dlmmPositionEvents},
+	{http.MethodGet, "/positions/*/total_claim_fees", func(d *Data, _ url.Values, args []string) (any, error) {
+		return append([]dlmm.PositionTotalClaimFees{}, d.DLMM.PositionClaimFees[args[0]]...), nil
+	}},
+	{http.MethodGet, "/positions/*/pnl", dlmmPositionPnL},
+	{http.MethodGet, "/portfolio", dlmmPortfolio},
+	{http.MethodGet, "/portfolio/open", dlmmOpenPortfolio},
+	{http.MethodGet, "/portfolio/total", func(d *Data, q url.Values, _ []string) (any, error) {
+		if q.Get("user") == "" {
+			return nil, errBadRequest("user is required")
+		}
+		total, ok := d.DLMM.PortfolioTotal[q.Get("user")]
+		if !ok {
+			total = dlmm.PortfolioTotalResponse{TotalPnLPctChange: "0", TotalPnLUsd: "0"}
+		}
+		return total, nil
+	}},
+}
+
+func dlmmProtocolMetrics(d *DLMMData) *dlmm.ProtocolMetrics {
+	if d.ProtocolMetrics != nil {
+		return d.ProtocolMetrics
+	}
+	m := &dlmm.ProtocolMetrics{TotalPools: len(d.Pools)}
+	for _, p := range d.Pools {
+		m.TotalTVL += p.TVL
+		m.Volume24h += p.Volume.Hour24
+		m.Fee24h += p.Fees.Hour24
+		m.TotalVolume += p.CumulativeMetrics.Volume
+		m.TotalFees += p.CumulativeMetrics.TradeFee
+	}
+	return m
+}
+
+func dlmmPositionEvents(d *Data, q url.Values, args []string) (any, error) {
+	eventType := dlmm.PositionEventType(q.Get("event_type"))
+	if eventType != "" && !eventType.IsValid() {
+		return nil, errBadRequest("invalid event_type %q", eventType)
+	}
+	direction := q.Get("order_direction")
+	if direction != "" && direction != string(dlmm.PositionEventOrderDirectionAsc) && direction != string(dlmm.PositionEventOrderDirectionDesc) {
+		return nil, errBadRequest("invalid order_direction %q", direction)
+	}
+
+	resp := &dlmm.GetPositionHistoricalEventsResponse{Events: []dlmm.PositionEvent{}}
+	for _, ev := range d.DLMM.PositionEvents[args[0]] {
+		if eventType == "" || ev.EventType == string(eventType) {
+			resp.Events = append(resp.Events, ev)
+		}
+	}
+	asc := direction == string(dlmm.PositionEventOrderDirectionAsc)
+	sort.SliceStable(resp.Events, func(a, b int) bool {
+		if asc {
+			return resp.Events[a].BlockTime < resp.Events[b].BlockTime
+		}
+		return resp.Events[a].BlockTime > resp.Events[b].BlockTime
+	})
+	return resp, nil
+}
+
+func dlmmPositionPnL(d *Data, q url.Values, args []string) (any, error) {
+	user := q.Get("user")
+	if user == "" {
+		return nil, errBadRequest("user is required")
+	}
+	status := dlmm.PositionStatus(q.Get("status"))
+	switch status {
+	case "", dlmm.PositionStatusAll, dlmm.PositionStatusOpen, dlmm.PositionStatusClosed:
+	default:
+		return nil, errBadRequest("invalid status %q", status)
+	}
+
+	var positions []dlmm.PositionPnLData
+	for _, p := range d.DLMM.PositionPnL[PoolUser{Pool: args[0], User: user}] {
+		if (status == dlmm.PositionStatusOpen && p.IsClosed) || (status == dlmm.PositionStatusClosed && !p.IsClosed) {
+			continue
+		}
+		positions = append(positions, p)
+	}
+
+	page, err := paginate(positions, q, defaultPortfolioPageSize, maxPortfolioPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &dlmm.GetPoolPositionPnLResponse{
+		HasNext:           page.CurrentPage < page.Pages,
+		Page:              page.CurrentPage,
+		PageSize:          page.PageSize,
+		Positions:         page.Data,
+		RewardTokenXPrice: "0",
+		RewardTokenYPrice: "0",
+		TokenXPrice:       "0",
+		TokenYPrice:       "0",
+		TotalCount:        int64(page.Total),
+	}, nil
+}
+
+func dlmmPortfolio(d *Data, q url.Values, _ []string) (any, error) {
+	user := q.Get("user")
+	if user == "" {
+		return nil, errBadRequest("user is required")
+	}
+	daysBack, err := intParam(q, "days_back", 0, 1, 3650)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pools still open, without a close time, are always included.
+	var items []dlmm.PoolPortfolioItem
+	cutoff := time.Now().AddDate(0, 0, -daysBack).Unix()
+	for _, item := range d.DLMM.Portfolio[user] {
+		if daysBack > 0 && item.LastClosedAt != nil && *item.LastClosedAt < cutoff {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	page, err := paginate(items, q, defaultPortfolioPageSize, maxPortfolioPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &dlmm.GetPortfolioResponse{
+		HasNext:    page.CurrentPage < page.Pages,
+		Page:       page.CurrentPage,
+		PageSize:   page.PageSize,
+		Pools:      page.Data,
+		TotalCount: int64(page.Total),
+	}, nil
+}
+
+func dlmmOpenPortfolio(d *Data, q url.Values, _ []string) (any, error) {
+	user := q.Get("user")
+	if user == "" {
+		return nil, errBadRequest("user is required")
+	}
+
+	// unclaimed_fee has no counterpart in the item type, so it keeps the seeded order.
+	items := append([]dlmm.PoolOpenPortfolioItem{}, d.DLMM.OpenPortfolio[user]...)
+	var key func(dlmm.PoolOpenPortfolioItem) float64
+	switch dlmm.GetOpenPortfolioSort(q.Get("sort_by")) {
+	case "", dlmm.SortByCurrentBalances:
+		key = func(i dlmm.PoolOpenPortfolioItem) float64 { return parseFloat(i.Balances) }
+	case dlmm.SortByFeePerTVL24h:
+		key = func(i dlmm.PoolOpenPortfolioItem) float64 { return parseFloat(i.FeePerTVL24h) }
+	case dlmm.SortByUnclaimedFee:
+	default:
+		return nil, errBadRequest("invalid sort_by %q", q.Get("sort_by"))
+	}
+	direction := dlmm.SortDirection(q.Get("sort_direction"))
+	if direction != "" && direction != dlmm.SortDirectionAsc && direction != dlmm.SortDirectionDesc {
+		return nil, errBadRequest("invalid sort_direction %q", direction)
+	}
+	if key != nil {
+		sort.SliceStable(items, func(a, b int) bool {
+			if direction == dlmm.SortDirectionAsc {
+				return key(items[a]) < key(items[b])
+			}
+			return key(items[a]) > key(items[b])
+		})
+	}
+
+	var balances, pnl float64
+	for _, item := range items {
+		balances += parseFloat(item.Balances)
+		pnl += parseFloat(item.PnL)
+	}
+
+	page, err := paginate(items, q, defaultPortfolioPageSize, maxOpenPortfolioPageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &dlmm.GetOpenPortfolioResponse{
+		HasNext:  page.CurrentPage < page.Pages,
+		Page:     page.CurrentPage,
+		PageSize: page.PageSize,
+		Pools:    page.Data,
+		Total: &dlmm.TotalMetrics{
+			Balances:      strconv.FormatFloat(balances, 'f', -1, 64),
+			PnL:           strconv.FormatFloat(pnl, 'f', -1, 64),
+			UnclaimedFees: "0",
+		},
+		TotalCount: int64(page.Total),
+	}, nil
+}
@@ -0,0 +1,31 @@
+// Package meteoratest provides an in-process fake of the Meteora APIs for
+// testing code that uses this SDK.
+//
+// A Server implements every route of the DLMM and DAMM v2 datapi (see
+// dlmm/openapi.json and dammv2/openapi.json) and of the DAMM v1, Stake2Earn
+// and Dynamic Vault APIs, served from an in-memory store. Pool listings
+// support pagination, query, filter_by and sort_by; wallet endpoints support
+// cursor pagination and time ranges; DAMM v1 search supports its filters,
+// sort keys and 0-based pages. Faults such as 429s, 5xx responses and latency
+// can be injected per path.
+//
+// # Usage
+//
+//	srv := meteoratest.NewServer()
+//	defer srv.Close()
+//
+//	srv.Seed(func(d *meteoratest.Data) {
+//	    d.DLMM.Pools = append(d.DLMM.Pools, dlmm.Pool{Address: "pool1", Name: "SOL-USDC", TVL: 1000})
+//	    d.Stake2Earn.Vaults = append(d.Stake2Earn.Vaults, stake2earn.Vault{VaultAddress: "vault1"})
+//	})
+//	srv.InjectFault(meteoratest.Fault{Path: "/dlmm/pools", Status: http.StatusTooManyRequests, Count: 1})
+//
+//	client := srv.Client()
+//	pools, err := client.DLMM.ListPools(ctx, &dlmm.ListPoolsParams{
+//	    FilterBy: meteora.String("tvl>100"),
+//	})
+//
+// Each API is served under its own path prefix (DLMMPrefix, DAMMv2Prefix,
+// ...), so standalone service clients can be pointed at srv.URL plus the
+// prefix.
+package meteoratest
@@ -0,0 +1,66 @@
+package meteoratest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ua1984/meteora-go/dynamicvault"
+)
+
+var dynamicVaultRoutes = []route{
+	{http.MethodGet, "/vault_info", func(d *Data, _ url.Values, _ []string) (any, error) {
+		return append([]dynamicvault.VaultInfo{}, d.DynamicVault.Vaults...), nil
+	}},
+	{http.MethodGet, "/vault_addresses", func(d *Data, _ url.Values, _ []string) (any, error) {
+		if d.DynamicVault.VaultAddresses != nil {
+			return d.DynamicVault.VaultAddresses, nil
+		}
+		addresses := []dynamicvault.VaultAddress{}
+		for _, v := range d.DynamicVault.Vaults {
+			addresses = append(addresses, dynamicvault.VaultAddress{
+				Symbol:        v.Symbol,
+				VaultAddress:  v.Pubkey,
+				LPMintAddress: v.LPMint,
+				FeeAddress:    v.FeePubkey,
+			})
+		}
+		return addresses, nil
+	}},
+	{http.MethodGet, "/vault_state/*", func(d *Data, _ url.Values, args []string) (any, error) {
+		for _, v := range d.DynamicVault.Vaults {
+			if v.TokenAddress == args[0] {
+				return v, nil
+			}
+		}
+		return nil, errNotFound("vault %s", args[0])
+	}},
+	{http.MethodGet, "/apy_state/*", func(d *Data, _ url.Values, args []string) (any, error) {
+		state, ok := d.DynamicVault.APYStates[args[0]]
+		if !ok {
+			return nil, errNotFound("APY state %s", args[0])
+		}
+		return state, nil
+	}},
+	{http.MethodGet, "/apy_filter/*/*/*", func(d *Data, _ url.Values, args []string) (any, error) {
+		start, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return nil, errBadRequest("invalid start time %q", args[1])
+		}
+		end, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return nil, errBadRequest("invalid end time %q", args[2])
+		}
+		entries := []dynamicvault.APYEntry{}
+		for _, e := range d.DynamicVault.APYHistory[args[0]] {
+			if e.Timestamp >= start && e.Timestamp <= end {
+				entries = append(entries, e)
+			}
+		}
+		return entries, nil
+	}},
+	{http.MethodGet, "/virtual_price/*/*", func(d *Data, _ url.Values, args []string) (any, error) {
+		prices := d.DynamicVault.VirtualPrices[VaultStrategy{TokenMint: args[0], Strategy: args[1]}]
+		return append([]dynamicvault.VirtualPrice{}, prices...), nil
+	}},
+}
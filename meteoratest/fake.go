@@ -0,0 +1,64 @@
+package meteoratest
+
+import (
+	"errors"
+	"sync"
+)
+
+//go:generate go run ../internal/cmd/fakegen -src ../dlmm -import github.com/ua1984/meteora-go/dlmm -type FakeDLMM -out fake_dlmm.go
+//go:generate go run ../internal/cmd/fakegen -src ../dammv2 -import github.com/ua1984/meteora-go/dammv2 -type FakeDAMMv2 -out fake_dammv2.go
+//go:generate go run ../internal/cmd/fakegen -src ../dammv1 -import github.com/ua1984/meteora-go/dammv1 -type FakeDAMMv1 -out fake_dammv1.go
+//go:generate go run ../internal/cmd/fakegen -src ../stake2earn -import github.com/ua1984/meteora-go/stake2earn -type FakeStake2Earn -out fake_stake2earn.go
+//go:generate go run ../internal/cmd/fakegen -src ../dynamicvault -import github.com/ua1984/meteora-go/dynamicvault -type FakeDynamicVault -out fake_dynamicvault.go
+
+// ErrNotStubbed is returned by fake methods whose Func field is not set.
+var ErrNotStubbed = errors.New("meteoratest: method not stubbed")
+
+// Call is a method call recorded by a fake.
+type Call struct {
+	// Method is the name of the called method.
+	Method string
+
+	// Args holds the arguments of the call, excluding the context.
+	Args []any
+}
+
+// callRecorder records the calls made to a fake. It is embedded in every
+// generated fake and is safe for concurrent use.
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns the calls made so far, in order.
+func (r *callRecorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the calls made so far to the named method, in order.
+func (r *callRecorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// ResetCalls forgets the calls made so far.
+func (r *callRecorder) ResetCalls() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+func (r *callRecorder) record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
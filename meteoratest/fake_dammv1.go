@@ -0,0 +1,139 @@
+// Code generated by fakegen. DO NOT EDIT.
+
+package meteoratest
+
+import (
+	"context"
+
+	"github.com/ua1984/meteora-go/dammv1"
+)
+
+// FakeDAMMv1 is an in-memory fake of dammv1.API.
+// Each method records its arguments, except the context, and calls the
+// matching Func field. Unset fields make the method return ErrNotStubbed, or
+// zero values if it has no error result.
+type FakeDAMMv1 struct {
+	callRecorder
+
+	// ListPoolsFunc implements ListPools.
+	ListPoolsFunc func(ctx context.Context, params *dammv1.ListPoolsParams) ([]dammv1.Pool, error)
+
+	// SearchPoolsFunc implements SearchPools.
+	SearchPoolsFunc func(ctx context.Context, params *dammv1.SearchParams) (*dammv1.SearchResult, error)
+
+	// GetPoolsMetricsFunc implements GetPoolsMetrics.
+	GetPoolsMetricsFunc func(ctx context.Context) (*dammv1.PoolMetrics, error)
+
+	// ListPoolConfigsFunc implements ListPoolConfigs.
+	ListPoolConfigsFunc func(ctx context.Context) ([]dammv1.PoolConfig, error)
+
+	// GetFeeConfigFunc implements GetFeeConfig.
+	GetFeeConfigFunc func(ctx context.Context, configAddr string) ([]dammv1.FeeConfig, error)
+
+	// ListPoolsWithFarmFunc implements ListPoolsWithFarm.
+	ListPoolsWithFarmFunc func(ctx context.Context, params *dammv1.PaginationParams) ([]dammv1.Pool, error)
+
+	// ListAlphaVaultsFunc implements ListAlphaVaults.
+	ListAlphaVaultsFunc func(ctx context.Context, params *dammv1.AlphaVaultParams) ([]dammv1.AlphaVault, error)
+
+	// ListAlphaVaultConfigsFunc implements ListAlphaVaultConfigs.
+	ListAlphaVaultConfigsFunc func(ctx context.Context) (*dammv1.AlphaVaultConfigs, error)
+
+	// GetPoolsByVaultLPFunc implements GetPoolsByVaultLP.
+	GetPoolsByVaultLPFunc func(ctx context.Context, address string) ([]dammv1.Pool, error)
+
+	// GetPoolsFunc implements GetPools.
+	GetPoolsFunc func(ctx context.Context, addresses []string, opts *dammv1.BatchOptions) []dammv1.PoolResult
+}
+
+var _ dammv1.API = (*FakeDAMMv1)(nil)
+
+// ListPools records the call and calls ListPoolsFunc.
+func (f *FakeDAMMv1) ListPools(ctx context.Context, params *dammv1.ListPoolsParams) ([]dammv1.Pool, error) {
+	f.record("ListPools", params)
+	if f.ListPoolsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListPoolsFunc(ctx, params)
+}
+
+// SearchPools records the call and calls SearchPoolsFunc.
+func (f *FakeDAMMv1) SearchPools(ctx context.Context, params *dammv1.SearchParams) (*dammv1.SearchResult, error) {
+	f.record("SearchPools", params)
+	if f.SearchPoolsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.SearchPoolsFunc(ctx, params)
+}
+
+// GetPoolsMetrics records the call and calls GetPoolsMetricsFunc.
+func (f *FakeDAMMv1) GetPoolsMetrics(ctx context.Context) (*dammv1.PoolMetrics, error) {
+	f.record("GetPoolsMetrics")
+	if f.GetPoolsMetricsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPoolsMetricsFunc(ctx)
+}
+
+// ListPoolConfigs records the call and calls ListPoolConfigsFunc.
+func (f *FakeDAMMv1) ListPoolConfigs(ctx context.Context) ([]dammv1.PoolConfig, error) {
+	f.record("ListPoolConfigs")
+	if f.ListPoolConfigsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListPoolConfigsFunc(ctx)
+}
+
+// GetFeeConfig records the call and calls GetFeeConfigFunc.
+func (f *FakeDAMMv1) GetFeeConfig(ctx context.Context, configAddr string) ([]dammv1.FeeConfig, error) {
+	f.record("GetFeeConfig", configAddr)
+	if f.GetFeeConfigFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetFeeConfigFunc(ctx, configAddr)
+}
+
+// ListPoolsWithFarm records the call and calls ListPoolsWithFarmFunc.
+func (f *FakeDAMMv1) ListPoolsWithFarm(ctx context.Context, params *dammv1.PaginationParams) ([]dammv1.Pool, error) {
+	f.record("ListPoolsWithFarm", params)
+	if f.ListPoolsWithFarmFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListPoolsWithFarmFunc(ctx, params)
+}
+
+// ListAlphaVaults records the call and calls ListAlphaVaultsFunc.
+func (f *FakeDAMMv1) ListAlphaVaults(ctx context.Context, params *dammv1.AlphaVaultParams) ([]dammv1.AlphaVault, error) {
+	f.record("ListAlphaVaults", params)
+	if f.ListAlphaVaultsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListAlphaVaultsFunc(ctx, params)
+}
+
+// ListAlphaVaultConfigs records the call and calls ListAlphaVaultConfigsFunc.
+func (f *FakeDAMMv1) ListAlphaVaultConfigs(ctx context.Context) (*dammv1.AlphaVaultConfigs, error) {
+	f.record("ListAlphaVaultConfigs")
+	if f.ListAlphaVaultConfigsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListAlphaVaultConfigsFunc(ctx)
+}
+
+// GetPoolsByVaultLP records the call and calls GetPoolsByVaultLPFunc.
+func (f *FakeDAMMv1) GetPoolsByVaultLP(ctx context.Context, address string) ([]dammv1.Pool, error) {
+	f.record("GetPoolsByVaultLP", address)
+	if f.GetPoolsByVaultLPFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPoolsByVaultLPFunc(ctx, address)
+}
+
+// GetPools records the call and calls GetPoolsFunc.
+func (f *FakeDAMMv1) GetPools(ctx context.Context, addresses []string, opts *dammv1.BatchOptions) []dammv1.PoolResult {
+	f.record("GetPools", addresses, opts)
+	if f.GetPoolsFunc == nil {
+		return nil
+	}
+	return f.GetPoolsFunc(ctx, addresses, opts)
+}
@@ -0,0 +1,139 @@
+// Code generated by fakegen. DO NOT EDIT.
+
+package meteoratest
+
+import (
+	"context"
+
+	"github.com/ua1984/meteora-go/dammv2"
+)
+
+// FakeDAMMv2 is an in-memory fake of dammv2.API.
+// Each method records its arguments, except the context, and calls the
+// matching Func field. Unset fields make the method return ErrNotStubbed, or
+// zero values if it has no error result.
+type FakeDAMMv2 struct {
+	callRecorder
+
+	// ListPoolsFunc implements ListPools.
+	ListPoolsFunc func(ctx context.Context, params *dammv2.ListPoolsParams) (*dammv2.PaginatedResponse[dammv2.Pool], error)
+
+	// ListGroupsFunc implements ListGroups.
+	ListGroupsFunc func(ctx context.Context, params *dammv2.ListGroupsParams) (*dammv2.PaginatedResponse[dammv2.PoolGroup], error)
+
+	// GetGroupFunc implements GetGroup.
+	GetGroupFunc func(ctx context.Context, lexicalOrderMints string, params *dammv2.GetGroupParams) (*dammv2.PaginatedResponse[dammv2.Pool], error)
+
+	// GetPoolFunc implements GetPool.
+	GetPoolFunc func(ctx context.Context, address string) (*dammv2.Pool, error)
+
+	// GetOHLCVFunc implements GetOHLCV.
+	GetOHLCVFunc func(ctx context.Context, address string, params *dammv2.OHLCVParams) (*dammv2.OHLCVResponse, error)
+
+	// GetVolumeHistoryFunc implements GetVolumeHistory.
+	GetVolumeHistoryFunc func(ctx context.Context, address string, params *dammv2.VolumeHistoryParams) (*dammv2.VolumeHistoryResponse, error)
+
+	// GetClosedPositionsFunc implements GetClosedPositions.
+	GetClosedPositionsFunc func(ctx context.Context, wallet string, params *dammv2.GetClosedPositionsParams) (*dammv2.CursorPaginatedResponse[dammv2.ClosedPosition], error)
+
+	// GetOpenPositionsFunc implements GetOpenPositions.
+	GetOpenPositionsFunc func(ctx context.Context, wallet string, params *dammv2.GetOpenPositionsParams) (*dammv2.OpenPositionsResponse, error)
+
+	// GetProtocolMetricsFunc implements GetProtocolMetrics.
+	GetProtocolMetricsFunc func(ctx context.Context) (*dammv2.ProtocolMetrics, error)
+
+	// GetPoolsFunc implements GetPools.
+	GetPoolsFunc func(ctx context.Context, addresses []string, opts *dammv2.BatchOptions) []dammv2.PoolResult
+}
+
+var _ dammv2.API = (*FakeDAMMv2)(nil)
+
+// ListPools records the call and calls ListPoolsFunc.
+func (f *FakeDAMMv2) ListPools(ctx context.Context, params *dammv2.ListPoolsParams) (*dammv2.PaginatedResponse[dammv2.Pool], error) {
+	f.record("ListPools", params)
+	if f.ListPoolsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListPoolsFunc(ctx, params)
+}
+
+// ListGroups records the call and calls ListGroupsFunc.
+func (f *FakeDAMMv2) ListGroups(ctx context.Context, params *dammv2.ListGroupsParams) (*dammv2.PaginatedResponse[dammv2.PoolGroup], error) {
+	f.record("ListGroups", params)
+	if f.ListGroupsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListGroupsFunc(ctx, params)
+}
+
+// GetGroup records the call and calls GetGroupFunc.
+func (f *FakeDAMMv2) GetGroup(ctx context.Context, lexicalOrderMints string, params *dammv2.GetGroupParams) (*dammv2.PaginatedResponse[dammv2.Pool], error) {
+	f.record("GetGroup", lexicalOrderMints, params)
+	if f.GetGroupFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetGroupFunc(ctx, lexicalOrderMints, params)
+}
+
+// GetPool records the call and calls GetPoolFunc.
+func (f *FakeDAMMv2) GetPool(ctx context.Context, address string) (*dammv2.Pool, error) {
+	f.record("GetPool", address)
+	if f.GetPoolFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPoolFunc(ctx, address)
+}
+
+// GetOHLCV records the call and calls GetOHLCVFunc.
+func (f *FakeDAMMv2) GetOHLCV(ctx context.Context, address string, params *dammv2.OHLCVParams) (*dammv2.OHLCVResponse, error) {
+	f.record("GetOHLCV", address, params)
+	if f.GetOHLCVFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetOHLCVFunc(ctx, address, params)
+}
+
+// GetVolumeHistory records the call and calls GetVolumeHistoryFunc.
+func (f *FakeDAMMv2) GetVolumeHistory(ctx context.Context, address string, params *dammv2.VolumeHistoryParams) (*dammv2.VolumeHistoryResponse, error) {
+	f.record("GetVolumeHistory", address, params)
+	if f.GetVolumeHistoryFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetVolumeHistoryFunc(ctx, address, params)
+}
+
+// GetClosedPositions records the call and calls GetClosedPositionsFunc.
+func (f *FakeDAMMv2) GetClosedPositions(ctx context.Context, wallet string, params *dammv2.GetClosedPositionsParams) (*dammv2.CursorPaginatedResponse[dammv2.ClosedPosition], error) {
+	f.record("GetClosedPositions", wallet, params)
+	if f.GetClosedPositionsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetClosedPositionsFunc(ctx, wallet, params)
+}
+
+// GetOpenPositions records the call and calls GetOpenPositionsFunc.
+func (f *FakeDAMMv2) GetOpenPositions(ctx context.Context, wallet string, params *dammv2.GetOpenPositionsParams) (*dammv2.OpenPositionsResponse, error) {
+	f.record("GetOpenPositions", wallet, params)
+	if f.GetOpenPositionsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetOpenPositionsFunc(ctx, wallet, params)
+}
+
+// GetProtocolMetrics records the call and calls GetProtocolMetricsFunc.
+func (f *FakeDAMMv2) GetProtocolMetrics(ctx context.Context) (*dammv2.ProtocolMetrics, error) {
+	f.record("GetProtocolMetrics")
+	if f.GetProtocolMetricsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetProtocolMetricsFunc(ctx)
+}
+
+// GetPools records the call and calls GetPoolsFunc.
+func (f *FakeDAMMv2) GetPools(ctx context.Context, addresses []string, opts *dammv2.BatchOptions) []dammv2.PoolResult {
+	f.record("GetPools", addresses, opts)
+	if f.GetPoolsFunc == nil {
+		return nil
+	}
+	return f.GetPoolsFunc(ctx, addresses, opts)
+}
@@ -0,0 +1,211 @@
+// Code generated by fakegen. DO NOT EDIT.
+
+package meteoratest
+
+import (
+	"context"
+
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+// FakeDLMM is an in-memory fake of dlmm.API.
+// Each method records its arguments, except the context, and calls the
+// matching Func field. Unset fields make the method return ErrNotStubbed, or
+// zero values if it has no error result.
+type FakeDLMM struct {
+	callRecorder
+
+	// ListPoolsFunc implements ListPools.
+	ListPoolsFunc func(ctx context.Context, params *dlmm.ListPoolsParams) (*dlmm.PaginatedResponse[dlmm.Pool], error)
+
+	// ListGroupsFunc implements ListGroups.
+	ListGroupsFunc func(ctx context.Context, params *dlmm.ListGroupsParams) (*dlmm.PaginatedResponse[dlmm.PoolGroup], error)
+
+	// GetGroupFunc implements GetGroup.
+	GetGroupFunc func(ctx context.Context, lexicalOrderMints string, params *dlmm.GetGroupParams) (*dlmm.PaginatedResponse[dlmm.Pool], error)
+
+	// GetPoolFunc implements GetPool.
+	GetPoolFunc func(ctx context.Context, address string) (*dlmm.Pool, error)
+
+	// GetOHLCVFunc implements GetOHLCV.
+	GetOHLCVFunc func(ctx context.Context, address string, params *dlmm.OHLCVParams) (*dlmm.OHLCVResponse, error)
+
+	// GetVolumeHistoryFunc implements GetVolumeHistory.
+	GetVolumeHistoryFunc func(ctx context.Context, address string, params *dlmm.VolumeHistoryParams) (*dlmm.VolumeHistoryResponse, error)
+
+	// GetProtocolMetricsFunc implements GetProtocolMetrics.
+	GetProtocolMetricsFunc func(ctx context.Context) (*dlmm.ProtocolMetrics, error)
+
+	// GetClosedPositionsFunc implements GetClosedPositions.
+	GetClosedPositionsFunc func(ctx context.Context, wallet string, params *dlmm.GetClosedPositionsParams) (*dlmm.ClosedPositionsCursorResponse, error)
+
+	// GetOpenPositionsFunc implements GetOpenPositions.
+	GetOpenPositionsFunc func(ctx context.Context, wallet string, params *dlmm.GetOpenPositionsParams) (*dlmm.OpenPositionsResponse, error)
+
+	// GetPositionHistoricalEventsFunc implements GetPositionHistoricalEvents.
+	GetPositionHistoricalEventsFunc func(ctx context.Context, address string, params *dlmm.GetPositionHistoricalEventsParams) (*dlmm.GetPositionHistoricalEventsResponse, error)
+
+	// GetPositionTotalClaimFeesFunc implements GetPositionTotalClaimFees.
+	GetPositionTotalClaimFeesFunc func(ctx context.Context, address string) ([]dlmm.PositionTotalClaimFees, error)
+
+	// GetPoolPositionPnLFunc implements GetPoolPositionPnL.
+	GetPoolPositionPnLFunc func(ctx context.Context, poolAddress string, params *dlmm.GetPoolPositionPnLParams) (*dlmm.GetPoolPositionPnLResponse, error)
+
+	// GetPortfolioFunc implements GetPortfolio.
+	GetPortfolioFunc func(ctx context.Context, params *dlmm.GetPortfolioParams) (*dlmm.GetPortfolioResponse, error)
+
+	// GetOpenPortfolioFunc implements GetOpenPortfolio.
+	GetOpenPortfolioFunc func(ctx context.Context, params *dlmm.GetOpenPortfolioParams) (*dlmm.GetOpenPortfolioResponse, error)
+
+	// GetPortfolioTotalFunc implements GetPortfolioTotal.
+	GetPortfolioTotalFunc func(ctx context.Context, user string) (*dlmm.PortfolioTotalResponse, error)
+
+	// GetPoolsFunc implements GetPools.
+	GetPoolsFunc func(ctx context.Context, addresses []string, opts *dlmm.BatchOptions) []dlmm.PoolResult
+}
+
+var _ dlmm.API = (*FakeDLMM)(nil)
+
+// ListPools records the call and calls ListPoolsFunc.
+func (f *FakeDLMM) ListPools(ctx context.Context, params *dlmm.ListPoolsParams) (*dlmm.PaginatedResponse[dlmm.Pool], error) {
+	f.record("ListPools", params)
+	if f.ListPoolsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListPoolsFunc(ctx, params)
+}
+
+// ListGroups records the call and calls ListGroupsFunc.
+func (f *FakeDLMM) ListGroups(ctx context.Context, params *dlmm.ListGroupsParams) (*dlmm.PaginatedResponse[dlmm.PoolGroup], error) {
+	f.record("ListGroups", params)
+	if f.ListGroupsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListGroupsFunc(ctx, params)
+}
+
+// GetGroup records the call and calls GetGroupFunc.
+func (f *FakeDLMM) GetGroup(ctx context.Context, lexicalOrderMints string, params *dlmm.GetGroupParams) (*dlmm.PaginatedResponse[dlmm.Pool], error) {
+	f.record("GetGroup", lexicalOrderMints, params)
+	if f.GetGroupFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetGroupFunc(ctx, lexicalOrderMints, params)
+}
+
+// GetPool records the call and calls GetPoolFunc.
+func (f *FakeDLMM) GetPool(ctx context.Context, address string) (*dlmm.Pool, error) {
+	f.record("GetPool", address)
+	if f.GetPoolFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPoolFunc(ctx, address)
+}
+
+// GetOHLCV records the call and calls GetOHLCVFunc.
+func (f *FakeDLMM) GetOHLCV(ctx context.Context, address string, params *dlmm.OHLCVParams) (*dlmm.OHLCVResponse, error) {
+	f.record("GetOHLCV", address, params)
+	if f.GetOHLCVFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetOHLCVFunc(ctx, address, params)
+}
+
+// GetVolumeHistory records the call and calls GetVolumeHistoryFunc.
+func (f *FakeDLMM) GetVolumeHistory(ctx context.Context, address string, params *dlmm.VolumeHistoryParams) (*dlmm.VolumeHistoryResponse, error) {
+	f.record("GetVolumeHistory", address, params)
+	if f.GetVolumeHistoryFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetVolumeHistoryFunc(ctx, address, params)
+}
+
+// GetProtocolMetrics records the call and calls GetProtocolMetricsFunc.
+func (f *FakeDLMM) GetProtocolMetrics(ctx context.Context) (*dlmm.ProtocolMetrics, error) {
+	f.record("GetProtocolMetrics")
+	if f.GetProtocolMetricsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetProtocolMetricsFunc(ctx)
+}
+
+// GetClosedPositions records the call and calls GetClosedPositionsFunc.
+func (f *FakeDLMM) GetClosedPositions(ctx context.Context, wallet string, params *dlmm.GetClosedPositionsParams) (*dlmm.ClosedPositionsCursorResponse, error) {
+	f.record("GetClosedPositions", wallet, params)
+	if f.GetClosedPositionsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetClosedPositionsFunc(ctx, wallet, params)
+}
+
+// GetOpenPositions records the call and calls GetOpenPositionsFunc.
+func (f *FakeDLMM) GetOpenPositions(ctx context.Context, wallet string, params *dlmm.GetOpenPositionsParams) (*dlmm.OpenPositionsResponse, error) {
+	f.record("GetOpenPositions", wallet, params)
+	if f.GetOpenPositionsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetOpenPositionsFunc(ctx, wallet, params)
+}
+
+// GetPositionHistoricalEvents records the call and calls GetPositionHistoricalEventsFunc.
+func (f *FakeDLMM) GetPositionHistoricalEvents(ctx context.Context, address string, params *dlmm.GetPositionHistoricalEventsParams) (*dlmm.GetPositionHistoricalEventsResponse, error) {
+	f.record("GetPositionHistoricalEvents", address, params)
+	if f.GetPositionHistoricalEventsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPositionHistoricalEventsFunc(ctx, address, params)
+}
+
+// GetPositionTotalClaimFees records the call and calls GetPositionTotalClaimFeesFunc.
+func (f *FakeDLMM) GetPositionTotalClaimFees(ctx context.Context, address string) ([]dlmm.PositionTotalClaimFees, error) {
+	f.record("GetPositionTotalClaimFees", address)
+	if f.GetPositionTotalClaimFeesFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPositionTotalClaimFeesFunc(ctx, address)
+}
+
+// GetPoolPositionPnL records the call and calls GetPoolPositionPnLFunc.
+func (f *FakeDLMM) GetPoolPositionPnL(ctx context.Context, poolAddress string, params *dlmm.GetPoolPositionPnLParams) (*dlmm.GetPoolPositionPnLResponse, error) {
+	f.record("GetPoolPositionPnL", poolAddress, params)
+	if f.GetPoolPositionPnLFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPoolPositionPnLFunc(ctx, poolAddress, params)
+}
+
+// GetPortfolio records the call and calls GetPortfolioFunc.
+func (f *FakeDLMM) GetPortfolio(ctx context.Context, params *dlmm.GetPortfolioParams) (*dlmm.GetPortfolioResponse, error) {
+	f.record("GetPortfolio", params)
+	if f.GetPortfolioFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPortfolioFunc(ctx, params)
+}
+
+// GetOpenPortfolio records the call and calls GetOpenPortfolioFunc.
+func (f *FakeDLMM) GetOpenPortfolio(ctx context.Context, params *dlmm.GetOpenPortfolioParams) (*dlmm.GetOpenPortfolioResponse, error) {
+	f.record("GetOpenPortfolio", params)
+	if f.GetOpenPortfolioFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetOpenPortfolioFunc(ctx, params)
+}
+
+// GetPortfolioTotal records the call and calls GetPortfolioTotalFunc.
+func (f *FakeDLMM) GetPortfolioTotal(ctx context.Context, user string) (*dlmm.PortfolioTotalResponse, error) {
+	f.record("GetPortfolioTotal", user)
+	if f.GetPortfolioTotalFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetPortfolioTotalFunc(ctx, user)
+}
+
+// GetPools records the call and calls GetPoolsFunc.
+func (f *FakeDLMM) GetPools(ctx context.Context, addresses []string, opts *dlmm.BatchOptions) []dlmm.PoolResult {
+	f.record("GetPools", addresses, opts)
+	if f.GetPoolsFunc == nil {
+		return nil
+	}
+	return f.GetPoolsFunc(ctx, addresses, opts)
+}
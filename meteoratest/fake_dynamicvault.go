@@ -0,0 +1,103 @@
+// Code generated by fakegen. DO NOT EDIT.
+
+package meteoratest
+
+import (
+	"context"
+
+	"github.com/ua1984/meteora-go/dynamicvault"
+)
+
+// FakeDynamicVault is an in-memory fake of dynamicvault.API.
+// Each method records its arguments, except the context, and calls the
+// matching Func field. Unset fields make the method return ErrNotStubbed, or
+// zero values if it has no error result.
+type FakeDynamicVault struct {
+	callRecorder
+
+	// ListVaultInfoFunc implements ListVaultInfo.
+	ListVaultInfoFunc func(ctx context.Context) ([]dynamicvault.VaultInfo, error)
+
+	// ListVaultAddressesFunc implements ListVaultAddresses.
+	ListVaultAddressesFunc func(ctx context.Context) ([]dynamicvault.VaultAddress, error)
+
+	// GetVaultStateFunc implements GetVaultState.
+	GetVaultStateFunc func(ctx context.Context, tokenMint string) (*dynamicvault.VaultState, error)
+
+	// GetAPYStateFunc implements GetAPYState.
+	GetAPYStateFunc func(ctx context.Context, tokenMint string) (*dynamicvault.APYState, error)
+
+	// GetAPYByTimeRangeFunc implements GetAPYByTimeRange.
+	GetAPYByTimeRangeFunc func(ctx context.Context, tokenMint string, start int64, end int64) ([]dynamicvault.APYEntry, error)
+
+	// GetVirtualPriceFunc implements GetVirtualPrice.
+	GetVirtualPriceFunc func(ctx context.Context, tokenMint string, strategy string) ([]dynamicvault.VirtualPrice, error)
+
+	// GetVaultStatesFunc implements GetVaultStates.
+	GetVaultStatesFunc func(ctx context.Context, tokenMints []string, opts *dynamicvault.BatchOptions) []dynamicvault.VaultStateResult
+}
+
+var _ dynamicvault.API = (*FakeDynamicVault)(nil)
+
+// ListVaultInfo records the call and calls ListVaultInfoFunc.
+func (f *FakeDynamicVault) ListVaultInfo(ctx context.Context) ([]dynamicvault.VaultInfo, error) {
+	f.record("ListVaultInfo")
+	if f.ListVaultInfoFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListVaultInfoFunc(ctx)
+}
+
+// ListVaultAddresses records the call and calls ListVaultAddressesFunc.
+func (f *FakeDynamicVault) ListVaultAddresses(ctx context.Context) ([]dynamicvault.VaultAddress, error) {
+	f.record("ListVaultAddresses")
+	if f.ListVaultAddressesFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListVaultAddressesFunc(ctx)
+}
+
+// GetVaultState records the call and calls GetVaultStateFunc.
+func (f *FakeDynamicVault) GetVaultState(ctx context.Context, tokenMint string) (*dynamicvault.VaultState, error) {
+	f.record("GetVaultState", tokenMint)
+	if f.GetVaultStateFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetVaultStateFunc(ctx, tokenMint)
+}
+
+// GetAPYState records the call and calls GetAPYStateFunc.
+func (f *FakeDynamicVault) GetAPYState(ctx context.Context, tokenMint string) (*dynamicvault.APYState, error) {
+	f.record("GetAPYState", tokenMint)
+	if f.GetAPYStateFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetAPYStateFunc(ctx, tokenMint)
+}
+
+// GetAPYByTimeRange records the call and calls GetAPYByTimeRangeFunc.
+func (f *FakeDynamicVault) GetAPYByTimeRange(ctx context.Context, tokenMint string, start int64, end int64) ([]dynamicvault.APYEntry, error) {
+	f.record("GetAPYByTimeRange", tokenMint, start, end)
+	if f.GetAPYByTimeRangeFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetAPYByTimeRangeFunc(ctx, tokenMint, start, end)
+}
+
+// GetVirtualPrice records the call and calls GetVirtualPriceFunc.
+func (f *FakeDynamicVault) GetVirtualPrice(ctx context.Context, tokenMint string, strategy string) ([]dynamicvault.VirtualPrice, error) {
+	f.record("GetVirtualPrice", tokenMint, strategy)
+	if f.GetVirtualPriceFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetVirtualPriceFunc(ctx, tokenMint, strategy)
+}
+
+// GetVaultStates records the call and calls GetVaultStatesFunc.
+func (f *FakeDynamicVault) GetVaultStates(ctx context.Context, tokenMints []string, opts *dynamicvault.BatchOptions) []dynamicvault.VaultStateResult {
+	f.record("GetVaultStates", tokenMints, opts)
+	if f.GetVaultStatesFunc == nil {
+		return nil
+	}
+	return f.GetVaultStatesFunc(ctx, tokenMints, opts)
+}
@@ -0,0 +1,79 @@
+// Code generated by fakegen. DO NOT EDIT.
+
+package meteoratest
+
+import (
+	"context"
+
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+// FakeStake2Earn is an in-memory fake of stake2earn.API.
+// Each method records its arguments, except the context, and calls the
+// matching Func field. Unset fields make the method return ErrNotStubbed, or
+// zero values if it has no error result.
+type FakeStake2Earn struct {
+	callRecorder
+
+	// GetAnalyticsFunc implements GetAnalytics.
+	GetAnalyticsFunc func(ctx context.Context) (*stake2earn.Analytics, error)
+
+	// ListVaultsFunc implements ListVaults.
+	ListVaultsFunc func(ctx context.Context) (*stake2earn.VaultListResponse, error)
+
+	// FilterVaultsFunc implements FilterVaults.
+	FilterVaultsFunc func(ctx context.Context, params *stake2earn.FilterParams) (*stake2earn.VaultListResponse, error)
+
+	// GetVaultFunc implements GetVault.
+	GetVaultFunc func(ctx context.Context, address string) (*stake2earn.Vault, error)
+
+	// GetVaultsByPoolFunc implements GetVaultsByPool.
+	GetVaultsByPoolFunc func(ctx context.Context, poolAddresses []string, opts *stake2earn.BatchOptions) []stake2earn.VaultResult
+}
+
+var _ stake2earn.API = (*FakeStake2Earn)(nil)
+
+// GetAnalytics records the call and calls GetAnalyticsFunc.
+func (f *FakeStake2Earn) GetAnalytics(ctx context.Context) (*stake2earn.Analytics, error) {
+	f.record("GetAnalytics")
+	if f.GetAnalyticsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetAnalyticsFunc(ctx)
+}
+
+// ListVaults records the call and calls ListVaultsFunc.
+func (f *FakeStake2Earn) ListVaults(ctx context.Context) (*stake2earn.VaultListResponse, error) {
+	f.record("ListVaults")
+	if f.ListVaultsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.ListVaultsFunc(ctx)
+}
+
+// FilterVaults records the call and calls FilterVaultsFunc.
+func (f *FakeStake2Earn) FilterVaults(ctx context.Context, params *stake2earn.FilterParams) (*stake2earn.VaultListResponse, error) {
+	f.record("FilterVaults", params)
+	if f.FilterVaultsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.FilterVaultsFunc(ctx, params)
+}
+
+// GetVault records the call and calls GetVaultFunc.
+func (f *FakeStake2Earn) GetVault(ctx context.Context, address string) (*stake2earn.Vault, error) {
+	f.record("GetVault", address)
+	if f.GetVaultFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return f.GetVaultFunc(ctx, address)
+}
+
+// GetVaultsByPool records the call and calls GetVaultsByPoolFunc.
+func (f *FakeStake2Earn) GetVaultsByPool(ctx context.Context, poolAddresses []string, opts *stake2earn.BatchOptions) []stake2earn.VaultResult {
+	f.record("GetVaultsByPool", poolAddresses, opts)
+	if f.GetVaultsByPoolFunc == nil {
+		return nil
+	}
+	return f.GetVaultsByPoolFunc(ctx, poolAddresses, opts)
+}
@@ -0,0 +1,84 @@
+package meteoratest_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	meteora "github.com/ua1984/meteora-go"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/meteoratest"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+type FakeTestSuite struct {
+	suite.Suite
+}
+
+func TestFake(t *testing.T) {
+	suite.Run(t, new(FakeTestSuite))
+}
+
+func (s *FakeTestSuite) TestStubbedMethod() {
+	// Arrange
+	fake := &meteoratest.FakeDLMM{
+		GetPoolFunc: func(ctx context.Context, address string) (*dlmm.Pool, error) {
+			return &dlmm.Pool{Address: address, TVL: 42}, nil
+		},
+	}
+	client := &meteora.Client{DLMM: fake}
+
+	// Act
+	pool, err := client.DLMM.GetPool(context.Background(), "pool1")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("pool1", pool.Address)
+	s.Equal(42.0, pool.TVL)
+	s.Equal([]meteoratest.Call{{Method: "GetPool", Args: []any{"pool1"}}}, fake.Calls())
+}
+
+func (s *FakeTestSuite) TestUnstubbedMethod() {
+	// Arrange
+	fake := &meteoratest.FakeStake2Earn{}
+	params := &stake2earn.FilterParams{}
+
+	// Act
+	vaults, err := fake.FilterVaults(context.Background(), params)
+	results := fake.GetVaultsByPool(context.Background(), []string{"pool1"}, nil)
+
+	// Assert
+	s.ErrorIs(err, meteoratest.ErrNotStubbed)
+	s.Nil(vaults)
+	s.Nil(results)
+	s.Require().Len(fake.CallsTo("FilterVaults"), 1)
+	s.Same(params, fake.CallsTo("FilterVaults")[0].Args[0])
+	s.Len(fake.Calls(), 2)
+}
+
+func (s *FakeTestSuite) TestConcurrentCalls() {
+	// Arrange
+	fake := &meteoratest.FakeStake2Earn{
+		GetVaultFunc: func(ctx context.Context, address string) (*stake2earn.Vault, error) {
+			return &stake2earn.Vault{VaultAddress: address}, nil
+		},
+	}
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fake.GetVault(context.Background(), "vault1")
+		}()
+	}
+	wg.Wait()
+	calls := len(fake.CallsTo("GetVault"))
+	fake.ResetCalls()
+
+	// Assert
+	s.Equal(20, calls)
+	s.Empty(fake.Calls())
+}
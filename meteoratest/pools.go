@@ -0,0 +1,247 @@
+package meteoratest
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Routes shared by the DLMM and DAMM v2 datapi, which have the same shape.
+
+const (
+	defaultPoolSort      = "volume_24h:desc"
+	defaultPageSize      = 10
+	maxPoolPageSize      = 1000
+	maxGroupPageSize     = 100
+	defaultClosedLimit   = 10
+	maxClosedLimit       = 100
+	defaultTimeframe     = "24h"
+	defaultVolumeTW      = "volume_24h"
+	defaultFeeTVLRatioTW = "fee_tvl_ratio_24h"
+)
+
+func pick[T any](items []T, indexes []int) []T {
+	picked := make([]T, 0, len(indexes))
+	for _, i := range indexes {
+		picked = append(picked, items[i])
+	}
+	return picked
+}
+
+func listPools[P any](pools []P, q url.Values) (any, error) {
+	indexes, err := selectDocs(pools, q, defaultPoolSort)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(pick(pools, indexes), q, defaultPageSize, maxPoolPageSize)
+}
+
+func getPool[P any](pools []P, address string) (any, error) {
+	for _, p := range pools {
+		if a, _ := toDoc(p)["address"].(string); a == address {
+			return p, nil
+		}
+	}
+	return nil, errNotFound("pool %s", address)
+}
+
+// poolGroup mirrors dlmm.PoolGroup and dammv2.PoolGroup.
+type poolGroup struct {
+	LexicalOrderMints string  `json:"lexical_order_mints"`
+	GroupName         string  `json:"group_name"`
+	TokenX            string  `json:"token_x"`
+	TokenY            string  `json:"token_y"`
+	PoolCount         int     `json:"pool_count"`
+	TotalTVL          float64 `json:"total_tvl"`
+	TotalVolume       float64 `json:"total_volume"`
+	MaxFeeTVLRatio    float64 `json:"max_fee_tvl_ratio"`
+	HasFarm           bool    `json:"has_farm"`
+	MaxFarmAPR        float64 `json:"max_farm_apr"`
+}
+
+// lexicalOrderMints returns the group key of a pool: its two token mints in
+// lexical order, joined by "-", and the matching symbols.
+func lexicalOrderMints(d doc) (key, name string) {
+	mintX, _ := d.path("token_x.address")
+	mintY, _ := d.path("token_y.address")
+	symX, _ := d.path("token_x.symbol")
+	symY, _ := d.path("token_y.symbol")
+	mints := []string{asString(mintX), asString(mintY)}
+	symbols := []string{asString(symX), asString(symY)}
+	if mints[1] < mints[0] {
+		mints[0], mints[1] = mints[1], mints[0]
+		symbols[0], symbols[1] = symbols[1], symbols[0]
+	}
+	return strings.Join(mints, "-"), strings.Join(symbols, "-")
+}
+
+func asString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func asFloat(v any) float64 {
+	f, _ := v.(float64)
+	return f
+}
+
+// listGroups filters pools with query and filter_by, aggregates them by token
+// pair, then sorts and paginates the groups. Volume is summed over the
+// volume_tw window and the fee/TVL ratio is the maximum over fee_tvl_ratio_tw.
+func listGroups[P any](pools []P, q url.Values) (any, error) {
+	filter := url.Values{"query": q["query"], "filter_by": q["filter_by"]}
+	indexes, err := selectDocs(pools, filter, "")
+	if err != nil {
+		return nil, err
+	}
+
+	volumeTW := q.Get("volume_tw")
+	if volumeTW == "" {
+		volumeTW = defaultVolumeTW
+	}
+	feeTVLRatioTW := q.Get("fee_tvl_ratio_tw")
+	if feeTVLRatioTW == "" {
+		feeTVLRatioTW = defaultFeeTVLRatioTW
+	}
+
+	byKey := map[string]*poolGroup{}
+	var groups []*poolGroup
+	for _, i := range indexes {
+		d := toDoc(pools[i])
+		key, name := lexicalOrderMints(d)
+		g, ok := byKey[key]
+		if !ok {
+			mintX, mintY, _ := strings.Cut(key, "-")
+			g = &poolGroup{LexicalOrderMints: key, GroupName: name, TokenX: mintX, TokenY: mintY}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		volume, ok := d.field(volumeTW)
+		if !ok {
+			return nil, errBadRequest("invalid volume_tw %q", volumeTW)
+		}
+		ratio, ok := d.field(feeTVLRatioTW)
+		if !ok {
+			return nil, errBadRequest("invalid fee_tvl_ratio_tw %q", feeTVLRatioTW)
+		}
+		hasFarm, _ := d["has_farm"].(bool)
+
+		g.PoolCount++
+		g.TotalTVL += asFloat(d["tvl"])
+		g.TotalVolume += asFloat(volume)
+		g.MaxFeeTVLRatio = max(g.MaxFeeTVLRatio, asFloat(ratio))
+		g.HasFarm = g.HasFarm || hasFarm
+		g.MaxFarmAPR = max(g.MaxFarmAPR, asFloat(d["farm_apr"]))
+	}
+
+	values := make([]poolGroup, len(groups))
+	for i, g := range groups {
+		values[i] = *g
+	}
+	sort.SliceStable(values, func(a, b int) bool { return values[a].LexicalOrderMints < values[b].LexicalOrderMints })
+	order, err := selectDocs(values, url.Values{"sort_by": q["sort_by"]}, defaultPoolSort)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(pick(values, order), q, defaultPageSize, maxGroupPageSize)
+}
+
+func getGroup[P any](pools []P, key string, q url.Values) (any, error) {
+	var members []P
+	for _, p := range pools {
+		if k, _ := lexicalOrderMints(toDoc(p)); k == key {
+			members = append(members, p)
+		}
+	}
+	if len(members) == 0 {
+		return nil, errNotFound("group %s", key)
+	}
+	return listPools(members, q)
+}
+
+// timeSeriesResponse mirrors the OHLCV and volume history responses.
+type timeSeriesResponse[T any] struct {
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Timeframe string `json:"timeframe"`
+	Data      []T    `json:"data"`
+}
+
+// timeSeries returns the points of series within the start_time and end_time
+// range, in timestamp order. Unknown pools are reported as not found.
+func timeSeries[P, T any](pools []P, address string, series []T, timestamp func(T) int64, q url.Values) (any, error) {
+	if _, err := getPool(pools, address); err != nil {
+		return nil, err
+	}
+
+	timestamps := make([]int64, len(series))
+	for i, point := range series {
+		timestamps[i] = timestamp(point)
+	}
+	start, end, err := timeRange(q, timestamps)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &timeSeriesResponse[T]{StartTime: start, EndTime: end, Timeframe: q.Get("timeframe"), Data: []T{}}
+	if resp.Timeframe == "" {
+		resp.Timeframe = defaultTimeframe
+	}
+	for _, point := range series {
+		if ts := timestamp(point); ts >= start && ts <= end {
+			resp.Data = append(resp.Data, point)
+		}
+	}
+	sort.SliceStable(resp.Data, func(a, b int) bool { return timestamp(resp.Data[a]) < timestamp(resp.Data[b]) })
+	return resp, nil
+}
+
+// cursorResponse mirrors the cursor-paginated closed positions responses.
+type cursorResponse[T any] struct {
+	Limit      int64   `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+	Data       []T     `json:"data"`
+}
+
+// closedPositions returns positions closed within the start_time and end_time
+// range, most recently closed first, optionally limited to one pool. The
+// cursor is the offset of the next position.
+func closedPositions[T any](positions []T, closedAt func(T) int64, pool func(T) string, q url.Values) (any, error) {
+	limit, err := intParam(q, "limit", defaultClosedLimit, 1, maxClosedLimit)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := intParam(q, "next_cursor", 0, 0, len(positions))
+	if err != nil {
+		return nil, err
+	}
+	start, hasStart, err := int64Param(q, "start_time")
+	if err != nil {
+		return nil, err
+	}
+	end, hasEnd, err := int64Param(q, "end_time")
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []T
+	for _, p := range positions {
+		if (hasStart && closedAt(p) < start) || (hasEnd && closedAt(p) > end) {
+			continue
+		}
+		if q.Has("pool") && pool(p) != q.Get("pool") {
+			continue
+		}
+		matched = append(matched, p)
+	}
+	sort.SliceStable(matched, func(a, b int) bool { return closedAt(matched[a]) > closedAt(matched[b]) })
+
+	from, to := min(offset, len(matched)), min(offset+limit, len(matched))
+	resp := &cursorResponse[T]{Limit: int64(limit), Data: append([]T{}, matched[from:to]...)}
+	if to < len(matched) {
+		next := strconv.Itoa(to)
+		resp.NextCursor = &next
+	}
+	return resp, nil
+}
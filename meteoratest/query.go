@@ -0,0 +1,437 @@
+package meteoratest
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// windows are the time windows accepted in <metric>_<window> field names.
+var windows = map[string]bool{"5m": true, "30m": true, "1h": true, "2h": true, "4h": true, "12h": true, "24h": true}
+
+// fieldAliases maps filter_by and sort_by field names to dotted JSON paths
+// where they differ from the JSON field names of the served records.
+var fieldAliases = map[string]string{
+	"pool_address":    "address",
+	"pool_created_at": "created_at",
+	"fee_pct":         "pool_config.base_fee_pct",
+	"bin_step":        "pool_config.bin_step",
+	"token_x":         "token_x.address",
+	"token_y":         "token_y.address",
+	"fee":             "fees",
+	"tvl":             "total_tvl",
+	"volume":          "total_volume",
+	"fee_tvl_ratio":   "max_fee_tvl_ratio",
+	"farm_apr":        "max_farm_apr",
+}
+
+// doc is a JSON record viewed as a generic object for filtering and sorting.
+type doc map[string]any
+
+func toDoc(v any) doc {
+	b, _ := json.Marshal(v)
+	var d doc
+	json.Unmarshal(b, &d)
+	return d
+}
+
+func (d doc) path(p string) (any, bool) {
+	var cur any = map[string]any(d)
+	for _, key := range strings.Split(p, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// field resolves a filter_by or sort_by field name. Scalar JSON fields match
+// by name, then aliases apply, then <metric>_<window> names are looked up in
+// the metric's time buckets. Windows missing from the buckets resolve to 0.
+func (d doc) field(name string) (any, bool) {
+	if v, ok := d[name]; ok {
+		if _, isObj := v.(map[string]any); !isObj {
+			return v, true
+		}
+	}
+	if alias, ok := fieldAliases[name]; ok {
+		if v, ok := d.path(alias); ok {
+			return v, true
+		}
+	}
+
+	i := strings.LastIndex(name, "_")
+	if i <= 0 || !windows[name[i+1:]] {
+		return nil, false
+	}
+	metric, window := name[:i], name[i+1:]
+	v, ok := d[metric]
+	if !ok {
+		alias, aliased := fieldAliases[metric]
+		if !aliased {
+			return nil, false
+		}
+		if v, ok = d.path(alias); !ok {
+			return nil, false
+		}
+	}
+	if buckets, ok := v.(map[string]any); ok {
+		if bv, ok := buckets[window]; ok {
+			return bv, true
+		}
+		return 0.0, true
+	}
+	return v, true
+}
+
+// condition is a single filter_by expression.
+type condition struct {
+	field string
+	op    string
+	value string
+}
+
+// parseFilter parses a filter_by expression of the form
+// "<field><op><value> [&& ...]".
+func parseFilter(s string) ([]condition, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var conds []condition
+	for _, expr := range strings.Split(s, "&&") {
+		expr = strings.TrimSpace(expr)
+		i := strings.IndexAny(expr, "=<>")
+		if i <= 0 {
+			return nil, errBadRequest("invalid filter_by expression %q", expr)
+		}
+		op := expr[i : i+1]
+		if op != "=" && i+1 < len(expr) && expr[i+1] == '=' {
+			op += "="
+		}
+		conds = append(conds, condition{
+			field: strings.TrimSpace(expr[:i]),
+			op:    op,
+			value: strings.TrimSpace(expr[i+len(op):]),
+		})
+	}
+	return conds, nil
+}
+
+func (c condition) match(d doc) (bool, error) {
+	v, ok := d.field(c.field)
+	if !ok {
+		return false, errBadRequest("unknown filter_by field %q", c.field)
+	}
+
+	switch tv := v.(type) {
+	case float64:
+		want, err := strconv.ParseFloat(c.value, 64)
+		if err != nil {
+			return false, errBadRequest("invalid numeric value %q for %s", c.value, c.field)
+		}
+		switch c.op {
+		case "=":
+			return tv == want, nil
+		case ">":
+			return tv > want, nil
+		case ">=":
+			return tv >= want, nil
+		case "<":
+			return tv < want, nil
+		case "<=":
+			return tv <= want, nil
+		}
+	case bool:
+		want, err := strconv.ParseBool(c.value)
+		if err != nil || c.op != "=" {
+			return false, errBadRequest("invalid boolean condition on %s", c.field)
+		}
+		return tv == want, nil
+	case string:
+		if c.op != "=" {
+			return false, errBadRequest("operator %s is not allowed on text field %s", c.op, c.field)
+		}
+		if strings.HasPrefix(c.value, "[") && strings.HasSuffix(c.value, "]") {
+			for _, alt := range strings.Split(c.value[1:len(c.value)-1], "|") {
+				if tv == strings.TrimSpace(alt) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return tv == c.value, nil
+	}
+	return false, errBadRequest("field %s cannot be filtered", c.field)
+}
+
+// sortKey is a single sort_by field.
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// parseSort parses a comma-separated sort_by value of the form
+// "<field>:<direction>[,...]".
+func parseSort(s string) ([]sortKey, error) {
+	var keys []sortKey
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field, dir, _ := strings.Cut(part, ":")
+		switch dir {
+		case "", "desc":
+			keys = append(keys, sortKey{field: field, desc: true})
+		case "asc":
+			keys = append(keys, sortKey{field: field})
+		default:
+			return nil, errBadRequest("invalid sort direction %q", dir)
+		}
+	}
+	return keys, nil
+}
+
+func compareValues(a, b any) int {
+	switch av := a.(type) {
+	case float64:
+		bv, _ := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+	case string:
+		bv, _ := b.(string)
+		return strings.Compare(av, bv)
+	case bool:
+		bv, _ := b.(bool)
+		switch {
+		case !av && bv:
+			return -1
+		case av && !bv:
+			return 1
+		}
+	}
+	return 0
+}
+
+// matchesQuery reports whether the record's name, address or tokens contain
+// query, ignoring case.
+func matchesQuery(d doc, query string) bool {
+	query = strings.ToLower(query)
+	for _, key := range []string{"name", "group_name", "address", "lexical_order_mints", "token_x", "token_y"} {
+		var candidates []any
+		switch v := d[key].(type) {
+		case map[string]any:
+			candidates = []any{v["address"], v["symbol"], v["name"]}
+		default:
+			candidates = []any{v}
+		}
+		for _, c := range candidates {
+			if s, ok := c.(string); ok && strings.Contains(strings.ToLower(s), query) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectDocs applies the query, filter_by and sort_by parameters to items and
+// returns the indexes of the matching items in result order.
+func selectDocs[T any](items []T, q url.Values, defaultSort string) ([]int, error) {
+	conds, err := parseFilter(q.Get("filter_by"))
+	if err != nil {
+		return nil, err
+	}
+	sortBy := q.Get("sort_by")
+	if sortBy == "" {
+		sortBy = defaultSort
+	}
+	keys, err := parseSort(sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]doc, len(items))
+	var indexes []int
+	for i := range items {
+		docs[i] = toDoc(items[i])
+		if query := q.Get("query"); query != "" && !matchesQuery(docs[i], query) {
+			continue
+		}
+		matched := true
+		for _, c := range conds {
+			ok, err := c.match(docs[i])
+			if err != nil {
+				return nil, err
+			}
+			matched = matched && ok
+		}
+		if matched {
+			indexes = append(indexes, i)
+		}
+	}
+
+	for _, k := range keys {
+		if len(docs) > 0 {
+			if _, ok := docs[0].field(k.field); !ok {
+				return nil, errBadRequest("unknown sort_by field %q", k.field)
+			}
+		}
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		for _, k := range keys {
+			av, _ := docs[indexes[a]].field(k.field)
+			bv, _ := docs[indexes[b]].field(k.field)
+			if c := compareValues(av, bv); c != 0 {
+				return (c < 0) != k.desc
+			}
+		}
+		return false
+	})
+
+	return indexes, nil
+}
+
+// paginated mirrors the page-based response shape shared by the DLMM and DAMM v2 APIs.
+type paginated[T any] struct {
+	Total       int `json:"total"`
+	Pages       int `json:"pages"`
+	CurrentPage int `json:"current_page"`
+	PageSize    int `json:"page_size"`
+	Data        []T `json:"data"`
+}
+
+// paginate returns one page of items using the 1-based page and page_size
+// parameters.
+func paginate[T any](items []T, q url.Values, defaultSize, maxSize int) (*paginated[T], error) {
+	page, err := intParam(q, "page", 1, 1, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	size, err := intParam(q, "page_size", defaultSize, 1, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := pageBounds(len(items), page-1, size)
+	return &paginated[T]{
+		Total:       len(items),
+		Pages:       (len(items) + size - 1) / size,
+		CurrentPage: page,
+		PageSize:    size,
+		Data:        append([]T{}, items[start:end]...),
+	}, nil
+}
+
+// pageBounds returns the slice bounds of the 0-based page of n items.
+func pageBounds(n, page, size int) (int, int) {
+	start := page * size
+	if start > n || start < 0 {
+		start = n
+	}
+	end := start + size
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// intParam parses an optional integer parameter within [min, max].
+func intParam(q url.Values, name string, def, min, max int) (int, error) {
+	s := q.Get(name)
+	if s == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < min || v > max {
+		return 0, errBadRequest("invalid %s %q", name, s)
+	}
+	return v, nil
+}
+
+// int64Param parses an optional int64 parameter; ok is false when it is absent.
+func int64Param(q url.Values, name string) (v int64, ok bool, err error) {
+	s := q.Get(name)
+	if s == "" {
+		return 0, false, nil
+	}
+	v, err = strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, false, errBadRequest("invalid %s %q", name, s)
+	}
+	return v, true, nil
+}
+
+// boolParam parses an optional boolean parameter; ok is false when it is absent.
+func boolParam(q url.Values, name string) (v bool, ok bool, err error) {
+	s := q.Get(name)
+	if s == "" {
+		return false, false, nil
+	}
+	v, err = strconv.ParseBool(s)
+	if err != nil {
+		return false, false, errBadRequest("invalid %s %q", name, s)
+	}
+	return v, true, nil
+}
+
+// timeRange resolves the start_time and end_time parameters to an inclusive
+// range, defaulting to the range covered by timestamps.
+func timeRange(q url.Values, timestamps []int64) (int64, int64, error) {
+	start, hasStart, err := int64Param(q, "start_time")
+	if err != nil {
+		return 0, 0, err
+	}
+	end, hasEnd, err := int64Param(q, "end_time")
+	if err != nil {
+		return 0, 0, err
+	}
+	if !hasStart {
+		start = math.MinInt64
+	}
+	if !hasEnd {
+		end = math.MaxInt64
+	}
+
+	lo, hi := end, start
+	for _, ts := range timestamps {
+		if ts >= start && ts <= end {
+			lo, hi = min(lo, ts), max(hi, ts)
+		}
+	}
+	if !hasStart {
+		start = lo
+	}
+	if !hasEnd {
+		end = hi
+	}
+	if start > end {
+		start, end = 0, 0
+	}
+	return start, end, nil
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
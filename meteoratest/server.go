@@ -0,0 +1,275 @@
+package meteoratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	meteora "github.com/ua1984/meteora-go"
+)
+
+// Path prefixes under which the Server exposes each API.
+const (
+	DLMMPrefix         = "/dlmm"
+	DAMMv2Prefix       = "/dammv2"
+	DAMMv1Prefix       = "/dammv1"
+	Stake2EarnPrefix   = "/stake2earn"
+	DynamicVaultPrefix = "/dynamicvault"
+)
+
+// Fault makes the Server misbehave for matching requests.
+type Fault struct {
+	// Path limits the fault to requests whose path starts with Path, including
+	// the service prefix (e.g., "/dlmm/pools"). Empty matches every request.
+	Path string
+
+	// Status is returned instead of the normal response when non-zero
+	// (e.g., http.StatusTooManyRequests or http.StatusServiceUnavailable).
+	Status int
+
+	// Latency delays the response, or the fault status if Status is set.
+	Latency time.Duration
+
+	// Count is the number of requests the fault applies to.
+	// Zero applies it to every matching request until ClearFaults is called.
+	Count int
+}
+
+// Request is a request received by the Server.
+type Request struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Path is the request path, including the service prefix.
+	Path string
+
+	// Query holds the query parameters.
+	Query url.Values
+}
+
+// Server is an in-process fake of the Meteora APIs backed by an in-memory
+// store. Each API is served under its own path prefix on a single
+// httptest.Server.
+type Server struct {
+	// URL is the base URL of the underlying httptest.Server.
+	URL string
+
+	srv *httptest.Server
+
+	mu       sync.RWMutex
+	data     *Data
+	faults   []*Fault
+	requests []Request
+}
+
+// NewServer starts a Server with an empty store. Call Close when done.
+func NewServer() *Server {
+	s := &Server{data: newData()}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the Server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Options returns meteora options pointing every service client at the Server.
+func (s *Server) Options() []meteora.Option {
+	return []meteora.Option{
+		meteora.WithDLMMBaseURL(s.URL + DLMMPrefix),
+		meteora.WithDAMMv2BaseURL(s.URL + DAMMv2Prefix),
+		meteora.WithDAMMv1BaseURL(s.URL + DAMMv1Prefix),
+		meteora.WithStake2EarnBaseURL(s.URL + Stake2EarnPrefix),
+		meteora.WithDynamicVaultBaseURL(s.URL + DynamicVaultPrefix),
+	}
+}
+
+// Client returns a meteora.Client talking to the Server. Additional options
+// are applied after the base URL options.
+func (s *Server) Client(opts ...meteora.Option) *meteora.Client {
+	return meteora.New(append(s.Options(), opts...)...)
+}
+
+// Seed calls fn with the store while holding the write lock, so records can be
+// added or replaced safely while requests are being served.
+func (s *Server) Seed(fn func(d *Data)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.data)
+}
+
+// Reset empties the store and removes all faults and recorded requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = newData()
+	s.faults = nil
+	s.requests = nil
+}
+
+// InjectFault adds a fault. Faults are checked in the order they were added
+// and the first matching one applies.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far, including faulted ones.
+func (s *Server) Requests() []Request {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	fault := s.record(r)
+	if fault != nil {
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault.Status != 0 {
+			writeError(w, &httpError{status: fault.Status, msg: http.StatusText(fault.Status)})
+			return
+		}
+	}
+
+	rt, args := s.match(r.Method, r.URL.Path)
+	if rt == nil {
+		writeError(w, errNotFound("route %s %s", r.Method, r.URL.Path))
+		return
+	}
+
+	// Responses may share slices with the store, so encode under the lock.
+	s.mu.RLock()
+	resp, err := rt.handle(s.data, r.URL.Query(), args)
+	var body []byte
+	if err == nil {
+		body, err = json.Marshal(resp)
+	}
+	s.mu.RUnlock()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// record logs r and returns the fault to apply, if any.
+func (s *Server) record(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()})
+	for i, f := range s.faults {
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		applied := *f
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &applied
+	}
+	return nil
+}
+
+// route is a single API endpoint. Pattern segments equal to "*" match any
+// single path segment and are passed to handle in order.
+type route struct {
+	method  string
+	pattern string
+	handle  func(d *Data, q url.Values, args []string) (any, error)
+}
+
+func (s *Server) match(method, path string) (*route, []string) {
+	for prefix, routes := range map[string][]route{
+		DLMMPrefix:         dlmmRoutes,
+		DAMMv2Prefix:       dammv2Routes,
+		DAMMv1Prefix:       dammv1Routes,
+		Stake2EarnPrefix:   stake2earnRoutes,
+		DynamicVaultPrefix: dynamicVaultRoutes,
+	} {
+		rest, ok := strings.CutPrefix(path, prefix)
+		if !ok || (rest != "" && rest[0] != '/') {
+			continue
+		}
+		segments := strings.Split(strings.Trim(rest, "/"), "/")
+		for i := range routes {
+			if routes[i].method != method {
+				continue
+			}
+			if args, ok := matchSegments(strings.Split(strings.Trim(routes[i].pattern, "/"), "/"), segments); ok {
+				return &routes[i], args
+			}
+		}
+	}
+	return nil, nil
+}
+
+func matchSegments(pattern, segments []string) ([]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	var args []string
+	for i, p := range pattern {
+		switch {
+		case p == "*" && segments[i] != "":
+			args = append(args, segments[i])
+		case p != segments[i]:
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+// httpError is an error response with a status code.
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+func errBadRequest(format string, args ...any) error {
+	return &httpError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+func errNotFound(format string, args ...any) error {
+	return &httpError{status: http.StatusNotFound, msg: fmt.Sprintf(format, args...) + " not found"}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if he, ok := err.(*httpError); ok {
+		status = he.status
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
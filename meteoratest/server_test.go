@@ -0,0 +1,405 @@
+package meteoratest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	meteora "github.com/ua1984/meteora-go"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/dynamicvault"
+	"github.com/ua1984/meteora-go/internal/httpclient"
+	"github.com/ua1984/meteora-go/meteoratest"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	srv    *meteoratest.Server
+	client *meteora.Client
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	s.srv = meteoratest.NewServer()
+	s.client = s.srv.Client()
+}
+
+func (s *ServerTestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func dlmmPool(address, mintX, mintY string, tvl, volume24h float64) dlmm.Pool {
+	return dlmm.Pool{
+		Address: address,
+		Name:    mintX + "-" + mintY,
+		TokenX:  dlmm.Token{Address: mintX, Symbol: mintX},
+		TokenY:  dlmm.Token{Address: mintY, Symbol: mintY},
+		TVL:     tvl,
+		Volume:  dlmm.TimeBuckets{Hour24: volume24h},
+		Fees:    dlmm.TimeBuckets{Hour24: volume24h / 100},
+	}
+}
+
+func (s *ServerTestSuite) seedDLMMPools() {
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DLMM.Pools = []dlmm.Pool{
+			dlmmPool("pool1", "SOL", "USDC", 100, 500),
+			dlmmPool("pool2", "USDC", "SOL", 300, 100),
+			dlmmPool("pool3", "JUP", "SOL", 50, 900),
+			dlmmPool("pool4", "BONK", "SOL", 1000, 10),
+		}
+		d.DLMM.Pools[3].IsBlacklisted = true
+	})
+}
+
+func (s *ServerTestSuite) TestDLMMListPools() {
+	s.seedDLMMPools()
+
+	tests := []struct {
+		name      string
+		params    *dlmm.ListPoolsParams
+		wantTotal int
+		wantPages int
+		want      []string
+	}{
+		{
+			name:      "should sort by 24h volume descending by default",
+			params:    nil,
+			wantTotal: 4,
+			wantPages: 1,
+			want:      []string{"pool3", "pool1", "pool2", "pool4"},
+		},
+		{
+			name:      "should filter, sort and paginate",
+			params:    &dlmm.ListPoolsParams{FilterBy: meteora.String("is_blacklisted=false && tvl>=100"), SortBy: meteora.String("tvl:asc"), Page: meteora.Int(2), PageSize: meteora.Int(1)},
+			wantTotal: 2,
+			wantPages: 2,
+			want:      []string{"pool2"},
+		},
+		{
+			name:      "should match multi-value text filters",
+			params:    &dlmm.ListPoolsParams{FilterBy: meteora.String("pool_address=[pool1|pool4]"), SortBy: meteora.String("fee_24h:asc")},
+			wantTotal: 2,
+			wantPages: 1,
+			want:      []string{"pool4", "pool1"},
+		},
+		{
+			name:      "should search by query",
+			params:    &dlmm.ListPoolsParams{Query: meteora.String("jup")},
+			wantTotal: 1,
+			wantPages: 1,
+			want:      []string{"pool3"},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			resp, err := s.client.DLMM.ListPools(context.Background(), tt.params)
+
+			// Assert
+			s.Require().NoError(err)
+			s.Equal(tt.wantTotal, resp.Total)
+			s.Equal(tt.wantPages, resp.Pages)
+			var got []string
+			for _, p := range resp.Data {
+				got = append(got, p.Address)
+			}
+			s.Equal(tt.want, got)
+		})
+	}
+
+	s.Run("should reject invalid filters", func() {
+		// Act
+		_, err := s.client.DLMM.ListPools(context.Background(), &dlmm.ListPoolsParams{FilterBy: meteora.String("unknown_field>1")})
+
+		// Assert
+		var apiErr *httpclient.APIError
+		s.Require().True(errors.As(err, &apiErr))
+		s.Equal(http.StatusBadRequest, apiErr.StatusCode)
+	})
+}
+
+func (s *ServerTestSuite) TestDLMMGroups() {
+	// Arrange
+	s.seedDLMMPools()
+
+	// Act
+	groups, err := s.client.DLMM.ListGroups(context.Background(), &dlmm.ListGroupsParams{SortBy: meteora.String("tvl:desc")})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Equal(3, groups.Total)
+	s.Equal("BONK-SOL", groups.Data[0].LexicalOrderMints)
+	s.Equal("SOL-USDC", groups.Data[1].LexicalOrderMints)
+	s.Equal(2, groups.Data[1].PoolCount)
+	s.Equal(400.0, groups.Data[1].TotalTVL)
+	s.Equal(600.0, groups.Data[1].TotalVolume)
+
+	// Act
+	members, err := s.client.DLMM.GetGroup(context.Background(), "SOL-USDC", nil)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(2, members.Total)
+}
+
+func (s *ServerTestSuite) TestDLMMPoolRoutes() {
+	// Arrange
+	s.seedDLMMPools()
+	s.srv.Seed(func(d *meteoratest.Data) {
+		for ts := int64(100); ts <= 500; ts += 100 {
+			d.DLMM.OHLCV["pool1"] = append(d.DLMM.OHLCV["pool1"], dlmm.OHLCV{Timestamp: ts, Close: float64(ts)})
+		}
+	})
+
+	// Act
+	pool, err := s.client.DLMM.GetPool(context.Background(), "pool1")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(100.0, pool.TVL)
+
+	// Act
+	start, end := int64(200), int64(400)
+	ohlcv, err := s.client.DLMM.GetOHLCV(context.Background(), "pool1", &dlmm.OHLCVParams{TimeframeBasedParams: dlmm.TimeframeBasedParams{StartTime: &start, EndTime: &end}})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Len(ohlcv.Data, 3)
+	s.Equal("24h", ohlcv.Timeframe)
+	s.Equal(int64(200), ohlcv.StartTime)
+
+	// Act
+	metrics, err := s.client.DLMM.GetProtocolMetrics(context.Background())
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(4, metrics.TotalPools)
+	s.Equal(1450.0, metrics.TotalTVL)
+
+	// Act
+	_, err = s.client.DLMM.GetPool(context.Background(), "missing")
+
+	// Assert
+	var apiErr *httpclient.APIError
+	s.Require().True(errors.As(err, &apiErr))
+	s.Equal(http.StatusNotFound, apiErr.StatusCode)
+}
+
+func (s *ServerTestSuite) TestDLMMClosedPositionsCursor() {
+	// Arrange
+	s.srv.Seed(func(d *meteoratest.Data) {
+		for i := 1; i <= 5; i++ {
+			d.DLMM.ClosedPositions["wallet"] = append(d.DLMM.ClosedPositions["wallet"], dlmm.ClosedPosition{
+				PositionAddress: fmt.Sprintf("pos%d", i),
+				PoolAddress:     "pool1",
+				ClosedAt:        int64(i * 100),
+			})
+		}
+	})
+
+	// Act
+	var got []string
+	params := &dlmm.GetClosedPositionsParams{Limit: meteora.Int(2)}
+	for {
+		resp, err := s.client.DLMM.GetClosedPositions(context.Background(), "wallet", params)
+		s.Require().NoError(err)
+		for _, p := range resp.Data {
+			got = append(got, p.PositionAddress)
+		}
+		if resp.NextCursor == nil {
+			break
+		}
+		params.NextCursor = resp.NextCursor
+	}
+
+	// Assert
+	s.Equal([]string{"pos5", "pos4", "pos3", "pos2", "pos1"}, got)
+}
+
+func (s *ServerTestSuite) TestDAMMv1() {
+	// Arrange
+	s.srv.Seed(func(d *meteoratest.Data) {
+		for i := 0; i < 5; i++ {
+			d.DAMMv1.Pools = append(d.DAMMv1.Pools, dammv1.Pool{
+				PoolAddress:    fmt.Sprintf("pool%d", i),
+				PoolName:       fmt.Sprintf("POOL%d", i),
+				PoolTokenMints: []string{fmt.Sprintf("mint%d", i), "SOL"},
+				PoolTVL:        fmt.Sprintf("%d", i*100),
+				PoolType:       "dynamic",
+			})
+		}
+	})
+
+	// Act
+	result, err := s.client.DAMMv1.SearchPools(context.Background(), &dammv1.SearchParams{Page: 0, Size: 2, PoolsToTop: []string{"pool1"}})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(5, result.TotalCount)
+	s.Require().Len(result.Data, 2)
+	s.Equal("pool1", result.Data[0].PoolAddress)
+	s.Equal("pool4", result.Data[1].PoolAddress)
+
+	// Act
+	minTVL := 250.0
+	pools, err := s.client.DAMMv1.ListPools(context.Background(), &dammv1.ListPoolsParams{Address: []string{"pool2", "pool3"}, HideLowTVL: &minTVL})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(pools, 1)
+	s.Equal("pool3", pools[0].PoolAddress)
+}
+
+func (s *ServerTestSuite) TestStake2EarnFilterLimit() {
+	// Arrange
+	var pools []string
+	s.srv.Seed(func(d *meteoratest.Data) {
+		for i := 0; i < 150; i++ {
+			pool := fmt.Sprintf("pool%d", i)
+			pools = append(pools, pool)
+			d.Stake2Earn.Vaults = append(d.Stake2Earn.Vaults, stake2earn.Vault{VaultAddress: "vault-" + pool, PoolAddress: pool, TotalStakedAmountUSD: 1})
+		}
+	})
+
+	// Act
+	resp, err := s.client.Stake2Earn.FilterVaults(context.Background(), &stake2earn.FilterParams{PoolAddresses: pools})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(150, resp.Total)
+
+	// Act
+	query := url.Values{"pool_address": pools[:101]}
+	raw, err := http.Get(s.srv.URL + meteoratest.Stake2EarnPrefix + "/vault/filter?" + query.Encode())
+
+	// Assert
+	s.Require().NoError(err)
+	raw.Body.Close()
+	s.Equal(http.StatusBadRequest, raw.StatusCode)
+
+	// Act
+	analytics, err := s.client.Stake2Earn.GetAnalytics(context.Background())
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(150, analytics.TotalFeeVaults)
+	s.Equal(150.0, analytics.TotalStakedAmountUSD)
+}
+
+func (s *ServerTestSuite) TestDynamicVault() {
+	// Arrange
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DynamicVault.Vaults = []dynamicvault.VaultInfo{{Symbol: "USDC", TokenAddress: "usdc-mint", Pubkey: "vault1", LPMint: "lp1"}}
+		d.DynamicVault.APYHistory["usdc-mint"] = []dynamicvault.APYEntry{{APY: 1, Timestamp: 10}, {APY: 2, Timestamp: 20}, {APY: 3, Timestamp: 30}}
+	})
+
+	// Act
+	state, err := s.client.DynamicVault.GetVaultState(context.Background(), "usdc-mint")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("vault1", state.Pubkey)
+
+	// Act
+	addresses, err := s.client.DynamicVault.ListVaultAddresses(context.Background())
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal([]dynamicvault.VaultAddress{{Symbol: "USDC", VaultAddress: "vault1", LPMintAddress: "lp1"}}, addresses)
+
+	// Act
+	entries, err := s.client.DynamicVault.GetAPYByTimeRange(context.Background(), "usdc-mint", 15, 30)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Len(entries, 2)
+}
+
+func (s *ServerTestSuite) TestFaults() {
+	s.seedDLMMPools()
+	fastRetry := func() *dlmm.Client {
+		return dlmm.NewClient(httpclient.NewWithRetryConfig(s.srv.URL+meteoratest.DLMMPrefix, nil, 2, time.Millisecond, time.Millisecond))
+	}
+
+	s.Run("should recover from transient 429s", func() {
+		// Arrange
+		s.srv.InjectFault(meteoratest.Fault{Path: "/dlmm/pools", Status: http.StatusTooManyRequests, Count: 2})
+		before := len(s.srv.Requests())
+
+		// Act
+		_, err := fastRetry().GetPool(context.Background(), "pool1")
+
+		// Assert
+		s.NoError(err)
+		s.Equal(3, len(s.srv.Requests())-before)
+	})
+
+	s.Run("should fail on persistent 5xx", func() {
+		// Arrange
+		s.srv.InjectFault(meteoratest.Fault{Path: "/dlmm", Status: http.StatusServiceUnavailable})
+		defer s.srv.ClearFaults()
+
+		// Act
+		_, err := fastRetry().GetPool(context.Background(), "pool1")
+
+		// Assert
+		var apiErr *httpclient.APIError
+		s.Require().True(errors.As(err, &apiErr))
+		s.Equal(http.StatusServiceUnavailable, apiErr.StatusCode)
+	})
+
+	s.Run("should delay responses", func() {
+		// Arrange
+		s.srv.InjectFault(meteoratest.Fault{Latency: 50 * time.Millisecond, Count: 1})
+		start := time.Now()
+
+		// Act
+		_, err := s.client.DLMM.GetPool(context.Background(), "pool1")
+
+		// Assert
+		s.NoError(err)
+		s.GreaterOrEqual(time.Since(start), 50*time.Millisecond)
+	})
+}
+
+func (s *ServerTestSuite) TestDAMMv2() {
+	// Arrange
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DAMMv2.Pools = []dammv2.Pool{
+			{Address: "pool1", TVL: 10, Volume: dammv2.TimeBuckets{Hour1: 5}},
+			{Address: "pool2", TVL: 20, Volume: dammv2.TimeBuckets{Hour1: 1}},
+		}
+		d.DAMMv2.OpenPositions["wallet"] = []dammv2.PositionsByPool{
+			{PoolAddress: "pool1", Positions: []dammv2.OpenPosition{{PositionAddress: "a"}, {PositionAddress: "b"}}},
+			{PoolAddress: "pool2", Positions: []dammv2.OpenPosition{{PositionAddress: "c"}}},
+		}
+	})
+
+	// Act
+	pools, err := s.client.DAMMv2.ListPools(context.Background(), &dammv2.ListPoolsParams{SortBy: meteora.String("volume_1h:desc")})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("pool1", pools.Data[0].Address)
+
+	// Act
+	open, err := s.client.DAMMv2.GetOpenPositions(context.Background(), "wallet", &dammv2.GetOpenPositionsParams{Pool: meteora.String("pool1")})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(int64(1), open.TotalPools)
+	s.Equal(int64(2), open.TotalPositions)
+}
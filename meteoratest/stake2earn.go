@@ -0,0 +1,50 @@
+package meteoratest
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+// maxFilterPoolAddresses is the documented limit of pool_address values per
+// /vault/filter request.
+const maxFilterPoolAddresses = 100
+
+var stake2earnRoutes = []route{
+	{http.MethodGet, "/analytics/all", func(d *Data, _ url.Values, _ []string) (any, error) {
+		if d.Stake2Earn.Analytics != nil {
+			return d.Stake2Earn.Analytics, nil
+		}
+		a := &stake2earn.Analytics{TotalFeeVaults: len(d.Stake2Earn.Vaults)}
+		for _, v := range d.Stake2Earn.Vaults {
+			a.TotalStakedAmountUSD += v.TotalStakedAmountUSD
+		}
+		return a, nil
+	}},
+	{http.MethodGet, "/vault/all", func(d *Data, _ url.Values, _ []string) (any, error) {
+		vaults := append([]stake2earn.Vault{}, d.Stake2Earn.Vaults...)
+		return &stake2earn.VaultListResponse{Total: len(vaults), Data: vaults}, nil
+	}},
+	{http.MethodGet, "/vault/filter", func(d *Data, q url.Values, _ []string) (any, error) {
+		pools := q["pool_address"]
+		if len(pools) > maxFilterPoolAddresses {
+			return nil, errBadRequest("at most %d pool_address values are allowed, got %d", maxFilterPoolAddresses, len(pools))
+		}
+		vaults := []stake2earn.Vault{}
+		for _, v := range d.Stake2Earn.Vaults {
+			if len(pools) == 0 || contains(pools, v.PoolAddress) {
+				vaults = append(vaults, v)
+			}
+		}
+		return &stake2earn.VaultListResponse{Total: len(vaults), Data: vaults}, nil
+	}},
+	{http.MethodGet, "/vault/*", func(d *Data, _ url.Values, args []string) (any, error) {
+		for _, v := range d.Stake2Earn.Vaults {
+			if v.VaultAddress == args[0] {
+				return v, nil
+			}
+		}
+		return nil, errNotFound("vault %s", args[0])
+	}},
+}
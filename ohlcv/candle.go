@@ -0,0 +1,120 @@
+package ohlcv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+// Candle is an OHLCV candle of any service.
+type Candle struct {
+	// Timestamp is the Unix timestamp in seconds of the start of the candle.
+	Timestamp int64 `json:"timestamp"`
+
+	// Open is the opening price.
+	Open float64 `json:"open"`
+
+	// High is the highest price.
+	High float64 `json:"high"`
+
+	// Low is the lowest price.
+	Low float64 `json:"low"`
+
+	// Close is the closing price.
+	Close float64 `json:"close"`
+
+	// Volume is the trading volume in USD.
+	Volume float64 `json:"volume"`
+}
+
+// timeframes maps the timeframes accepted by the OHLCV endpoints to the
+// length of their candles.
+var timeframes = map[string]time.Duration{
+	"5m":  5 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"2h":  2 * time.Hour,
+	"4h":  4 * time.Hour,
+	"12h": 12 * time.Hour,
+	"24h": 24 * time.Hour,
+}
+
+// TimeframeDuration returns the length of the candles of timeframe.
+func TimeframeDuration(timeframe string) (time.Duration, error) {
+	d, ok := timeframes[timeframe]
+	if !ok {
+		return 0, fmt.Errorf("ohlcv.TimeframeDuration: unknown timeframe %q", timeframe)
+	}
+	return d, nil
+}
+
+// Source fetches the candles of a pool.
+type Source interface {
+	// Name identifies the source in store keys, such as "dlmm".
+	Name() string
+
+	// Fetch returns the candles of pool with timestamps between start and
+	// end inclusive, in Unix seconds.
+	Fetch(ctx context.Context, pool, timeframe string, start, end int64) ([]Candle, error)
+}
+
+// DLMM returns a Source fetching candles with dlmm.API.GetOHLCV.
+func DLMM(api dlmm.API) Source {
+	return dlmmSource{api}
+}
+
+type dlmmSource struct {
+	api dlmm.API
+}
+
+func (dlmmSource) Name() string {
+	return "dlmm"
+}
+
+func (s dlmmSource) Fetch(ctx context.Context, pool, timeframe string, start, end int64) ([]Candle, error) {
+	resp, err := s.api.GetOHLCV(ctx, pool, &dlmm.OHLCVParams{TimeframeBasedParams: dlmm.TimeframeBasedParams{
+		Timeframe: &timeframe,
+		StartTime: &start,
+		EndTime:   &end,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	candles := make([]Candle, len(resp.Data))
+	for i, c := range resp.Data {
+		candles[i] = Candle{Timestamp: c.Timestamp, Open: c.Open, High: c.High, Low: c.Low, Close: c.Close, Volume: c.Volume}
+	}
+	return candles, nil
+}
+
+// DAMMv2 returns a Source fetching candles with dammv2.API.GetOHLCV.
+func DAMMv2(api dammv2.API) Source {
+	return dammv2Source{api}
+}
+
+type dammv2Source struct {
+	api dammv2.API
+}
+
+func (dammv2Source) Name() string {
+	return "dammv2"
+}
+
+func (s dammv2Source) Fetch(ctx context.Context, pool, timeframe string, start, end int64) ([]Candle, error) {
+	resp, err := s.api.GetOHLCV(ctx, pool, &dammv2.OHLCVParams{
+		Timeframe: &timeframe,
+		StartTime: &start,
+		EndTime:   &end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	candles := make([]Candle, len(resp.Data))
+	for i, c := range resp.Data {
+		candles[i] = Candle{Timestamp: c.Timestamp, Open: c.Open, High: c.High, Low: c.Low, Close: c.Close, Volume: c.Volume}
+	}
+	return candles, nil
+}
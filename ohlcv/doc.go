@@ -0,0 +1,32 @@
+// Package ohlcv keeps a local, incrementally synced history of DLMM and
+// DAMM v2 OHLCV candles.
+//
+// A Syncer remembers nothing itself: for each pool and timeframe it asks the
+// Store for the latest stored candle, fetches the range from that candle up
+// to now in chunks of API-sized requests and upserts the candles by
+// timestamp. The latest candle is always fetched again because it may still
+// have been open when it was stored. A crash therefore loses at most the
+// chunk being written, and the next run resumes where the store ends.
+//
+// Two stores are provided: MemoryStore, and FileStore, which keeps one
+// append-only file per pool and timeframe.
+//
+// # Usage
+//
+//	client := meteora.New()
+//	store, err := ohlcv.NewFileStore("candles")
+//	if err != nil {
+//	    return err
+//	}
+//	syncer := ohlcv.NewSyncer(store, &ohlcv.Options{
+//	    Start: time.Now().AddDate(0, -3, 0),
+//	    Progress: func(p ohlcv.Progress) {
+//	        log.Printf("%s %s: chunk %d/%d", p.Job.Pool, p.Job.Timeframe, p.Chunk, p.Chunks)
+//	    },
+//	})
+//	results := syncer.Sync(ctx, []ohlcv.Job{
+//	    {Source: ohlcv.DLMM(client.DLMM), Pool: dlmmPool, Timeframe: "1h"},
+//	    {Source: ohlcv.DAMMv2(client.DAMMv2), Pool: dammPool, Timeframe: "1h"},
+//	})
+//	candles, err := store.Candles(ctx, results[0].Job.Key(), 0, 0)
+package ohlcv
@@ -0,0 +1,150 @@
+package ohlcv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore keeps the candles of each key in its own file under a directory,
+// at <dir>/<source>/<timeframe>/<pool>.ndjson. Upserts append one JSON line
+// per candle and the last line for a timestamp wins, so a crash can lose at
+// most the candles being written and a partially written line is discarded
+// on the next read. The candles of a key are loaded into memory on first use.
+type FileStore struct {
+	dir string
+
+	mu     sync.Mutex
+	series map[Key][]Candle
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a FileStore rooted at dir, which is created if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ohlcv.NewFileStore: %w", err)
+	}
+	return &FileStore{dir: dir, series: map[Key][]Candle{}}, nil
+}
+
+func (s *FileStore) path(key Key) string {
+	return filepath.Join(s.dir, key.Source, key.Timeframe, key.Pool+".ndjson")
+}
+
+// load returns the candles of key, reading the file on first use. s.mu must
+// be held.
+func (s *FileStore) load(key Key) ([]Candle, error) {
+	if series, ok := s.series[key]; ok {
+		return series, nil
+	}
+	f, err := os.OpenFile(s.path(key), os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		s.series[key] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var series []Candle
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				if err := f.Truncate(offset); err != nil {
+					return nil, err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var c Candle
+		if err := json.Unmarshal(line, &c); err != nil {
+			return nil, fmt.Errorf("%s: corrupt candle at offset %d: %w", s.path(key), offset, err)
+		}
+		series = upsert(series, []Candle{c})
+		offset += int64(len(line))
+	}
+	s.series[key] = series
+	return series, nil
+}
+
+// Last returns the latest stored candle of key.
+func (s *FileStore) Last(ctx context.Context, key Key) (Candle, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	series, err := s.load(key)
+	if err != nil {
+		return Candle{}, false, fmt.Errorf("ohlcv.FileStore.Last: %w", err)
+	}
+	return last(series)
+}
+
+// Upsert appends candles to the file of key and syncs it.
+func (s *FileStore) Upsert(ctx context.Context, key Key, candles []Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	series, err := s.load(key)
+	if err != nil {
+		return fmt.Errorf("ohlcv.FileStore.Upsert: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, c := range candles {
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("ohlcv.FileStore.Upsert: %w", err)
+		}
+	}
+	if err := appendFile(s.path(key), buf.Bytes()); err != nil {
+		return fmt.Errorf("ohlcv.FileStore.Upsert: %w", err)
+	}
+	s.series[key] = upsert(series, candles)
+	return nil
+}
+
+func appendFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Candles returns the stored candles of key between start and end.
+func (s *FileStore) Candles(ctx context.Context, key Key, start, end int64) ([]Candle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	series, err := s.load(key)
+	if err != nil {
+		return nil, fmt.Errorf("ohlcv.FileStore.Candles: %w", err)
+	}
+	return between(series, start, end), nil
+}
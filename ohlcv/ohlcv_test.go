@@ -0,0 +1,196 @@
+package ohlcv_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/meteoratest"
+	"github.com/ua1984/meteora-go/ohlcv"
+)
+
+type OHLCVTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestOHLCV(t *testing.T) {
+	suite.Run(t, new(OHLCVTestSuite))
+}
+
+func (s *OHLCVTestSuite) SetupTest() {
+	s.ctx = context.Background()
+}
+
+const day = int64(24 * 60 * 60)
+
+// stubSource serves one daily candle per day with Close set to close.
+type stubSource struct {
+	mu     sync.Mutex
+	close  float64
+	failAt int
+	calls  [][2]int64
+}
+
+func (s *stubSource) Name() string {
+	return "stub"
+}
+
+func (s *stubSource) Fetch(ctx context.Context, pool, timeframe string, start, end int64) ([]ohlcv.Candle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, [2]int64{start, end})
+	if len(s.calls) == s.failAt {
+		return nil, errors.New("unavailable")
+	}
+	var candles []ohlcv.Candle
+	for ts := start - start%day; ts <= end; ts += day {
+		if ts >= start {
+			candles = append(candles, ohlcv.Candle{Timestamp: ts, Close: s.close})
+		}
+	}
+	return candles, nil
+}
+
+func today() int64 {
+	now := time.Now().Unix()
+	return now - now%day
+}
+
+func (s *OHLCVTestSuite) TestSync() {
+	// Arrange
+	store := ohlcv.NewMemoryStore()
+	source := &stubSource{close: 1}
+	job := ohlcv.Job{Source: source, Pool: "pool1", Timeframe: "24h"}
+	var progress []ohlcv.Progress
+	syncer := ohlcv.NewSyncer(store, &ohlcv.Options{
+		ChunkSize: 2,
+		Start:     time.Now().Add(-5 * 24 * time.Hour),
+		Progress:  func(p ohlcv.Progress) { progress = append(progress, p) },
+	})
+
+	s.Run("should backfill in chunks", func() {
+		// Act
+		results := syncer.Sync(s.ctx, []ohlcv.Job{job})
+
+		// Assert
+		s.Require().NoError(results[0].Err)
+		s.Equal(6, results[0].Candles)
+		s.Equal(today(), results[0].Last.Timestamp)
+		s.Require().Len(progress, 3)
+		s.Equal(3, progress[2].Chunks)
+		s.Equal(today()-5*day, progress[0].Start)
+		s.Equal(today()-3*day-1, progress[0].End)
+		candles, err := store.Candles(s.ctx, job.Key(), 0, 0)
+		s.Require().NoError(err)
+		s.Len(candles, 6)
+	})
+
+	s.Run("should refetch only the open candle", func() {
+		// Arrange
+		source.close = 2
+		source.calls = nil
+
+		// Act
+		results := syncer.Sync(s.ctx, []ohlcv.Job{job})
+
+		// Assert
+		s.Require().NoError(results[0].Err)
+		s.Equal(1, results[0].Candles)
+		s.Require().Len(source.calls, 1)
+		s.Equal(today(), source.calls[0][0])
+		candles, err := store.Candles(s.ctx, job.Key(), 0, 0)
+		s.Require().NoError(err)
+		s.Require().Len(candles, 6)
+		s.Equal(1.0, candles[4].Close)
+		s.Equal(2.0, candles[5].Close)
+	})
+}
+
+func (s *OHLCVTestSuite) TestSyncResumesAfterError() {
+	// Arrange
+	store := ohlcv.NewMemoryStore()
+	source := &stubSource{close: 1, failAt: 2}
+	job := ohlcv.Job{Source: source, Pool: "pool1", Timeframe: "24h"}
+	syncer := ohlcv.NewSyncer(store, &ohlcv.Options{ChunkSize: 2, Start: time.Now().Add(-5 * 24 * time.Hour)})
+
+	// Act
+	failed := syncer.Sync(s.ctx, []ohlcv.Job{job})
+	resumed := syncer.Sync(s.ctx, []ohlcv.Job{job})
+
+	// Assert
+	s.ErrorContains(failed[0].Err, "stub pool1 24h: unavailable")
+	s.Equal(2, failed[0].Candles)
+	s.Require().NoError(resumed[0].Err)
+	s.Equal(today()-4*day, source.calls[2][0])
+	candles, err := store.Candles(s.ctx, job.Key(), 0, 0)
+	s.Require().NoError(err)
+	s.Len(candles, 6)
+}
+
+func (s *OHLCVTestSuite) TestSyncUnknownTimeframe() {
+	// Arrange
+	syncer := ohlcv.NewSyncer(ohlcv.NewMemoryStore(), nil)
+
+	// Act
+	results := syncer.Sync(s.ctx, []ohlcv.Job{{Source: &stubSource{}, Pool: "pool1", Timeframe: "3m"}})
+
+	// Assert
+	s.ErrorContains(results[0].Err, `unknown timeframe "3m"`)
+}
+
+func (s *OHLCVTestSuite) TestFileStore() {
+	// Arrange
+	dir := s.T().TempDir()
+	key := ohlcv.Key{Source: "dlmm", Pool: "pool1", Timeframe: "1h"}
+	store, err := ohlcv.NewFileStore(dir)
+	s.Require().NoError(err)
+	s.Require().NoError(store.Upsert(s.ctx, key, []ohlcv.Candle{{Timestamp: 3600, Close: 1}, {Timestamp: 7200, Close: 1}}))
+	s.Require().NoError(store.Upsert(s.ctx, key, []ohlcv.Candle{{Timestamp: 7200, Close: 2}, {Timestamp: 0, Close: 0.5}}))
+
+	// Simulate a crash in the middle of a write.
+	path := filepath.Join(dir, "dlmm", "1h", "pool1.ndjson")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	s.Require().NoError(err)
+	_, err = f.WriteString(`{"timestamp":10800,"op`)
+	s.Require().NoError(err)
+	s.Require().NoError(f.Close())
+
+	// Act
+	reopened, err := ohlcv.NewFileStore(dir)
+	s.Require().NoError(err)
+	last, ok, lastErr := reopened.Last(s.ctx, key)
+	candles, candlesErr := reopened.Candles(s.ctx, key, 3600, 0)
+
+	// Assert
+	s.Require().NoError(lastErr)
+	s.True(ok)
+	s.Equal(ohlcv.Candle{Timestamp: 7200, Close: 2}, last)
+	s.Require().NoError(candlesErr)
+	s.Equal([]ohlcv.Candle{{Timestamp: 3600, Close: 1}, {Timestamp: 7200, Close: 2}}, candles)
+}
+
+func (s *OHLCVTestSuite) TestDLMMSource() {
+	// Arrange
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.Seed(func(d *meteoratest.Data) {
+		d.DLMM.Pools = []dlmm.Pool{{Address: "pool1"}}
+		d.DLMM.OHLCV["pool1"] = []dlmm.OHLCV{{Timestamp: 3600, Close: 1}, {Timestamp: 7200, Close: 2}, {Timestamp: 10800, Close: 3}}
+	})
+	source := ohlcv.DLMM(srv.Client().DLMM)
+
+	// Act
+	candles, err := source.Fetch(s.ctx, "pool1", "1h", 7200, 10800)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal([]ohlcv.Candle{{Timestamp: 7200, Close: 2}, {Timestamp: 10800, Close: 3}}, candles)
+	s.Equal("7200", srv.Requests()[0].Query.Get("start_time"))
+}
@@ -0,0 +1,108 @@
+package ohlcv
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Key identifies the candles of one pool and timeframe.
+type Key struct {
+	// Source is the Name of the Source the candles were fetched from.
+	Source string
+
+	// Pool is the pool address.
+	Pool string
+
+	// Timeframe is the candle timeframe, such as "1h".
+	Timeframe string
+}
+
+// Store persists candles. Implementations must be safe for concurrent use.
+type Store interface {
+	// Last returns the stored candle of key with the latest timestamp. It
+	// returns false if no candle is stored.
+	Last(ctx context.Context, key Key) (Candle, bool, error)
+
+	// Upsert stores candles, replacing stored candles with the same
+	// timestamp.
+	Upsert(ctx context.Context, key Key, candles []Candle) error
+
+	// Candles returns the stored candles of key with timestamps between start
+	// and end inclusive, oldest first. An end of zero is unbounded.
+	Candles(ctx context.Context, key Key, start, end int64) ([]Candle, error)
+}
+
+// MemoryStore keeps candles in memory.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	series map[Key][]Candle
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{series: map[Key][]Candle{}}
+}
+
+// Last returns the latest stored candle of key.
+func (m *MemoryStore) Last(ctx context.Context, key Key) (Candle, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return last(m.series[key])
+}
+
+// Upsert stores candles for key.
+func (m *MemoryStore) Upsert(ctx context.Context, key Key, candles []Candle) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.series[key] = upsert(m.series[key], candles)
+	return nil
+}
+
+// Candles returns the stored candles of key between start and end.
+func (m *MemoryStore) Candles(ctx context.Context, key Key, start, end int64) ([]Candle, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return between(m.series[key], start, end), nil
+}
+
+func last(series []Candle) (Candle, bool, error) {
+	if len(series) == 0 {
+		return Candle{}, false, nil
+	}
+	return series[len(series)-1], true, nil
+}
+
+// upsert merges candles into the timestamp-ordered series.
+func upsert(series, candles []Candle) []Candle {
+	for _, c := range candles {
+		n := len(series)
+		if n == 0 || series[n-1].Timestamp < c.Timestamp {
+			series = append(series, c)
+			continue
+		}
+		i := sort.Search(n, func(i int) bool { return series[i].Timestamp >= c.Timestamp })
+		if series[i].Timestamp == c.Timestamp {
+			series[i] = c
+			continue
+		}
+		series = append(series, Candle{})
+		copy(series[i+1:], series[i:])
+		series[i] = c
+	}
+	return series
+}
+
+func between(series []Candle, start, end int64) []Candle {
+	lo := sort.Search(len(series), func(i int) bool { return series[i].Timestamp >= start })
+	hi := len(series)
+	if end != 0 {
+		hi = sort.Search(len(series), func(i int) bool { return series[i].Timestamp > end })
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return append([]Candle(nil), series[lo:hi]...)
+}
@@ -0,0 +1,186 @@
+package ohlcv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ua1984/meteora-go/internal/batch"
+)
+
+// DefaultChunkSize is the number of candles requested per API call.
+const DefaultChunkSize = 1000
+
+// DefaultBackfill is how far back a pool without stored candles is fetched
+// when Options.Start is not set.
+const DefaultBackfill = 30 * 24 * time.Hour
+
+// Job identifies the candles to sync.
+type Job struct {
+	// Source fetches the candles.
+	Source Source
+
+	// Pool is the pool address.
+	Pool string
+
+	// Timeframe is the candle timeframe, such as "1h".
+	Timeframe string
+}
+
+// Key returns the store key of the candles of j.
+func (j Job) Key() Key {
+	return Key{Source: j.Source.Name(), Pool: j.Pool, Timeframe: j.Timeframe}
+}
+
+// Progress reports a chunk of a job that was fetched and stored.
+type Progress struct {
+	// Job is the job the chunk belongs to.
+	Job Job
+
+	// Chunk is the 1-based number of the chunk.
+	Chunk int
+
+	// Chunks is the number of chunks of the job.
+	Chunks int
+
+	// Start and End bound the fetched range in Unix seconds.
+	Start, End int64
+
+	// Candles is the number of candles received for the chunk.
+	Candles int
+}
+
+// Result is the outcome of a job.
+type Result struct {
+	// Job is the synced job.
+	Job Job
+
+	// Candles is the number of candles fetched and stored.
+	Candles int
+
+	// Last is the latest stored candle after the sync, or nil if none is
+	// stored.
+	Last *Candle
+
+	// Err is the error that stopped the job, if any. Chunks stored before the
+	// error are kept and the next sync resumes after them.
+	Err error
+}
+
+// Options configures a Syncer. A nil *Options uses the defaults.
+type Options struct {
+	// Concurrency is the number of jobs synced in parallel. Requests still
+	// wait on the rate limiter of the client behind each Source, if it has
+	// one, which is shared by all jobs. Defaults to 8.
+	Concurrency int
+
+	// ChunkSize is the number of candles requested per API call. Defaults to
+	// DefaultChunkSize.
+	ChunkSize int
+
+	// Start is where pools without stored candles are fetched from. Defaults
+	// to DefaultBackfill before now.
+	Start time.Time
+
+	// Progress, if set, is called after each stored chunk. It is called from
+	// several goroutines when Concurrency is above one.
+	Progress func(Progress)
+}
+
+// Syncer fetches new candles into a Store.
+type Syncer struct {
+	store Store
+	opts  Options
+	now   func() time.Time
+}
+
+// NewSyncer returns a Syncer storing candles in store.
+func NewSyncer(store Store, opts *Options) *Syncer {
+	s := &Syncer{store: store, now: time.Now}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.ChunkSize <= 0 {
+		s.opts.ChunkSize = DefaultChunkSize
+	}
+	return s
+}
+
+// Sync brings the stored candles of every job up to date and returns one
+// result per job, in order.
+//
+// For each job the range from the latest stored candle up to now is fetched
+// in chunks of ChunkSize candles and upserted by timestamp. The latest stored
+// candle is always fetched again since it may still have been open when it was
+// stored, so running Sync repeatedly is safe and cheap.
+func (s *Syncer) Sync(ctx context.Context, jobs []Job) []Result {
+	results := make([]Result, len(jobs))
+	batch.Run(ctx, len(jobs), s.opts.Concurrency, func(ctx context.Context, i int) {
+		results[i] = s.sync(ctx, jobs[i])
+	})
+	return results
+}
+
+func (s *Syncer) sync(ctx context.Context, job Job) Result {
+	res := Result{Job: job}
+	fail := func(err error) Result {
+		res.Err = fmt.Errorf("ohlcv.Syncer.Sync: %s %s %s: %w", job.Source.Name(), job.Pool, job.Timeframe, err)
+		return res
+	}
+
+	tf, err := TimeframeDuration(job.Timeframe)
+	if err != nil {
+		return fail(err)
+	}
+	step := int64(tf / time.Second)
+	key := job.Key()
+
+	last, ok, err := s.store.Last(ctx, key)
+	if err != nil {
+		return fail(err)
+	}
+	now := s.now()
+	var start int64
+	switch {
+	case ok:
+		start = last.Timestamp
+		res.Last = &last
+	case !s.opts.Start.IsZero():
+		start = s.opts.Start.Unix()
+	default:
+		start = now.Add(-DefaultBackfill).Unix()
+	}
+	start -= start % step
+	end := now.Unix()
+
+	span := step * int64(s.opts.ChunkSize)
+	chunks := int((end - start + span) / span)
+	for chunk := 1; start <= end; chunk++ {
+		if err := ctx.Err(); err != nil {
+			return fail(err)
+		}
+		chunkEnd := start + span - 1
+		if chunkEnd > end {
+			chunkEnd = end
+		}
+		candles, err := job.Source.Fetch(ctx, job.Pool, job.Timeframe, start, chunkEnd)
+		if err != nil {
+			return fail(err)
+		}
+		if err := s.store.Upsert(ctx, key, candles); err != nil {
+			return fail(err)
+		}
+		res.Candles += len(candles)
+		for i := range candles {
+			if res.Last == nil || candles[i].Timestamp >= res.Last.Timestamp {
+				c := candles[i]
+				res.Last = &c
+			}
+		}
+		if s.opts.Progress != nil {
+			s.opts.Progress(Progress{Job: job, Chunk: chunk, Chunks: chunks, Start: start, End: chunkEnd, Candles: len(candles)})
+		}
+		start = chunkEnd + 1
+	}
+	return res
+}
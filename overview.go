@@ -0,0 +1,244 @@
+package meteora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dynamicvault"
+)
+
+// ProductMetrics holds the headline metrics of one Meteora product.
+type ProductMetrics struct {
+	// TVL is the total value locked in USD.
+	TVL float64 `json:"tvl"`
+
+	// Volume24h is the trading volume of the last 24 hours in USD. It is zero
+	// for Stake2Earn and Dynamic Vault, which do not trade.
+	Volume24h float64 `json:"volume_24h"`
+
+	// Fees24h is the fees of the last 24 hours in USD. It is zero for
+	// Stake2Earn and Dynamic Vault, which do not report fees.
+	Fees24h float64 `json:"fees_24h"`
+
+	// Pools is the number of pools, or of vaults for Stake2Earn and Dynamic
+	// Vault.
+	Pools int `json:"pools"`
+
+	// Err is the error that prevented the metrics from being fetched. The
+	// other fields hold whatever was fetched before the error.
+	Err error `json:"-"`
+}
+
+// Overview is a protocol-wide summary of every Meteora product.
+type Overview struct {
+	// DLMM holds the DLMM protocol metrics.
+	DLMM ProductMetrics `json:"dlmm"`
+
+	// DAMMv2 holds the DAMM v2 protocol metrics.
+	DAMMv2 ProductMetrics `json:"dammv2"`
+
+	// DAMMv1 holds the DAMM v1 metrics: dynamic AMM and multitoken pools
+	// combined.
+	DAMMv1 ProductMetrics `json:"dammv1"`
+
+	// DAMMv1Categories holds the DAMM v1 metrics per category ("dynamic_amm",
+	// "lst", "farms" and "multitokens"). Categories overlap, so they do not
+	// add up to DAMMv1. Pools is not reported per category.
+	DAMMv1Categories map[string]ProductMetrics `json:"dammv1_categories"`
+
+	// Stake2Earn holds the staked value and number of fee vaults.
+	Stake2Earn ProductMetrics `json:"stake2earn"`
+
+	// DynamicVault holds the value and number of Dynamic Vaults. Each vault
+	// is valued at its total amount times its USD rate. The vault API does
+	// not report token decimals, so they are inferred from the yield the
+	// vault reports in both native units and USD, unless given in
+	// OverviewOptions.TokenDecimals. Vaults that have earned no yield and
+	// are not listed there are counted in UnpricedVaults instead of TVL.
+	DynamicVault ProductMetrics `json:"dynamic_vault"`
+
+	// UnpricedVaults is the number of Dynamic Vaults left out of
+	// DynamicVault.TVL.
+	UnpricedVaults int `json:"unpriced_vaults"`
+
+	// Total sums DLMM, DAMM v2 and DAMM v1, leaving out the products that
+	// failed. Stake2Earn and Dynamic Vault are not added: staked LP tokens
+	// are already counted in the DAMM v1 pools they represent, and DAMM v1
+	// pool liquidity is itself deposited in Dynamic Vaults.
+	Total ProductMetrics `json:"total"`
+}
+
+// Complete reports whether every product was fetched without error.
+func (o *Overview) Complete() bool {
+	for _, p := range o.products() {
+		if p.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *Overview) products() []*ProductMetrics {
+	return []*ProductMetrics{&o.DLMM, &o.DAMMv2, &o.DAMMv1, &o.Stake2Earn, &o.DynamicVault}
+}
+
+// OverviewOptions are optional parameters for GetMeteoraOverview.
+type OverviewOptions struct {
+	// TokenDecimals maps token mints to their number of decimals, used to
+	// price Dynamic Vaults instead of the decimals inferred from their yield.
+	// It is only needed for vaults that have earned no yield.
+	TokenDecimals map[string]int
+}
+
+// GetMeteoraOverview fetches the protocol metrics of every service
+// concurrently and returns them in one normalized Overview. It makes one
+// request per service, plus a one-pool DAMM v1 search to count the pools.
+// opts may be nil.
+//
+// A service that fails does not fail the others: its ProductMetrics.Err is
+// set, it is left out of the total and the errors are returned joined
+// together with the partial overview, which is never nil.
+func (c *Client) GetMeteoraOverview(ctx context.Context, opts *OverviewOptions) (*Overview, error) {
+	if opts == nil {
+		opts = &OverviewOptions{}
+	}
+	o := &Overview{}
+	fetches := []struct {
+		name  string
+		dst   *ProductMetrics
+		fetch func(ctx context.Context, o *Overview) error
+	}{
+		{"dlmm", &o.DLMM, c.dlmmOverview},
+		{"dammv2", &o.DAMMv2, c.dammv2Overview},
+		{"dammv1", &o.DAMMv1, c.dammv1Overview},
+		{"stake2earn", &o.Stake2Earn, c.stake2earnOverview},
+		{"dynamicvault", &o.DynamicVault, func(ctx context.Context, o *Overview) error {
+			return c.dynamicVaultOverview(ctx, o, opts.TokenDecimals)
+		}},
+	}
+
+	// Each fetch writes only its own fields of o.
+	var wg sync.WaitGroup
+	for _, f := range fetches {
+		f := f
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f.fetch(ctx, o); err != nil {
+				f.dst.Err = fmt.Errorf("meteora.GetMeteoraOverview: %s: %w", f.name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, p := range []*ProductMetrics{&o.DLMM, &o.DAMMv2, &o.DAMMv1} {
+		if p.Err != nil {
+			continue
+		}
+		o.Total.TVL += p.TVL
+		o.Total.Volume24h += p.Volume24h
+		o.Total.Fees24h += p.Fees24h
+		o.Total.Pools += p.Pools
+	}
+
+	var errs []error
+	for _, p := range o.products() {
+		if p.Err != nil {
+			errs = append(errs, p.Err)
+		}
+	}
+	return o, errors.Join(errs...)
+}
+
+func (c *Client) dlmmOverview(ctx context.Context, o *Overview) error {
+	m, err := c.DLMM.GetProtocolMetrics(ctx)
+	if err != nil {
+		return err
+	}
+	o.DLMM = ProductMetrics{TVL: m.TotalTVL, Volume24h: m.Volume24h, Fees24h: m.Fee24h, Pools: m.TotalPools}
+	return nil
+}
+
+func (c *Client) dammv2Overview(ctx context.Context, o *Overview) error {
+	m, err := c.DAMMv2.GetProtocolMetrics(ctx)
+	if err != nil {
+		return err
+	}
+	o.DAMMv2 = ProductMetrics{TVL: m.TotalTVL, Volume24h: m.Volume24h, Fees24h: m.Fee24h, Pools: m.TotalPools}
+	return nil
+}
+
+// dammv1Overview combines the pool metrics with the total count of a one-pool
+// search, since the metrics endpoint does not report a pool count. Pools with
+// unrecognized tokens are included in the count.
+func (c *Client) dammv1Overview(ctx context.Context, o *Overview) error {
+	m, err := c.DAMMv1.GetPoolsMetrics(ctx)
+	if err != nil {
+		return err
+	}
+	o.DAMMv1 = ProductMetrics{
+		TVL:       m.DynamicAMMTVL + m.MultitokensTVL,
+		Volume24h: m.DynamicAMMDailyVolume + m.MultitokensDailyVolume,
+		Fees24h:   m.DynamicAMMDailyFee + m.MultitokensDailyFee,
+	}
+	o.DAMMv1Categories = map[string]ProductMetrics{
+		"dynamic_amm": {TVL: m.DynamicAMM.TVL, Volume24h: m.DynamicAMM.DailyVolume, Fees24h: m.DynamicAMM.DailyFee},
+		"lst":         {TVL: m.LST.TVL, Volume24h: m.LST.DailyVolume, Fees24h: m.LST.DailyFee},
+		"farms":       {TVL: m.Farms.TVL, Volume24h: m.Farms.DailyVolume, Fees24h: m.Farms.DailyFee},
+		"multitokens": {TVL: m.Multitokens.TVL, Volume24h: m.Multitokens.DailyVolume, Fees24h: m.Multitokens.DailyFee},
+	}
+
+	search, err := c.DAMMv1.SearchPools(ctx, &dammv1.SearchParams{Size: 1, Unknown: Bool(true)})
+	if err != nil {
+		return err
+	}
+	o.DAMMv1.Pools = search.TotalCount
+	return nil
+}
+
+func (c *Client) stake2earnOverview(ctx context.Context, o *Overview) error {
+	a, err := c.Stake2Earn.GetAnalytics(ctx)
+	if err != nil {
+		return err
+	}
+	o.Stake2Earn = ProductMetrics{TVL: a.TotalStakedAmountUSD, Pools: a.TotalFeeVaults}
+	return nil
+}
+
+func (c *Client) dynamicVaultOverview(ctx context.Context, o *Overview, decimals map[string]int) error {
+	vaults, err := c.DynamicVault.ListVaultInfo(ctx)
+	if err != nil {
+		return err
+	}
+	o.DynamicVault.Pools = len(vaults)
+	for i := range vaults {
+		v := &vaults[i]
+		d, ok := vaultDecimals(v, decimals)
+		if !ok {
+			o.UnpricedVaults++
+			continue
+		}
+		o.DynamicVault.TVL += float64(v.TotalAmount) / math.Pow10(d) * v.USDRate
+	}
+	return nil
+}
+
+// vaultDecimals returns the decimals of the vault's token from decimals, or
+// else infers them from its yield: EarnedUSDAmount is EarnedAmount over
+// 10^decimals at the prices the yield was earned at. Rounding to a whole
+// number of decimals absorbs price moves of up to about three times since
+// then. It reports false if the vault has earned no yield.
+func vaultDecimals(v *dynamicvault.VaultInfo, decimals map[string]int) (int, bool) {
+	if d, ok := decimals[v.TokenAddress]; ok {
+		return d, true
+	}
+	if v.EarnedAmount <= 0 || v.EarnedUSDAmount <= 0 || v.USDRate <= 0 {
+		return 0, false
+	}
+	d := math.Round(math.Log10(float64(v.EarnedAmount) * v.USDRate / v.EarnedUSDAmount))
+	return int(max(d, 0)), true
+}
@@ -0,0 +1,119 @@
+package meteora_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	meteora "github.com/ua1984/meteora-go"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/dynamicvault"
+	"github.com/ua1984/meteora-go/meteoratest"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+type OverviewTestSuite struct {
+	suite.Suite
+	srv    *meteoratest.Server
+	client *meteora.Client
+}
+
+func TestOverview(t *testing.T) {
+	suite.Run(t, new(OverviewTestSuite))
+}
+
+func (s *OverviewTestSuite) SetupTest() {
+	s.srv = meteoratest.NewServer()
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DLMM.Pools = []dlmm.Pool{
+			{Address: "d1", TVL: 100, Volume: dlmm.TimeBuckets{Hour24: 10}, Fees: dlmm.TimeBuckets{Hour24: 1}},
+			{Address: "d2", TVL: 200, Volume: dlmm.TimeBuckets{Hour24: 20}, Fees: dlmm.TimeBuckets{Hour24: 2}},
+		}
+		d.DAMMv2.Pools = []dammv2.Pool{{Address: "v2", TVL: 50, Volume: dammv2.TimeBuckets{Hour24: 5}, Fees: dammv2.TimeBuckets{Hour24: 0.5}}}
+		d.DAMMv1.Pools = []dammv1.Pool{{PoolAddress: "v1a"}, {PoolAddress: "v1b"}, {PoolAddress: "v1c"}}
+		d.DAMMv1.PoolMetrics = &dammv1.PoolMetrics{
+			DynamicAMMTVL:          300,
+			DynamicAMMDailyVolume:  30,
+			DynamicAMMDailyFee:     3,
+			MultitokensTVL:         40,
+			MultitokensDailyVolume: 4,
+			MultitokensDailyFee:    0.4,
+			LST:                    dammv1.MetricsBreakdown{TVL: 60, DailyVolume: 6, DailyFee: 0.6},
+		}
+		d.Stake2Earn.Analytics = &stake2earn.Analytics{TotalFeeVaults: 7, TotalStakedAmountUSD: 70}
+		d.DynamicVault.Vaults = []dynamicvault.VaultInfo{
+			// 6 decimals: 1 USDC of yield reported as 1.02 USD.
+			{Symbol: "USDC", TokenAddress: "usdc", TotalAmount: 5_000_000, USDRate: 1, EarnedAmount: 1_000_000, EarnedUSDAmount: 1.02},
+			// 9 decimals: 0.01 SOL of yield earned at 100 USD, now at 150.
+			{Symbol: "SOL", TokenAddress: "sol", TotalAmount: 2_000_000_000, USDRate: 150, EarnedAmount: 10_000_000, EarnedUSDAmount: 1},
+			// No yield yet, so its decimals are unknown.
+			{Symbol: "NEW", TokenAddress: "new", TotalAmount: 1_000, USDRate: 2},
+		}
+	})
+	s.client = s.srv.Client()
+}
+
+func (s *OverviewTestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func (s *OverviewTestSuite) TestGetMeteoraOverview() {
+	// Act
+	o, err := s.client.GetMeteoraOverview(context.Background(), nil)
+
+	// Assert
+	s.Require().NoError(err)
+	s.True(o.Complete())
+	s.Equal(meteora.ProductMetrics{TVL: 300, Volume24h: 30, Fees24h: 3, Pools: 2}, o.DLMM)
+	s.Equal(meteora.ProductMetrics{TVL: 50, Volume24h: 5, Fees24h: 0.5, Pools: 1}, o.DAMMv2)
+	s.Equal(meteora.ProductMetrics{TVL: 340, Volume24h: 34, Fees24h: 3.4, Pools: 3}, o.DAMMv1)
+	s.Equal(meteora.ProductMetrics{TVL: 60, Volume24h: 6, Fees24h: 0.6}, o.DAMMv1Categories["lst"])
+	s.Equal(meteora.ProductMetrics{TVL: 70, Pools: 7}, o.Stake2Earn)
+	s.Equal(meteora.ProductMetrics{TVL: 305, Pools: 3}, o.DynamicVault)
+	s.Equal(1, o.UnpricedVaults)
+	s.Equal(meteora.ProductMetrics{TVL: 690, Volume24h: 69, Fees24h: 6.9, Pools: 6}, o.Total)
+}
+
+func (s *OverviewTestSuite) TestPartialOverview() {
+	// Arrange
+	s.srv.InjectFault(meteoratest.Fault{Path: meteoratest.DAMMv2Prefix, Status: http.StatusBadRequest})
+
+	// Act
+	o, err := s.client.GetMeteoraOverview(context.Background(), nil)
+
+	// Assert
+	s.ErrorContains(err, "meteora.GetMeteoraOverview: dammv2")
+	s.Require().NotNil(o)
+	s.False(o.Complete())
+	s.Error(o.DAMMv2.Err)
+	s.NoError(o.DLMM.Err)
+	s.Equal(640.0, o.Total.TVL)
+	s.Equal(5, o.Total.Pools)
+}
+
+func (s *OverviewTestSuite) TestOverviewWithDecimals() {
+	// Arrange
+	opts := &meteora.OverviewOptions{TokenDecimals: map[string]int{"new": 3}}
+
+	// Act
+	o, err := s.client.GetMeteoraOverview(context.Background(), opts)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(meteora.ProductMetrics{TVL: 307, Pools: 3}, o.DynamicVault)
+	s.Zero(o.UnpricedVaults)
+}
+
+func (s *OverviewTestSuite) TestOverviewCountsDAMMv1PoolsWithoutListing() {
+	// Act
+	_, err := s.client.GetMeteoraOverview(context.Background(), nil)
+
+	// Assert
+	s.Require().NoError(err)
+	for _, r := range s.srv.Requests() {
+		s.NotEqual(meteoratest.DAMMv1Prefix+"/pools", r.Path)
+	}
+}
@@ -0,0 +1,406 @@
+package meteora
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/internal/batch"
+	"github.com/ua1984/meteora-go/internal/httpclient"
+)
+
+// PoolProtocol identifies the protocol of a pool ranked by RankPairPools.
+type PoolProtocol string
+
+const (
+	// ProtocolDLMM is a DLMM pool.
+	ProtocolDLMM PoolProtocol = "dlmm"
+
+	// ProtocolDAMMv2 is a DAMM v2 pool.
+	ProtocolDAMMv2 PoolProtocol = "dammv2"
+)
+
+// PairRankWeights are the weights of each factor in PoolRank.Score. Only their
+// ratios matter; a zero weight leaves the factor out.
+type PairRankWeights struct {
+	// FeeAPR weights the fee APR.
+	FeeAPR float64
+
+	// FarmAPR weights the farming reward APR.
+	FarmAPR float64
+
+	// VolumeConsistency weights the volume consistency.
+	VolumeConsistency float64
+}
+
+// DefaultPairRankWeights favors fee income, then steady volume, then farming
+// rewards.
+var DefaultPairRankWeights = PairRankWeights{FeeAPR: 0.5, FarmAPR: 0.2, VolumeConsistency: 0.3}
+
+// PairRankOptions are optional parameters for RankPairPools and RankPair.
+type PairRankOptions struct {
+	// Window is the FeeTVLRatio window the fee APR is annualized from.
+	// Allowed values: 30m, 1h, 2h, 4h, 12h, 24h; others are an error.
+	// Default: 24h.
+	Window string
+
+	// MinTVL is the TVL depth floor in USD. Pools below it are not scored.
+	MinTVL float64
+
+	// Weights are the factor weights. Default: DefaultPairRankWeights.
+	Weights *PairRankWeights
+
+	// Timeframe is the volume history bucket interval. Default: "1h".
+	Timeframe string
+
+	// Lookback is how far back volume history is fetched. Default: 7 days.
+	Lookback time.Duration
+
+	// Now is the end of the volume history. Default: time.Now().
+	Now time.Time
+
+	// Concurrency is the maximum number of pools whose volume history is
+	// fetched at once. Pools below MinTVL are not fetched. Default: 8.
+	Concurrency int
+}
+
+// RankFactor is one factor of a PoolRank score.
+type RankFactor struct {
+	// Value is the raw value of the factor.
+	Value float64 `json:"value"`
+
+	// Normalized is Value scaled to between 0 and 1 against the other scored
+	// pools.
+	Normalized float64 `json:"normalized"`
+
+	// Weight is the factor's weight.
+	Weight float64 `json:"weight"`
+
+	// Contribution is the factor's share of the score: Normalized times
+	// Weight as a percentage of the sum of weights.
+	Contribution float64 `json:"contribution"`
+}
+
+// PoolRank is the ranking of one pool of a pair.
+type PoolRank struct {
+	// Rank is the 1-based position of the pool among scored pools. It is
+	// zero for pools that were not scored.
+	Rank int `json:"rank"`
+
+	// Protocol is the pool's protocol.
+	Protocol PoolProtocol `json:"protocol"`
+
+	// Address is the pool address.
+	Address string `json:"address"`
+
+	// Name is the pool name.
+	Name string `json:"name"`
+
+	// BinStep is the pool's bin step in basis points. It is zero for DAMM v2
+	// pools.
+	BinStep int `json:"bin_step"`
+
+	// BaseFeePct is the pool's base fee percentage.
+	BaseFeePct float64 `json:"base_fee_pct"`
+
+	// TVL is the pool's total value locked in USD.
+	TVL float64 `json:"tvl"`
+
+	// Scored reports whether the pool met MinTVL and its volume history was
+	// fetched. The factors and Score are only set when it is true.
+	Scored bool `json:"scored"`
+
+	// BelowMinTVL reports whether TVL is below PairRankOptions.MinTVL.
+	BelowMinTVL bool `json:"below_min_tvl"`
+
+	// FeeAPR is the fee-to-TVL ratio over the window, annualized, in percent.
+	// Its Normalized value is relative to the highest FeeAPR scored.
+	FeeAPR RankFactor `json:"fee_apr"`
+
+	// FarmAPR is the farming reward APR in percent. Its Normalized value is
+	// relative to the highest FarmAPR scored.
+	FarmAPR RankFactor `json:"farm_apr"`
+
+	// VolumeConsistency rates how steady the volume history was, from 0 for
+	// no volume to 1 for the same volume in every bucket: mean / (mean +
+	// standard deviation). It is its own Normalized value.
+	VolumeConsistency RankFactor `json:"volume_consistency"`
+
+	// Score is the sum of the factor contributions, from 0 to 100.
+	Score float64 `json:"score"`
+
+	// Err is the error returned when fetching the pool's volume history.
+	Err error `json:"-"`
+}
+
+// RankPairPools scores the DLMM and DAMM v2 pools of a token pair, as returned
+// by GetGroup, and ranks them from best to worst. Pools below MinTVL are not
+// scored and their volume history is not fetched; a failed volume history
+// request sets Err on its pool only, which is then not scored either. Unscored
+// pools follow the scored ones, larger TVL first. Ties are broken by TVL, then
+// address, so the ranking is reproducible. An unknown Window is an error.
+// opts may be nil.
+func (c *Client) RankPairPools(ctx context.Context, dlmmPools []dlmm.Pool, dammv2Pools []dammv2.Pool, opts *PairRankOptions) ([]PoolRank, error) {
+	if opts == nil {
+		opts = &PairRankOptions{}
+	}
+	window, err := opts.window()
+	if err != nil {
+		return nil, fmt.Errorf("meteora.RankPairPools: %w", err)
+	}
+	timeframe, lookback, now := opts.Timeframe, opts.Lookback, opts.Now
+	if timeframe == "" {
+		timeframe = "1h"
+	}
+	if lookback <= 0 {
+		lookback = 7 * 24 * time.Hour
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	weights := DefaultPairRankWeights
+	if opts.Weights != nil {
+		weights = *opts.Weights
+	}
+
+	ranks := make([]PoolRank, 0, len(dlmmPools)+len(dammv2Pools))
+	for _, p := range dlmmPools {
+		ranks = append(ranks, PoolRank{
+			Protocol:   ProtocolDLMM,
+			Address:    p.Address,
+			Name:       p.Name,
+			BinStep:    p.PoolConfig.BinStep,
+			BaseFeePct: p.PoolConfig.BaseFeePct,
+			TVL:        p.TVL,
+			FeeAPR:     RankFactor{Value: annualizedFeeTVLRatio(p.FeeTVLRatio, window)},
+			FarmAPR:    RankFactor{Value: p.FarmAPR},
+		})
+	}
+	for _, p := range dammv2Pools {
+		ranks = append(ranks, PoolRank{
+			Protocol:   ProtocolDAMMv2,
+			Address:    p.Address,
+			Name:       p.Name,
+			BaseFeePct: p.PoolConfig.BaseFeePct,
+			TVL:        p.TVL,
+			FeeAPR:     RankFactor{Value: annualizedFeeTVLRatio(dlmm.TimeBuckets(p.FeeTVLRatio), window)},
+			FarmAPR:    RankFactor{Value: p.FarmAPR},
+		})
+	}
+
+	var deep []int
+	for i := range ranks {
+		ranks[i].BelowMinTVL = ranks[i].TVL < opts.MinTVL
+		if !ranks[i].BelowMinTVL {
+			deep = append(deep, i)
+		}
+	}
+
+	start, end := now.Add(-lookback).Unix(), now.Unix()
+	batch.Run(ctx, len(deep), opts.Concurrency, func(ctx context.Context, j int) {
+		r := &ranks[deep[j]]
+		volumes, err := c.volumeHistory(ctx, r.Protocol, r.Address, timeframe, start, end)
+		if err != nil {
+			r.Err = fmt.Errorf("meteora.RankPairPools: %s: %w", r.Address, err)
+			return
+		}
+		r.VolumeConsistency.Value = volumeConsistency(volumes)
+		r.Scored = true
+	})
+
+	var maxFeeAPR, maxFarmAPR float64
+	for _, r := range ranks {
+		if r.Scored {
+			maxFeeAPR = max(maxFeeAPR, r.FeeAPR.Value)
+			maxFarmAPR = max(maxFarmAPR, r.FarmAPR.Value)
+		}
+	}
+	total := weights.FeeAPR + weights.FarmAPR + weights.VolumeConsistency
+	for i := range ranks {
+		r := &ranks[i]
+		if !r.Scored {
+			continue
+		}
+		r.FeeAPR.score(normalize(r.FeeAPR.Value, maxFeeAPR), weights.FeeAPR, total)
+		r.FarmAPR.score(normalize(r.FarmAPR.Value, maxFarmAPR), weights.FarmAPR, total)
+		r.VolumeConsistency.score(r.VolumeConsistency.Value, weights.VolumeConsistency, total)
+		r.Score = r.FeeAPR.Contribution + r.FarmAPR.Contribution + r.VolumeConsistency.Contribution
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		a, b := ranks[i], ranks[j]
+		if a.Scored != b.Scored {
+			return a.Scored
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.TVL != b.TVL {
+			return a.TVL > b.TVL
+		}
+		return a.Address < b.Address
+	})
+	for i := range ranks {
+		if ranks[i].Scored {
+			ranks[i].Rank = i + 1
+		}
+	}
+	return ranks, nil
+}
+
+// RankPair fetches every DLMM and DAMM v2 pool of the pair identified by
+// lexicalOrderMints, the two token mints in lexical order joined by "-", and
+// ranks them with RankPairPools. A pair with no pools on one protocol, which
+// its GetGroup reports as not found, contributes no pools from it. opts may be
+// nil.
+func (c *Client) RankPair(ctx context.Context, lexicalOrderMints string, opts *PairRankOptions) ([]PoolRank, error) {
+	if _, err := opts.window(); err != nil {
+		return nil, fmt.Errorf("meteora.RankPair: %w", err)
+	}
+	pageSize := 100
+
+	var dlmmPools []dlmm.Pool
+	for page := 1; ; page++ {
+		resp, err := c.DLMM.GetGroup(ctx, lexicalOrderMints, &dlmm.GetGroupParams{Page: &page, PageSize: &pageSize})
+		if isNotFound(err) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("meteora.RankPair: %w", err)
+		}
+		dlmmPools = append(dlmmPools, resp.Data...)
+		if page >= resp.Pages || len(resp.Data) == 0 {
+			break
+		}
+	}
+
+	var dammv2Pools []dammv2.Pool
+	for page := 1; ; page++ {
+		resp, err := c.DAMMv2.GetGroup(ctx, lexicalOrderMints, &dammv2.GetGroupParams{Page: &page, PageSize: &pageSize})
+		if isNotFound(err) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("meteora.RankPair: %w", err)
+		}
+		dammv2Pools = append(dammv2Pools, resp.Data...)
+		if page >= resp.Pages || len(resp.Data) == 0 {
+			break
+		}
+	}
+
+	ranks, err := c.RankPairPools(ctx, dlmmPools, dammv2Pools, opts)
+	if err != nil {
+		return nil, fmt.Errorf("meteora.RankPair: %w", err)
+	}
+	return ranks, nil
+}
+
+// window returns Window, defaulted, or an error if it is not one of the
+// FeeTVLRatio windows. o may be nil.
+func (o *PairRankOptions) window() (string, error) {
+	if o == nil || o.Window == "" {
+		return "24h", nil
+	}
+	switch o.Window {
+	case "30m", "1h", "2h", "4h", "12h", "24h":
+		return o.Window, nil
+	}
+	return "", fmt.Errorf("unknown window %q", o.Window)
+}
+
+// isNotFound reports whether err is an API error with status 404.
+func isNotFound(err error) bool {
+	var apiErr *httpclient.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// volumeHistory returns the bucket volumes of a pool from its protocol's
+// GetVolumeHistory.
+func (c *Client) volumeHistory(ctx context.Context, protocol PoolProtocol, address, timeframe string, start, end int64) ([]float64, error) {
+	var volumes []float64
+	if protocol == ProtocolDLMM {
+		resp, err := c.DLMM.GetVolumeHistory(ctx, address, &dlmm.VolumeHistoryParams{
+			TimeframeBasedParams: dlmm.TimeframeBasedParams{Timeframe: &timeframe, StartTime: &start, EndTime: &end},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Data {
+			volumes = append(volumes, v.Volume)
+		}
+		return volumes, nil
+	}
+
+	resp, err := c.DAMMv2.GetVolumeHistory(ctx, address, &dammv2.VolumeHistoryParams{Timeframe: &timeframe, StartTime: &start, EndTime: &end})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range resp.Data {
+		volumes = append(volumes, v.Volume)
+	}
+	return volumes, nil
+}
+
+func (f *RankFactor) score(normalized, weight, total float64) {
+	f.Normalized, f.Weight = normalized, weight
+	if total > 0 {
+		f.Contribution = normalized * weight / total * 100
+	}
+}
+
+// annualizedFeeTVLRatio returns the fee-to-TVL ratio of window scaled to a
+// year. window must be valid.
+func annualizedFeeTVLRatio(b dlmm.TimeBuckets, window string) float64 {
+	const year = 365 * 24 * time.Hour
+	switch window {
+	case "30m":
+		return b.Min30 * float64(year/(30*time.Minute))
+	case "1h":
+		return b.Hour1 * float64(year/time.Hour)
+	case "2h":
+		return b.Hour2 * float64(year/(2*time.Hour))
+	case "4h":
+		return b.Hour4 * float64(year/(4*time.Hour))
+	case "12h":
+		return b.Hour12 * float64(year/(12*time.Hour))
+	case "24h":
+		return b.Hour24 * float64(year/(24*time.Hour))
+	}
+	return 0
+}
+
+// volumeConsistency returns mean / (mean + standard deviation) of volumes, or
+// zero when there was no volume.
+func volumeConsistency(volumes []float64) float64 {
+	if len(volumes) == 0 {
+		return 0
+	}
+	var mean float64
+	for _, v := range volumes {
+		mean += v
+	}
+	mean /= float64(len(volumes))
+	if mean <= 0 {
+		return 0
+	}
+	var variance float64
+	for _, v := range volumes {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(volumes)))
+	return mean / (mean + stddev)
+}
+
+func normalize(v, maxV float64) float64 {
+	if maxV <= 0 {
+		return 0
+	}
+	return max(v, 0) / maxV
+}
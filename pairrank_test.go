@@ -0,0 +1,185 @@
+package meteora_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	meteora "github.com/ua1984/meteora-go"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/meteoratest"
+)
+
+type PairRankTestSuite struct {
+	suite.Suite
+	srv    *meteoratest.Server
+	client *meteora.Client
+	now    time.Time
+	dlmm   []dlmm.Pool
+	dammv2 []dammv2.Pool
+}
+
+func TestPairRank(t *testing.T) {
+	suite.Run(t, new(PairRankTestSuite))
+}
+
+func (s *PairRankTestSuite) SetupTest() {
+	s.now = time.Unix(1_700_000_000, 0)
+	sol, usdc := dlmm.Token{Address: "sol"}, dlmm.Token{Address: "usdc"}
+	s.dlmm = []dlmm.Pool{
+		{
+			Address: "steady", TokenX: sol, TokenY: usdc, TVL: 100_000,
+			PoolConfig:  dlmm.PoolConfig{BinStep: 10, BaseFeePct: 0.1},
+			FeeTVLRatio: dlmm.TimeBuckets{Hour24: 0.1, Hour1: 0.01},
+		},
+		{
+			Address: "bursty", TokenX: sol, TokenY: usdc, TVL: 50_000, FarmAPR: 10,
+			PoolConfig:  dlmm.PoolConfig{BinStep: 80, BaseFeePct: 0.8},
+			FeeTVLRatio: dlmm.TimeBuckets{Hour24: 0.2},
+		},
+	}
+	s.dammv2 = []dammv2.Pool{
+		{Address: "shallow", TokenX: dammv2.Token{Address: "sol"}, TokenY: dammv2.Token{Address: "usdc"}, TVL: 500},
+	}
+
+	hour := int64(3600)
+	ts := s.now.Unix()
+	s.srv = meteoratest.NewServer()
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DLMM.Pools = s.dlmm
+		d.DAMMv2.Pools = s.dammv2
+		d.DLMM.VolumeHistory["steady"] = []dlmm.VolumeHistory{{Timestamp: ts - 2*hour, Volume: 100}, {Timestamp: ts - hour, Volume: 100}}
+		d.DLMM.VolumeHistory["bursty"] = []dlmm.VolumeHistory{{Timestamp: ts - 2*hour, Volume: 0}, {Timestamp: ts - hour, Volume: 200}}
+	})
+	s.client = s.srv.Client()
+}
+
+func (s *PairRankTestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func (s *PairRankTestSuite) TestRankPairPools() {
+	// Arrange
+	opts := &meteora.PairRankOptions{MinTVL: 1000, Now: s.now}
+
+	// Act
+	ranks, err := s.client.RankPairPools(context.Background(), s.dlmm, s.dammv2, opts)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(ranks, 3)
+	s.Equal("bursty", ranks[0].Address)
+	s.Equal(1, ranks[0].Rank)
+	s.Equal(80, ranks[0].BinStep)
+	s.InDelta(73, ranks[0].FeeAPR.Value, 1e-9)
+	s.InDelta(50, ranks[0].FeeAPR.Contribution, 1e-9)
+	s.InDelta(20, ranks[0].FarmAPR.Contribution, 1e-9)
+	s.InDelta(0.5, ranks[0].VolumeConsistency.Value, 1e-9)
+	s.InDelta(85, ranks[0].Score, 1e-9)
+
+	s.Equal("steady", ranks[1].Address)
+	s.Equal(2, ranks[1].Rank)
+	s.InDelta(0.5, ranks[1].FeeAPR.Normalized, 1e-9)
+	s.InDelta(1, ranks[1].VolumeConsistency.Normalized, 1e-9)
+	s.InDelta(55, ranks[1].Score, 1e-9)
+
+	s.Equal("shallow", ranks[2].Address)
+	s.Equal(meteora.ProtocolDAMMv2, ranks[2].Protocol)
+	s.True(ranks[2].BelowMinTVL)
+	s.False(ranks[2].Scored)
+	s.Zero(ranks[2].Rank)
+	s.Len(s.srv.Requests(), 2)
+}
+
+func (s *PairRankTestSuite) TestRankPairPoolsOptions() {
+	tests := []struct {
+		name      string
+		opts      *meteora.PairRankOptions
+		wantFirst string
+		wantScore float64
+	}{
+		{
+			name:      "should rank by volume consistency alone",
+			opts:      &meteora.PairRankOptions{Now: s.now, MinTVL: 1000, Weights: &meteora.PairRankWeights{VolumeConsistency: 1}},
+			wantFirst: "steady",
+			wantScore: 100,
+		},
+		{
+			name:      "should annualize the chosen window",
+			opts:      &meteora.PairRankOptions{Now: s.now, MinTVL: 1000, Window: "1h", Weights: &meteora.PairRankWeights{FeeAPR: 1}},
+			wantFirst: "steady",
+			wantScore: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			ranks, err := s.client.RankPairPools(context.Background(), s.dlmm, s.dammv2, tt.opts)
+
+			// Assert
+			s.Require().NoError(err)
+			s.Equal(tt.wantFirst, ranks[0].Address)
+			s.InDelta(tt.wantScore, ranks[0].Score, 1e-9)
+		})
+	}
+}
+
+func (s *PairRankTestSuite) TestRankPair() {
+	// Act
+	ranks, err := s.client.RankPair(context.Background(), "sol-usdc", &meteora.PairRankOptions{MinTVL: 1000, Now: s.now})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(ranks, 3)
+	s.Equal("bursty", ranks[0].Address)
+	s.Equal("shallow", ranks[2].Address)
+}
+
+func (s *PairRankTestSuite) TestRankPairDLMMOnly() {
+	// Arrange
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DAMMv2.Pools = nil
+	})
+
+	// Act
+	ranks, err := s.client.RankPair(context.Background(), "sol-usdc", &meteora.PairRankOptions{MinTVL: 1000, Now: s.now})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(ranks, 2)
+	s.Equal(meteora.ProtocolDLMM, ranks[0].Protocol)
+	s.Equal(meteora.ProtocolDLMM, ranks[1].Protocol)
+}
+
+func (s *PairRankTestSuite) TestRankPairUnknownWindow() {
+	// Arrange
+	opts := &meteora.PairRankOptions{Window: "7d", Now: s.now}
+
+	// Act
+	ranks, err := s.client.RankPair(context.Background(), "sol-usdc", opts)
+	poolRanks, poolsErr := s.client.RankPairPools(context.Background(), s.dlmm, s.dammv2, opts)
+
+	// Assert
+	s.EqualError(err, `meteora.RankPair: unknown window "7d"`)
+	s.Nil(ranks)
+	s.EqualError(poolsErr, `meteora.RankPairPools: unknown window "7d"`)
+	s.Nil(poolRanks)
+	s.Empty(s.srv.Requests())
+}
+
+func (s *PairRankTestSuite) TestRankPairPoolsError() {
+	// Arrange
+	s.srv.InjectFault(meteoratest.Fault{Path: meteoratest.DLMMPrefix, Status: http.StatusBadRequest})
+
+	// Act
+	ranks, err := s.client.RankPairPools(context.Background(), s.dlmm[:1], nil, &meteora.PairRankOptions{Now: s.now})
+
+	// Assert
+	s.Require().NoError(err)
+	s.ErrorContains(ranks[0].Err, "meteora.RankPairPools: steady")
+	s.False(ranks[0].Scored)
+}
@@ -0,0 +1,49 @@
+package meteora
+
+import "github.com/ua1984/meteora-go/internal/cassette"
+
+// RecorderMode selects whether WithRecorder records or replays exchanges.
+type RecorderMode = cassette.Mode
+
+const (
+	// Replay serves recorded responses and never touches the network.
+	// Requests without a recording fail with an error wrapping ErrNoRecording
+	// and are not retried.
+	Replay = cassette.Replay
+
+	// RecordFinal sends requests over the network and records the response of
+	// the final attempt of each request, after any retries.
+	RecordFinal = cassette.RecordFinal
+
+	// RecordAll sends requests over the network and records the response of
+	// every attempt, so retried 429 and 5xx responses replay in order.
+	RecordAll = cassette.RecordAll
+)
+
+// ErrNoRecording is returned in Replay mode for requests that were never
+// recorded.
+var ErrNoRecording = cassette.ErrNoRecording
+
+// RecorderOption configures the recorder installed by WithRecorder.
+type RecorderOption = cassette.Option
+
+// ScrubHeaders removes the named request and response headers from
+// recordings. Authorization, Cookie, Proxy-Authorization and Set-Cookie are
+// always removed.
+func ScrubHeaders(names ...string) RecorderOption {
+	return cassette.ScrubHeaders(names...)
+}
+
+// RedactWallets replaces the given wallet addresses in recorded paths,
+// queries and response bodies with the value returned by RedactedWallet.
+// Replayed requests are matched after redaction, so tests can call the API
+// with either the real address or the placeholder.
+func RedactWallets(wallets ...string) RecorderOption {
+	return cassette.RedactWallets(wallets...)
+}
+
+// RedactedWallet returns the placeholder RedactWallets substitutes for
+// wallet. It is stable across runs.
+func RedactedWallet(wallet string) string {
+	return cassette.Placeholder(wallet)
+}
@@ -0,0 +1,70 @@
+package meteora_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	meteora "github.com/ua1984/meteora-go"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/meteoratest"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+type RecorderTestSuite struct {
+	suite.Suite
+}
+
+func TestRecorder(t *testing.T) {
+	suite.Run(t, new(RecorderTestSuite))
+}
+
+func (s *RecorderTestSuite) TestRecordThenReplayOffline() {
+	// Arrange
+	const wallet = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	dir := s.T().TempDir()
+	srv := meteoratest.NewServer()
+	srv.Seed(func(d *meteoratest.Data) {
+		d.DLMM.Pools = []dlmm.Pool{{Address: "pool1", Name: "SOL-USDC", TVL: 1000}}
+		d.Stake2Earn.Vaults = []stake2earn.Vault{{VaultAddress: "vault1"}}
+	})
+	ctx := context.Background()
+	redact := meteora.RedactWallets(wallet)
+
+	// Act
+	recording := srv.Client(meteora.WithRecorder(meteora.RecordFinal, dir, redact))
+	recordedPool, err := recording.DLMM.GetPool(ctx, "pool1")
+	s.Require().NoError(err)
+	_, err = recording.DLMM.GetPortfolioTotal(ctx, wallet)
+	s.Require().NoError(err)
+	recordedVault, err := recording.Stake2Earn.GetVault(ctx, "vault1")
+	s.Require().NoError(err)
+	srv.Close()
+
+	replaying := srv.Client(meteora.WithRecorder(meteora.Replay, dir, redact))
+	replayedPool, poolErr := replaying.DLMM.GetPool(ctx, "pool1")
+	_, portfolioErr := replaying.DLMM.GetPortfolioTotal(ctx, wallet)
+	replayedVault, vaultErr := replaying.Stake2Earn.GetVault(ctx, "vault1")
+	_, missingErr := replaying.DLMM.GetPool(ctx, "pool2")
+
+	// Assert
+	s.NoError(poolErr)
+	s.NoError(portfolioErr)
+	s.NoError(vaultErr)
+	s.Equal(recordedPool, replayedPool)
+	s.Equal(recordedVault, replayedVault)
+	s.ErrorIs(missingErr, meteora.ErrNoRecording)
+
+	for _, service := range []string{"dlmm", "stake2earn"} {
+		files, err := filepath.Glob(filepath.Join(dir, service, "*.json"))
+		s.Require().NoError(err)
+		s.NotEmpty(files, service)
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			s.Require().NoError(err)
+			s.NotContains(string(data), wallet)
+		}
+	}
+}
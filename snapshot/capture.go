@@ -0,0 +1,146 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+// pageSize is the largest page size accepted by the DLMM and DAMM v2 pool
+// listings.
+const pageSize = 1000
+
+// Sources are the clients pools are listed from. A nil source is skipped.
+// The service fields of meteora.Client can be used directly.
+type Sources struct {
+	// DLMM lists DLMM pools.
+	DLMM dlmm.API
+
+	// DAMMv2 lists DAMM v2 pools.
+	DAMMv2 dammv2.API
+
+	// DAMMv1 lists DAMM v1 pools.
+	DAMMv1 dammv1.API
+}
+
+// Capturer lists the pools of each service and saves them as snapshots.
+type Capturer struct {
+	sources Sources
+	store   Store
+	now     func() time.Time
+}
+
+// NewCapturer returns a Capturer saving snapshots of sources to store.
+func NewCapturer(sources Sources, store Store) *Capturer {
+	return &Capturer{sources: sources, store: store, now: time.Now}
+}
+
+// Capture lists every pool of each configured service and saves one snapshot
+// per service. A service that fails does not prevent the others from being
+// saved; the saved snapshots are returned with the joined errors.
+func (c *Capturer) Capture(ctx context.Context) ([]*Snapshot, error) {
+	type capture struct {
+		service Service
+		list    func(ctx context.Context) ([]PoolMetrics, error)
+	}
+	var captures []capture
+	if c.sources.DLMM != nil {
+		captures = append(captures, capture{DLMM, c.listDLMM})
+	}
+	if c.sources.DAMMv2 != nil {
+		captures = append(captures, capture{DAMMv2, c.listDAMMv2})
+	}
+	if c.sources.DAMMv1 != nil {
+		captures = append(captures, capture{DAMMv1, c.listDAMMv1})
+	}
+
+	var snaps []*Snapshot
+	var errs []error
+	for _, cp := range captures {
+		snap := &Snapshot{Service: cp.service, Time: c.now().UTC()}
+		pools, err := cp.list(ctx)
+		if err == nil {
+			snap.Pools = pools
+			err = c.store.Save(ctx, snap)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("snapshot.Capturer.Capture: %s: %w", cp.service, err))
+			continue
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, errors.Join(errs...)
+}
+
+// Run captures immediately and then every interval until ctx is done. Capture
+// errors are passed to onError, which may be nil, and do not stop the loop.
+// Run returns the error of ctx, or an error right away if interval is not
+// positive.
+func (c *Capturer) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("snapshot.Capturer.Run: interval %v is not positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := c.Capture(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Capturer) listDLMM(ctx context.Context) ([]PoolMetrics, error) {
+	var pools []PoolMetrics
+	for page := 1; ; page++ {
+		p, size := page, pageSize
+		resp, err := c.sources.DLMM.ListPools(ctx, &dlmm.ListPoolsParams{Page: &p, PageSize: &size})
+		if err != nil {
+			return nil, err
+		}
+		for _, pool := range resp.Data {
+			pools = append(pools, fromDLMM(pool))
+		}
+		if page >= resp.Pages || len(resp.Data) == 0 {
+			return pools, nil
+		}
+	}
+}
+
+func (c *Capturer) listDAMMv2(ctx context.Context) ([]PoolMetrics, error) {
+	var pools []PoolMetrics
+	for page := 1; ; page++ {
+		p, size := page, pageSize
+		resp, err := c.sources.DAMMv2.ListPools(ctx, &dammv2.ListPoolsParams{Page: &p, PageSize: &size})
+		if err != nil {
+			return nil, err
+		}
+		for _, pool := range resp.Data {
+			pools = append(pools, fromDAMMv2(pool))
+		}
+		if page >= resp.Pages || len(resp.Data) == 0 {
+			return pools, nil
+		}
+	}
+}
+
+func (c *Capturer) listDAMMv1(ctx context.Context) ([]PoolMetrics, error) {
+	resp, err := c.sources.DAMMv1.ListPools(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	pools := make([]PoolMetrics, len(resp))
+	for i, pool := range resp {
+		pools[i] = fromDAMMv1(pool)
+	}
+	return pools, nil
+}
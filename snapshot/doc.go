@@ -0,0 +1,41 @@
+// Package snapshot records the pool listings of the DLMM, DAMM v2 and DAMM v1
+// APIs over time.
+//
+// The APIs only report current values: TVL, APR and the 24-hour volume, fee
+// and fee/TVL figures of each pool. A Capturer lists every pool of each
+// service and saves the metrics as a Snapshot in a Store, building the
+// history the APIs do not keep. Two stores are provided:
+//
+//   - MemoryStore keeps snapshots in memory.
+//   - FileStore appends snapshots to a single file, one JSON document per
+//     line, and reads them back on demand.
+//
+// The history is queried with Series (the metrics of one pool over time), At
+// (the snapshot in effect at a given time), TopMovers (the largest changes of
+// a metric between two snapshots) and Disappeared/Appeared (pools removed
+// from or added to the listing).
+//
+// # Usage
+//
+//	store, err := snapshot.OpenFileStore("pools.snapshots")
+//	if err != nil {
+//	    return err
+//	}
+//	defer store.Close()
+//
+//	client := meteora.New()
+//	capturer := snapshot.NewCapturer(snapshot.Sources{
+//	    DLMM:   client.DLMM,
+//	    DAMMv2: client.DAMMv2,
+//	    DAMMv1: client.DAMMv1,
+//	}, store)
+//	go capturer.Run(ctx, time.Hour, func(err error) { log.Print(err) })
+//
+//	// TVL history of a pool over the last week.
+//	points, err := snapshot.Series(ctx, store, snapshot.DLMM, address, time.Now().AddDate(0, 0, -7), time.Time{})
+//
+//	// Largest TVL changes over the last day.
+//	day, err := snapshot.At(ctx, store, snapshot.DLMM, time.Now().AddDate(0, 0, -1))
+//	now, err := snapshot.At(ctx, store, snapshot.DLMM, time.Time{})
+//	movers := snapshot.TopMovers(day, now, snapshot.MetricTVL, 10)
+package snapshot
@@ -0,0 +1,148 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileStore keeps snapshots in a single append-only file, one JSON document
+// per line. Only the service, time and position of each snapshot are kept in
+// memory; snapshots are read from disk when queried.
+//
+// Every Save is synced to disk before it returns. A snapshot left incomplete
+// by a crash is discarded when the file is opened again.
+type FileStore struct {
+	mu    sync.RWMutex
+	f     *os.File
+	size  int64
+	index map[Service][]fileEntry
+}
+
+var _ Store = (*FileStore)(nil)
+
+// fileEntry locates a snapshot in the file.
+type fileEntry struct {
+	time   time.Time
+	offset int64
+	length int64
+}
+
+// OpenFileStore opens the store at path, creating the file if needed.
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot.OpenFileStore: %w", err)
+	}
+	s := &FileStore{f: f, index: map[Service][]fileEntry{}}
+	if err := s.load(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("snapshot.OpenFileStore: %w", err)
+	}
+	return s, nil
+}
+
+// load builds the index and truncates a trailing incomplete line.
+func (s *FileStore) load() error {
+	r := bufio.NewReader(s.f)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				if err := s.f.Truncate(offset); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var head struct {
+			Service Service   `json:"service"`
+			Time    time.Time `json:"time"`
+		}
+		if err := json.Unmarshal(line, &head); err != nil {
+			return fmt.Errorf("corrupt snapshot at offset %d: %w", offset, err)
+		}
+		entry := fileEntry{time: head.Time, offset: offset, length: int64(len(line))}
+		s.index[head.Service] = insertSorted(s.index[head.Service], entry, func(e fileEntry) time.Time { return e.time })
+		offset += entry.length
+	}
+	s.size = offset
+	return nil
+}
+
+// Save appends snap to the file and syncs it.
+func (s *FileStore) Save(ctx context.Context, snap *Snapshot) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(snap); err != nil {
+		return fmt.Errorf("snapshot.FileStore.Save: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.WriteAt(buf.Bytes(), s.size); err != nil {
+		return fmt.Errorf("snapshot.FileStore.Save: %w", err)
+	}
+	if err := s.f.Sync(); err != nil {
+		return fmt.Errorf("snapshot.FileStore.Save: %w", err)
+	}
+	entry := fileEntry{time: snap.Time, offset: s.size, length: int64(buf.Len())}
+	s.index[snap.Service] = insertSorted(s.index[snap.Service], entry, func(e fileEntry) time.Time { return e.time })
+	s.size += entry.length
+	return nil
+}
+
+// Snapshots reads the snapshots of service between from and to.
+func (s *FileStore) Snapshots(ctx context.Context, service Service, from, to time.Time) ([]*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := s.index[service]
+	lo, hi := timeRange(len(entries), func(i int) time.Time { return entries[i].time }, from, to)
+
+	snaps := make([]*Snapshot, 0, hi-lo)
+	for _, e := range entries[lo:hi] {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("snapshot.FileStore.Snapshots: %w", err)
+		}
+		data := make([]byte, e.length)
+		if _, err := s.f.ReadAt(data, e.offset); err != nil {
+			return nil, fmt.Errorf("snapshot.FileStore.Snapshots: %w", err)
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, fmt.Errorf("snapshot.FileStore.Snapshots: %w", err)
+		}
+		snaps = append(snaps, &snap)
+	}
+	return snaps, nil
+}
+
+// Times returns the times of the stored snapshots of service without reading
+// them from disk.
+func (s *FileStore) Times(ctx context.Context, service Service) ([]time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	times := make([]time.Time, len(s.index[service]))
+	for i, e := range s.index[service] {
+		times[i] = e.time
+	}
+	return times, nil
+}
+
+// Close closes the file.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
@@ -0,0 +1,139 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrNoSnapshot is returned by At when no snapshot was taken at or before the
+// requested time.
+var ErrNoSnapshot = errors.New("snapshot: no snapshot")
+
+// Point is the metrics of a pool in one snapshot.
+type Point struct {
+	// Time is the time of the snapshot.
+	Time time.Time `json:"time"`
+
+	// Pool is the metrics of the pool at Time.
+	Pool PoolMetrics `json:"pool"`
+}
+
+// Series returns the metrics of the pool at address in every snapshot of
+// service between from and to, oldest first. Snapshots in which the pool is
+// missing are skipped.
+func Series(ctx context.Context, store Store, service Service, address string, from, to time.Time) ([]Point, error) {
+	snaps, err := store.Snapshots(ctx, service, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot.Series: %w", err)
+	}
+	var points []Point
+	for _, snap := range snaps {
+		if p := snap.Pool(address); p != nil {
+			points = append(points, Point{Time: snap.Time, Pool: *p})
+		}
+	}
+	return points, nil
+}
+
+// At returns the latest snapshot of service taken at or before t. A zero t
+// returns the latest snapshot.
+func At(ctx context.Context, store Store, service Service, t time.Time) (*Snapshot, error) {
+	times, err := store.Times(ctx, service)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot.At: %w", err)
+	}
+	i := len(times)
+	if !t.IsZero() {
+		i = sort.Search(len(times), func(i int) bool { return times[i].After(t) })
+	}
+	if i == 0 {
+		return nil, fmt.Errorf("snapshot.At: %s at %s: %w", service, t.Format(time.RFC3339), ErrNoSnapshot)
+	}
+	at := times[i-1]
+	snaps, err := store.Snapshots(ctx, service, at, at)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot.At: %w", err)
+	}
+	if len(snaps) == 0 {
+		return nil, fmt.Errorf("snapshot.At: %s at %s: %w", service, t.Format(time.RFC3339), ErrNoSnapshot)
+	}
+	return snaps[len(snaps)-1], nil
+}
+
+// Mover is the change of a metric of a pool between two snapshots.
+type Mover struct {
+	// Address is the pool address.
+	Address string `json:"address"`
+
+	// Name is the pool name in the later snapshot.
+	Name string `json:"name"`
+
+	// Before is the value in the earlier snapshot.
+	Before float64 `json:"before"`
+
+	// After is the value in the later snapshot.
+	After float64 `json:"after"`
+
+	// Change is After minus Before.
+	Change float64 `json:"change"`
+
+	// ChangePct is Change as a percentage of Before. It is zero when Before
+	// is zero.
+	ChangePct float64 `json:"change_pct"`
+}
+
+// TopMovers returns the n pools present in both snapshots whose metric
+// changed the most in absolute terms, largest change first. A non-positive n
+// returns every pool.
+func TopMovers(before, after *Snapshot, metric Metric, n int) []Mover {
+	prev := before.index()
+	var movers []Mover
+	for _, p := range after.Pools {
+		b, ok := prev[p.Address]
+		if !ok {
+			continue
+		}
+		m := Mover{
+			Address: p.Address,
+			Name:    p.Name,
+			Before:  metric.Value(*b),
+			After:   metric.Value(p),
+		}
+		m.Change = m.After - m.Before
+		if m.Before != 0 {
+			m.ChangePct = m.Change / math.Abs(m.Before) * 100
+		}
+		movers = append(movers, m)
+	}
+	sort.SliceStable(movers, func(i, j int) bool {
+		return math.Abs(movers[i].Change) > math.Abs(movers[j].Change)
+	})
+	if n > 0 && len(movers) > n {
+		movers = movers[:n]
+	}
+	return movers
+}
+
+// Disappeared returns the pools of before that are missing from after.
+func Disappeared(before, after *Snapshot) []PoolMetrics {
+	return missing(before, after.index())
+}
+
+// Appeared returns the pools of after that are missing from before.
+func Appeared(before, after *Snapshot) []PoolMetrics {
+	return missing(after, before.index())
+}
+
+func missing(s *Snapshot, other map[string]*PoolMetrics) []PoolMetrics {
+	var pools []PoolMetrics
+	for _, p := range s.Pools {
+		if _, ok := other[p.Address]; !ok {
+			pools = append(pools, p)
+		}
+	}
+	return pools
+}
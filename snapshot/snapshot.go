@@ -0,0 +1,159 @@
+package snapshot
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+// Service identifies the API a snapshot was captured from.
+type Service string
+
+const (
+	// DLMM is the DLMM API.
+	DLMM Service = "dlmm"
+
+	// DAMMv2 is the DAMM v2 API.
+	DAMMv2 Service = "dammv2"
+
+	// DAMMv1 is the DAMM v1 API.
+	DAMMv1 Service = "dammv1"
+)
+
+// Snapshot holds the metrics of every pool of a service at one point in time.
+type Snapshot struct {
+	// Service is the API the pools were listed from.
+	Service Service `json:"service"`
+
+	// Time is when the capture started.
+	Time time.Time `json:"time"`
+
+	// Pools holds one entry per pool, in the order returned by the API.
+	Pools []PoolMetrics `json:"pools"`
+}
+
+// Pool returns the metrics of the pool with the given address, or nil if the
+// pool is not in the snapshot.
+func (s *Snapshot) Pool(address string) *PoolMetrics {
+	for i := range s.Pools {
+		if s.Pools[i].Address == address {
+			return &s.Pools[i]
+		}
+	}
+	return nil
+}
+
+func (s *Snapshot) index() map[string]*PoolMetrics {
+	m := make(map[string]*PoolMetrics, len(s.Pools))
+	for i := range s.Pools {
+		m[s.Pools[i].Address] = &s.Pools[i]
+	}
+	return m
+}
+
+// PoolMetrics is the service-independent view of a pool kept in snapshots.
+type PoolMetrics struct {
+	// Address is the pool address.
+	Address string `json:"address"`
+
+	// Name is the pool name, usually "TOKENX-TOKENY".
+	Name string `json:"name"`
+
+	// TVL is the total value locked in USD.
+	TVL float64 `json:"tvl"`
+
+	// APR is the annual percentage rate in percent. DAMM v2 does not report
+	// one; it is estimated as FeeTVLRatio24h × 365.
+	APR float64 `json:"apr"`
+
+	// Volume24h is the trading volume of the last 24 hours in USD.
+	Volume24h float64 `json:"volume_24h"`
+
+	// Fees24h is the fees of the last 24 hours in USD.
+	Fees24h float64 `json:"fees_24h"`
+
+	// FeeTVLRatio24h is Fees24h as a percentage of TVL. It is computed from
+	// the fee volume for DAMM v1, which does not report it.
+	FeeTVLRatio24h float64 `json:"fee_tvl_ratio_24h"`
+}
+
+// Metric selects a numeric field of PoolMetrics.
+type Metric string
+
+const (
+	// MetricTVL selects PoolMetrics.TVL.
+	MetricTVL Metric = "tvl"
+
+	// MetricAPR selects PoolMetrics.APR.
+	MetricAPR Metric = "apr"
+
+	// MetricVolume24h selects PoolMetrics.Volume24h.
+	MetricVolume24h Metric = "volume_24h"
+
+	// MetricFees24h selects PoolMetrics.Fees24h.
+	MetricFees24h Metric = "fees_24h"
+
+	// MetricFeeTVLRatio24h selects PoolMetrics.FeeTVLRatio24h.
+	MetricFeeTVLRatio24h Metric = "fee_tvl_ratio_24h"
+)
+
+// Value returns the value of m in p. Unknown metrics are zero.
+func (m Metric) Value(p PoolMetrics) float64 {
+	switch m {
+	case MetricTVL:
+		return p.TVL
+	case MetricAPR:
+		return p.APR
+	case MetricVolume24h:
+		return p.Volume24h
+	case MetricFees24h:
+		return p.Fees24h
+	case MetricFeeTVLRatio24h:
+		return p.FeeTVLRatio24h
+	default:
+		return 0
+	}
+}
+
+func fromDLMM(p dlmm.Pool) PoolMetrics {
+	return PoolMetrics{
+		Address:        p.Address,
+		Name:           p.Name,
+		TVL:            p.TVL,
+		APR:            p.APR,
+		Volume24h:      p.Volume.Hour24,
+		Fees24h:        p.Fees.Hour24,
+		FeeTVLRatio24h: p.FeeTVLRatio.Hour24,
+	}
+}
+
+func fromDAMMv2(p dammv2.Pool) PoolMetrics {
+	return PoolMetrics{
+		Address:        p.Address,
+		Name:           p.Name,
+		TVL:            p.TVL,
+		APR:            p.FeeTVLRatio.Hour24 * 365,
+		Volume24h:      p.Volume.Hour24,
+		Fees24h:        p.Fees.Hour24,
+		FeeTVLRatio24h: p.FeeTVLRatio.Hour24,
+	}
+}
+
+func fromDAMMv1(p dammv1.Pool) PoolMetrics {
+	tvl, _ := strconv.ParseFloat(p.PoolTVL, 64)
+	m := PoolMetrics{
+		Address:   p.PoolAddress,
+		Name:      p.PoolName,
+		TVL:       tvl,
+		APR:       p.APR,
+		Volume24h: p.TradingVolume,
+		Fees24h:   p.FeeVolume,
+	}
+	if tvl > 0 {
+		m.FeeTVLRatio24h = p.FeeVolume / tvl * 100
+	}
+	return m
+}
@@ -0,0 +1,209 @@
+package snapshot_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/dammv2"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/meteoratest"
+	"github.com/ua1984/meteora-go/snapshot"
+)
+
+type SnapshotTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestSnapshot(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
+
+func (s *SnapshotTestSuite) SetupTest() {
+	s.ctx = context.Background()
+}
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func snap(hours int, pools ...snapshot.PoolMetrics) *snapshot.Snapshot {
+	return &snapshot.Snapshot{Service: snapshot.DLMM, Time: t0.Add(time.Duration(hours) * time.Hour), Pools: pools}
+}
+
+func pool(address string, tvl float64) snapshot.PoolMetrics {
+	return snapshot.PoolMetrics{Address: address, Name: address + "-USDC", TVL: tvl}
+}
+
+func (s *SnapshotTestSuite) stores() map[string]func() snapshot.Store {
+	return map[string]func() snapshot.Store{
+		"memory": func() snapshot.Store { return snapshot.NewMemoryStore() },
+		"file": func() snapshot.Store {
+			store, err := snapshot.OpenFileStore(filepath.Join(s.T().TempDir(), "snapshots"))
+			s.Require().NoError(err)
+			s.T().Cleanup(func() { store.Close() })
+			return store
+		},
+	}
+}
+
+func (s *SnapshotTestSuite) TestStore() {
+	for name, newStore := range s.stores() {
+		s.Run(name, func() {
+			// Arrange
+			store := newStore()
+			for _, sn := range []*snapshot.Snapshot{snap(2, pool("a", 2)), snap(0, pool("a", 0)), snap(1, pool("a", 1))} {
+				s.Require().NoError(store.Save(s.ctx, sn))
+			}
+			s.Require().NoError(store.Save(s.ctx, &snapshot.Snapshot{Service: snapshot.DAMMv1, Time: t0}))
+
+			// Act
+			all, err := store.Snapshots(s.ctx, snapshot.DLMM, time.Time{}, time.Time{})
+			s.Require().NoError(err)
+			ranged, err := store.Snapshots(s.ctx, snapshot.DLMM, t0.Add(time.Hour), t0.Add(2*time.Hour))
+			s.Require().NoError(err)
+			times, err := store.Times(s.ctx, snapshot.DLMM)
+			s.Require().NoError(err)
+
+			// Assert
+			s.Require().Len(all, 3)
+			s.Equal([]float64{0, 1, 2}, []float64{all[0].Pools[0].TVL, all[1].Pools[0].TVL, all[2].Pools[0].TVL})
+			s.Len(ranged, 2)
+			s.Equal([]time.Time{t0, t0.Add(time.Hour), t0.Add(2 * time.Hour)}, times)
+		})
+	}
+}
+
+func (s *SnapshotTestSuite) TestFileStoreReopen() {
+	// Arrange
+	path := filepath.Join(s.T().TempDir(), "snapshots")
+	store, err := snapshot.OpenFileStore(path)
+	s.Require().NoError(err)
+	s.Require().NoError(store.Save(s.ctx, snap(0, pool("a", 1))))
+	s.Require().NoError(store.Close())
+
+	// Simulate a crash in the middle of a write.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	s.Require().NoError(err)
+	_, err = f.WriteString(`{"service":"dlmm","time":"2026-01-01T01:00:00Z","pools":[{"addr`)
+	s.Require().NoError(err)
+	s.Require().NoError(f.Close())
+
+	// Act
+	store, err = snapshot.OpenFileStore(path)
+	s.Require().NoError(err)
+	defer store.Close()
+	s.Require().NoError(store.Save(s.ctx, snap(2, pool("a", 3))))
+	snaps, err := store.Snapshots(s.ctx, snapshot.DLMM, time.Time{}, time.Time{})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(snaps, 2)
+	s.Equal(pool("a", 1), snaps[0].Pools[0])
+	s.Equal(pool("a", 3), snaps[1].Pools[0])
+}
+
+func (s *SnapshotTestSuite) TestCapture() {
+	// Arrange
+	srv := meteoratest.NewServer()
+	defer srv.Close()
+	srv.Seed(func(d *meteoratest.Data) {
+		d.DLMM.Pools = []dlmm.Pool{
+			{Address: "d1", Name: "SOL-USDC", TVL: 100, APR: 12, Volume: dlmm.TimeBuckets{Hour24: 50}, Fees: dlmm.TimeBuckets{Hour24: 1}, FeeTVLRatio: dlmm.TimeBuckets{Hour24: 1}},
+			{Address: "d2", TVL: 200},
+		}
+		d.DAMMv1.Pools = []dammv1.Pool{{PoolAddress: "v1", PoolName: "SOL-USDC", PoolTVL: "400", APR: 3, TradingVolume: 80, FeeVolume: 2}}
+	})
+	client := srv.Client()
+	failing := &meteoratest.FakeDAMMv2{
+		ListPoolsFunc: func(ctx context.Context, params *dammv2.ListPoolsParams) (*dammv2.PaginatedResponse[dammv2.Pool], error) {
+			return nil, errors.New("unavailable")
+		},
+	}
+	store := snapshot.NewMemoryStore()
+	capturer := snapshot.NewCapturer(snapshot.Sources{DLMM: client.DLMM, DAMMv2: failing, DAMMv1: client.DAMMv1}, store)
+
+	// Act
+	snaps, err := capturer.Capture(s.ctx)
+
+	// Assert
+	s.ErrorContains(err, "dammv2: unavailable")
+	s.Require().Len(snaps, 2)
+	s.Equal(snapshot.PoolMetrics{Address: "d1", Name: "SOL-USDC", TVL: 100, APR: 12, Volume24h: 50, Fees24h: 1, FeeTVLRatio24h: 1}, snaps[0].Pools[0])
+	s.Len(snaps[0].Pools, 2)
+	s.Equal(snapshot.PoolMetrics{Address: "v1", Name: "SOL-USDC", TVL: 400, APR: 3, Volume24h: 80, Fees24h: 2, FeeTVLRatio24h: 0.5}, snaps[1].Pools[0])
+	saved, err := store.Snapshots(s.ctx, snapshot.DLMM, time.Time{}, time.Time{})
+	s.Require().NoError(err)
+	s.Len(saved, 1)
+	s.Equal(1, len(failing.Calls()))
+}
+
+func (s *SnapshotTestSuite) TestRunInvalidInterval() {
+	// Arrange
+	store := snapshot.NewMemoryStore()
+	capturer := snapshot.NewCapturer(snapshot.Sources{}, store)
+	called := false
+
+	// Act
+	err := capturer.Run(s.ctx, -time.Second, func(error) { called = true })
+
+	// Assert
+	s.EqualError(err, "snapshot.Capturer.Run: interval -1s is not positive")
+	s.False(called)
+}
+
+func (s *SnapshotTestSuite) TestQueries() {
+	// Arrange
+	store := snapshot.NewMemoryStore()
+	first := snap(0, pool("a", 100), pool("b", 100), pool("gone", 5))
+	second := snap(24, pool("a", 150), pool("b", 20), pool("new", 7))
+	s.Require().NoError(store.Save(s.ctx, first))
+	s.Require().NoError(store.Save(s.ctx, second))
+
+	s.Run("should return the series of a pool", func() {
+		// Act
+		points, err := snapshot.Series(s.ctx, store, snapshot.DLMM, "gone", time.Time{}, time.Time{})
+
+		// Assert
+		s.Require().NoError(err)
+		s.Equal([]snapshot.Point{{Time: t0, Pool: pool("gone", 5)}}, points)
+	})
+
+	s.Run("should find the snapshot in effect at a time", func() {
+		// Act
+		before, errBefore := snapshot.At(s.ctx, store, snapshot.DLMM, t0.Add(-time.Hour))
+		mid, errMid := snapshot.At(s.ctx, store, snapshot.DLMM, t0.Add(12*time.Hour))
+		latest, errLatest := snapshot.At(s.ctx, store, snapshot.DLMM, time.Time{})
+
+		// Assert
+		s.Nil(before)
+		s.ErrorIs(errBefore, snapshot.ErrNoSnapshot)
+		s.Require().NoError(errMid)
+		s.Equal(first, mid)
+		s.Require().NoError(errLatest)
+		s.Equal(second, latest)
+	})
+
+	s.Run("should rank movers by absolute change", func() {
+		// Act
+		movers := snapshot.TopMovers(first, second, snapshot.MetricTVL, 1)
+
+		// Assert
+		s.Equal([]snapshot.Mover{{Address: "b", Name: "b-USDC", Before: 100, After: 20, Change: -80, ChangePct: -80}}, movers)
+		s.Len(snapshot.TopMovers(first, second, snapshot.MetricTVL, 0), 2)
+	})
+
+	s.Run("should list pools that disappeared and appeared", func() {
+		// Act
+		gone := snapshot.Disappeared(first, second)
+		added := snapshot.Appeared(first, second)
+
+		// Assert
+		s.Equal([]snapshot.PoolMetrics{pool("gone", 5)}, gone)
+		s.Equal([]snapshot.PoolMetrics{pool("new", 7)}, added)
+	})
+}
@@ -0,0 +1,90 @@
+package snapshot
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store persists snapshots. Implementations must be safe for concurrent use.
+type Store interface {
+	// Save stores a snapshot.
+	Save(ctx context.Context, snap *Snapshot) error
+
+	// Snapshots returns the snapshots of service taken between from and to
+	// inclusive, oldest first. A zero from or to leaves that end unbounded.
+	Snapshots(ctx context.Context, service Service, from, to time.Time) ([]*Snapshot, error)
+
+	// Times returns the times of the snapshots of service, oldest first.
+	Times(ctx context.Context, service Service) ([]time.Time, error)
+}
+
+// MemoryStore keeps snapshots in memory. It is useful for tests and for
+// processes that only compare snapshots they captured themselves.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	snapshots map[Service][]*Snapshot
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snapshots: map[Service][]*Snapshot{}}
+}
+
+// Save stores snap. The snapshot must not be modified afterwards.
+func (m *MemoryStore) Save(ctx context.Context, snap *Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots[snap.Service] = insertSorted(m.snapshots[snap.Service], snap, func(s *Snapshot) time.Time { return s.Time })
+	return nil
+}
+
+// Snapshots returns the stored snapshots of service between from and to.
+func (m *MemoryStore) Snapshots(ctx context.Context, service Service, from, to time.Time) ([]*Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	all := m.snapshots[service]
+	lo, hi := timeRange(len(all), func(i int) time.Time { return all[i].Time }, from, to)
+	return append([]*Snapshot(nil), all[lo:hi]...), nil
+}
+
+// Times returns the times of the stored snapshots of service.
+func (m *MemoryStore) Times(ctx context.Context, service Service) ([]time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	times := make([]time.Time, len(m.snapshots[service]))
+	for i, snap := range m.snapshots[service] {
+		times[i] = snap.Time
+	}
+	return times, nil
+}
+
+// insertSorted inserts v after the elements of s whose time is not after its
+// own, keeping s ordered by time.
+func insertSorted[T any](s []T, v T, at func(T) time.Time) []T {
+	t := at(v)
+	i := sort.Search(len(s), func(i int) bool { return at(s[i]).After(t) })
+	s = append(s, v)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+// timeRange returns the bounds of the elements of a time-ordered sequence of
+// length n that fall between from and to inclusive.
+func timeRange(n int, at func(int) time.Time, from, to time.Time) (int, int) {
+	lo, hi := 0, n
+	if !from.IsZero() {
+		lo = sort.Search(n, func(i int) bool { return !at(i).Before(from) })
+	}
+	if !to.IsZero() {
+		hi = sort.Search(n, func(i int) bool { return at(i).After(to) })
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
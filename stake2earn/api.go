@@ -0,0 +1,26 @@
+package stake2earn
+
+import "context"
+
+// API is the set of Stake2Earn requests implemented by Client. Code that depends
+// on API instead of *Client can substitute a fake, such as the ones in the
+// meteoratest package.
+type API interface {
+	// GetAnalytics returns protocol-wide Stake2Earn analytics.
+	GetAnalytics(ctx context.Context) (*Analytics, error)
+
+	// ListVaults returns all Stake2Earn vaults.
+	ListVaults(ctx context.Context) (*VaultListResponse, error)
+
+	// FilterVaults returns filtered and paginated vaults.
+	FilterVaults(ctx context.Context, params *FilterParams) (*VaultListResponse, error)
+
+	// GetVault returns a single vault by address.
+	GetVault(ctx context.Context, address string) (*Vault, error)
+
+	// GetVaultsByPool looks up the vaults of multiple pools using the pool address
+	// filter of FilterVaults, sending the addresses in chunks of at most 100.
+	GetVaultsByPool(ctx context.Context, poolAddresses []string, opts *BatchOptions) []VaultResult
+}
+
+var _ API = (*Client)(nil)
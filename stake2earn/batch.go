@@ -0,0 +1,112 @@
+package stake2earn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ua1984/meteora-go/internal/batch"
+)
+
+// ErrVaultNotFound is reported by GetVaultsByPool for pool addresses without a vault.
+var ErrVaultNotFound = errors.New("vault not found")
+
+// BatchOptions are optional parameters for batch methods such as GetVaultsByPool.
+type BatchOptions struct {
+	// Concurrency is the maximum number of requests in flight. Default: 8.
+	// Requests are additionally throttled by the client's rate limit, if set.
+	Concurrency int
+
+	// ChunkSize is the maximum number of pool addresses sent per request.
+	// Default: the client's chunk size (see Client.SetChunkSize). Maximum: 100.
+	ChunkSize int
+}
+
+// VaultResult is the outcome of looking up the vault of a single pool in a batch.
+type VaultResult struct {
+	// PoolAddress is the requested pool address.
+	PoolAddress string
+
+	// Vault is the vault of the pool, or nil if Err is set.
+	Vault *Vault
+
+	// Err is the error returned for this pool address, if any.
+	Err error
+}
+
+// GetVaultsByPool looks up the vaults of multiple pools using the pool address
+// filter of FilterVaults, sending the addresses in chunks of at most 100. It
+// returns one result per unique pool address, in order of first appearance.
+// Pools without a vault are reported with ErrVaultNotFound. Like FilterVaults,
+// it expects each response to hold every vault of its chunk; a response with
+// fewer vaults than its Total fails its chunk. Unlike FilterVaults, a failed
+// chunk does not fail the batch; its error is reported on each of its
+// addresses.
+func (c *Client) GetVaultsByPool(ctx context.Context, poolAddresses []string, opts *BatchOptions) []VaultResult {
+	unique := batch.Unique(poolAddresses)
+	size := opts.chunkSize()
+	if size <= 0 {
+		size = c.chunkSize
+	}
+	chunks := batch.Chunk(unique, size)
+
+	resps := make([]*VaultListResponse, len(chunks))
+	errs := make([]error, len(chunks))
+	batch.Run(ctx, len(chunks), opts.concurrency(), func(ctx context.Context, i int) {
+		resps[i], errs[i] = c.FilterVaults(ctx, &FilterParams{PoolAddresses: chunks[i]})
+		if errs[i] == nil {
+			if err := resps[i].complete(); err != nil {
+				errs[i] = fmt.Errorf("stake2earn.GetVaultsByPool: %w", err)
+			}
+		}
+	})
+
+	byPool := map[string]*Vault{}
+	chunkErr := map[string]error{}
+	for i, chunk := range chunks {
+		if errs[i] != nil {
+			for _, addr := range chunk {
+				chunkErr[addr] = errs[i]
+			}
+			continue
+		}
+		for j := range resps[i].Data {
+			byPool[resps[i].Data[j].PoolAddress] = &resps[i].Data[j]
+		}
+	}
+
+	results := make([]VaultResult, len(unique))
+	for i, addr := range unique {
+		results[i] = VaultResult{PoolAddress: addr, Vault: byPool[addr], Err: chunkErr[addr]}
+		if results[i].Vault == nil && results[i].Err == nil {
+			results[i].Err = fmt.Errorf("stake2earn.GetVaultsByPool: %s: %w", addr, ErrVaultNotFound)
+		}
+	}
+
+	return results
+}
+
+// complete returns an error if r holds fewer vaults than its Total.
+func (r *VaultListResponse) complete() error {
+	if len(r.Data) < r.Total {
+		return fmt.Errorf("response holds %d of %d vaults", len(r.Data), r.Total)
+	}
+	return nil
+}
+
+func (o *BatchOptions) concurrency() int {
+	if o == nil {
+		return 0
+	}
+	return o.Concurrency
+}
+
+func (o *BatchOptions) chunkSize() int {
+	if o == nil || o.ChunkSize <= 0 {
+		return 0
+	}
+	if o.ChunkSize > batch.DefaultChunkSize {
+		return batch.DefaultChunkSize
+	}
+	return o.ChunkSize
+}
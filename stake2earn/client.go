@@ -5,17 +5,30 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/ua1984/meteora-go/internal/batch"
 	"github.com/ua1984/meteora-go/internal/httpclient"
 )
 
 // Client provides access to the Stake2Earn API.
 type Client struct {
-	http *httpclient.Client
+	http      *httpclient.Client
+	chunkSize int
 }
 
 // NewClient creates a new Stake2Earn client.
 func NewClient(http *httpclient.Client) *Client {
-	return &Client{http: http}
+	return &Client{http: http, chunkSize: batch.DefaultChunkSize}
+}
+
+// SetChunkSize sets the maximum number of pool addresses sent per FilterVaults
+// request. Longer lists are split into several concurrent requests whose
+// responses are merged. Sizes of zero or less, or above the API limit of 100,
+// restore the default of 100.
+func (c *Client) SetChunkSize(size int) {
+	if size <= 0 || size > batch.DefaultChunkSize {
+		size = batch.DefaultChunkSize
+	}
+	c.chunkSize = size
 }
 
 // GetAnalytics returns protocol-wide Stake2Earn analytics.
@@ -38,8 +51,51 @@ func (c *Client) ListVaults(ctx context.Context) (*VaultListResponse, error) {
 	return &resp, nil
 }
 
-// FilterVaults returns filtered and paginated vaults.
+// FilterVaults returns filtered vaults. The endpoint takes no page parameter
+// and is expected to return every matching vault in one response.
+//
+// PoolAddresses lists longer than the client's chunk size are fetched in
+// several requests and merged without duplicate vaults; Total is adjusted for
+// the duplicates removed. A chunk response holding fewer vaults than its Total
+// is an error, as the missing vaults cannot be requested.
 func (c *Client) FilterVaults(ctx context.Context, params *FilterParams) (*VaultListResponse, error) {
+	if params == nil || len(params.PoolAddresses) <= c.chunkSize {
+		return c.filterVaults(ctx, params)
+	}
+
+	chunks := batch.Chunk(batch.Unique(params.PoolAddresses), c.chunkSize)
+	results, err := batch.Map(ctx, len(chunks), batch.DefaultConcurrency, func(ctx context.Context, i int) (*VaultListResponse, error) {
+		resp, err := c.filterVaults(ctx, &FilterParams{PoolAddresses: chunks[i]})
+		if err != nil {
+			return nil, err
+		}
+		if err := resp.complete(); err != nil {
+			return nil, fmt.Errorf("stake2earn.FilterVaults: %w", err)
+		}
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &VaultListResponse{}
+	seen := map[string]bool{}
+	for _, r := range results {
+		merged.Total += r.Total
+		for _, v := range r.Data {
+			if seen[v.VaultAddress] {
+				merged.Total--
+				continue
+			}
+			seen[v.VaultAddress] = true
+			merged.Data = append(merged.Data, v)
+		}
+	}
+
+	return merged, nil
+}
+
+func (c *Client) filterVaults(ctx context.Context, params *FilterParams) (*VaultListResponse, error) {
 	var q url.Values
 	if params != nil {
 		q = url.Values{}
@@ -3,8 +3,10 @@ package stake2earn_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -232,3 +234,131 @@ func (s *Stake2EarnClientTestSuite) TestGetVault() {
 		})
 	}
 }
+
+func (s *Stake2EarnClientTestSuite) TestGetVaultsByPool() {
+	// Arrange
+	var mu sync.Mutex
+	var chunkSizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("/vault/filter", r.URL.Path)
+		pools := r.URL.Query()["pool_address"]
+		mu.Lock()
+		chunkSizes = append(chunkSizes, len(pools))
+		mu.Unlock()
+		resp := stake2earn.VaultListResponse{}
+		for _, p := range pools {
+			if p != "pool-without-vault" {
+				resp.Data = append(resp.Data, stake2earn.Vault{VaultAddress: "vault-" + p, PoolAddress: p})
+			}
+		}
+		resp.Total = len(resp.Data)
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := stake2earn.NewClient(httpclient.New(server.URL, nil))
+
+	pools := []string{"pool-without-vault"}
+	for i := 0; i < 150; i++ {
+		pools = append(pools, fmt.Sprintf("pool%d", i))
+	}
+
+	// Act
+	results := client.GetVaultsByPool(context.TODO(), pools, nil)
+
+	// Assert
+	s.ElementsMatch([]int{100, 51}, chunkSizes)
+	s.Require().Len(results, 151)
+	s.ErrorIs(results[0].Err, stake2earn.ErrVaultNotFound)
+	s.Equal("vault-pool0", results[1].Vault.VaultAddress)
+	s.Equal("pool149", results[150].PoolAddress)
+	s.NoError(results[150].Err)
+}
+
+// setupIncompleteServer returns a server answering vault filters with a vault
+// per pool address, except that the response for a chunk starting with pool0
+// reports one vault more than it holds.
+func (s *Stake2EarnClientTestSuite) setupIncompleteServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pools := r.URL.Query()["pool_address"]
+		resp := stake2earn.VaultListResponse{}
+		for _, p := range pools {
+			resp.Data = append(resp.Data, stake2earn.Vault{VaultAddress: "vault-" + p, PoolAddress: p})
+		}
+		resp.Total = len(resp.Data)
+		if pools[0] == "pool0" {
+			resp.Total++
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func (s *Stake2EarnClientTestSuite) TestGetVaultsByPoolIncompleteResponse() {
+	// Arrange
+	server := s.setupIncompleteServer()
+	defer server.Close()
+	client := stake2earn.NewClient(httpclient.New(server.URL, nil))
+
+	// Act
+	results := client.GetVaultsByPool(context.TODO(), []string{"pool0", "pool1", "pool2"}, &stake2earn.BatchOptions{ChunkSize: 2})
+
+	// Assert
+	s.Require().Len(results, 3)
+	s.EqualError(results[0].Err, "stake2earn.GetVaultsByPool: response holds 2 of 3 vaults")
+	s.EqualError(results[1].Err, "stake2earn.GetVaultsByPool: response holds 2 of 3 vaults")
+	s.Nil(results[1].Vault)
+	s.NoError(results[2].Err)
+	s.Equal("vault-pool2", results[2].Vault.VaultAddress)
+}
+
+func (s *Stake2EarnClientTestSuite) TestFilterVaultsChunked() {
+	// Arrange
+	var mu sync.Mutex
+	var chunkSizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pools := r.URL.Query()["pool_address"]
+		mu.Lock()
+		chunkSizes = append(chunkSizes, len(pools))
+		mu.Unlock()
+		// Every chunk also returns the same shared vault.
+		resp := stake2earn.VaultListResponse{Data: []stake2earn.Vault{{VaultAddress: "shared"}}}
+		for _, p := range pools {
+			resp.Data = append(resp.Data, stake2earn.Vault{VaultAddress: "vault-" + p, PoolAddress: p})
+		}
+		resp.Total = len(resp.Data)
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := stake2earn.NewClient(httpclient.New(server.URL, nil))
+	client.SetChunkSize(40)
+
+	var pools []string
+	for i := 0; i < 100; i++ {
+		pools = append(pools, fmt.Sprintf("pool%d", i))
+	}
+
+	// Act
+	resp, err := client.FilterVaults(context.TODO(), &stake2earn.FilterParams{PoolAddresses: pools})
+
+	// Assert
+	s.NoError(err)
+	s.ElementsMatch([]int{40, 40, 20}, chunkSizes)
+	s.Len(resp.Data, 101)
+	s.Equal(101, resp.Total)
+	s.Equal("shared", resp.Data[0].VaultAddress)
+	s.Equal("vault-pool0", resp.Data[1].VaultAddress)
+}
+
+func (s *Stake2EarnClientTestSuite) TestFilterVaultsChunkedIncompleteResponse() {
+	// Arrange
+	server := s.setupIncompleteServer()
+	defer server.Close()
+	client := stake2earn.NewClient(httpclient.New(server.URL, nil))
+	client.SetChunkSize(2)
+
+	// Act
+	resp, err := client.FilterVaults(context.TODO(), &stake2earn.FilterParams{PoolAddresses: []string{"pool0", "pool1", "pool2"}})
+
+	// Assert
+	s.EqualError(err, "stake2earn.FilterVaults: response holds 2 of 3 vaults")
+	s.Nil(resp)
+}
@@ -2,6 +2,7 @@ package stake2earn
 
 // FilterParams are optional query parameters for the FilterVaults method.
 type FilterParams struct {
-	// PoolAddresses filters vaults by pool address. Maximum of 100 addresses.
+	// PoolAddresses filters vaults by pool address. The API accepts at most 100
+	// addresses per request; longer lists are split automatically.
 	PoolAddresses []string
 }
@@ -0,0 +1,199 @@
+package stake2earn
+
+import (
+	"sort"
+	"time"
+)
+
+const daysPerYear = 365
+
+// APR returns the staking APR of the vault in percent: DailyRewardUSD over a
+// year relative to TotalStakedAmountUSD. It returns 0 for a vault with nothing
+// staked.
+func (v *Vault) APR() float64 {
+	return aprFor(v.DailyRewardUSD, v.TotalStakedAmountUSD)
+}
+
+func aprFor(dailyRewardUSD, stakedUSD float64) float64 {
+	if stakedUSD <= 0 {
+		return 0
+	}
+	return dailyRewardUSD * daysPerYear / stakedUSD * 100
+}
+
+// UnlockPoint is one step of a reward unlock schedule.
+type UnlockPoint struct {
+	// Time is the end of the step.
+	Time time.Time `json:"time"`
+
+	// UnlockedUSD is the reward in USD unlocked during the step.
+	UnlockedUSD float64 `json:"unlocked_usd"`
+
+	// CumulativeUSD is the reward in USD unlocked from the start of the
+	// schedule up to Time.
+	CumulativeUSD float64 `json:"cumulative_usd"`
+}
+
+// UnlockSchedule projects how the vault's pending reward (CurrentRewardUSD)
+// unlocks to stakers, in steps of step starting at now. The reward unlocks
+// linearly over unlockIn, counted from now or from StartFeeDistributeTimestamp
+// if that is later; nothing unlocks before. The API does not report how long
+// pending fees take to unlock (SecondsToFullUnlock is about withdrawing
+// stake), so unlockIn is supplied by the caller. The projection covers only
+// the reward already pending; fees collected later are not included.
+//
+// A vault without pending reward, or an unlockIn of zero or less, has an
+// empty schedule. A step of zero or less is treated as one day.
+func (v *Vault) UnlockSchedule(now time.Time, unlockIn, step time.Duration) []UnlockPoint {
+	if v.CurrentRewardUSD <= 0 || unlockIn <= 0 {
+		return nil
+	}
+	if step <= 0 {
+		step = 24 * time.Hour
+	}
+
+	start := now
+	if distribute := time.Unix(v.StartFeeDistributeTimestamp, 0); distribute.After(start) {
+		start = distribute
+	}
+	end := start.Add(unlockIn)
+	rate := v.CurrentRewardUSD / unlockIn.Seconds()
+
+	var points []UnlockPoint
+	var cumulative float64
+	for t := now; t.Before(end); {
+		next := t.Add(step)
+		if next.After(end) {
+			next = end
+		}
+		var unlocked float64
+		if next.After(start) {
+			from := t
+			if from.Before(start) {
+				from = start
+			}
+			unlocked = next.Sub(from).Seconds() * rate
+		}
+		cumulative += unlocked
+		points = append(points, UnlockPoint{Time: next, UnlockedUSD: unlocked, CumulativeUSD: cumulative})
+		t = next
+	}
+	return points
+}
+
+// StakeEstimate is the expected reward of a hypothetical new stake.
+type StakeEstimate struct {
+	// StakeUSD is the value of the new stake in USD.
+	StakeUSD float64 `json:"stake_usd"`
+
+	// Share is the new stake's percentage of the vault after it is added.
+	Share float64 `json:"share"`
+
+	// DailyRewardUSD is the expected daily reward of the new stake in USD.
+	DailyRewardUSD float64 `json:"daily_reward_usd"`
+
+	// APRBefore is the vault APR before the new stake, in percent.
+	APRBefore float64 `json:"apr_before"`
+
+	// APRAfter is the vault APR after the new stake, in percent. Every staker,
+	// including the new one, earns this APR.
+	APRAfter float64 `json:"apr_after"`
+
+	// Dilution is APRBefore minus APRAfter, in percentage points.
+	Dilution float64 `json:"dilution"`
+}
+
+// EstimateStake estimates the daily reward of adding stakeUSD to the vault.
+// The daily reward is assumed to stay at DailyRewardUSD and to be shared pro
+// rata, so the new stake dilutes the APR of every staker.
+func (v *Vault) EstimateStake(stakeUSD float64) StakeEstimate {
+	e := StakeEstimate{StakeUSD: stakeUSD, APRBefore: v.APR()}
+	total := v.TotalStakedAmountUSD + stakeUSD
+	if total > 0 {
+		e.Share = stakeUSD / total * 100
+		e.DailyRewardUSD = v.DailyRewardUSD * stakeUSD / total
+	}
+	e.APRAfter = aprFor(v.DailyRewardUSD, total)
+	e.Dilution = e.APRBefore - e.APRAfter
+	return e
+}
+
+// RankOptions are optional parameters for RankVaults.
+type RankOptions struct {
+	// MarketCapReference is the market cap in USD at which the market cap
+	// factor is 0.5. Default: 10,000,000.
+	MarketCapReference float64
+
+	// ThresholdPenalty is the fraction of the score removed for vaults that
+	// have not reached their TVL threshold, between 0 and 1. Zero disables
+	// the penalty. Default (nil): 0.5.
+	ThresholdPenalty *float64
+
+	// StakeUSD is the hypothetical stake size used to compute APRs after
+	// dilution. Default: 0, ranking by the current APR.
+	StakeUSD float64
+}
+
+// VaultRank is a vault with its risk-adjusted score.
+type VaultRank struct {
+	// Vault is the ranked vault.
+	Vault Vault `json:"vault"`
+
+	// APR is the vault APR in percent, after diluting by RankOptions.StakeUSD.
+	APR float64 `json:"apr"`
+
+	// MarketCapFactor is MarketCap / (MarketCap + MarketCapReference). It
+	// tends to 1 for large tokens and is 0 for vaults without a market cap.
+	MarketCapFactor float64 `json:"marketcap_factor"`
+
+	// ThresholdFactor is 1 for vaults that reached their TVL threshold and
+	// 1 - ThresholdPenalty otherwise.
+	ThresholdFactor float64 `json:"threshold_factor"`
+
+	// Score is APR × MarketCapFactor × ThresholdFactor.
+	Score float64 `json:"score"`
+}
+
+// RankVaults ranks vaults by risk-adjusted yield, highest score first. The
+// APR is discounted for tokens with a small market cap, whose fees and price
+// are less dependable, and for vaults that have not reached their TVL
+// threshold. opts may be nil.
+func RankVaults(vaults []Vault, opts *RankOptions) []VaultRank {
+	reference, penalty, stake := opts.marketCapReference(), opts.thresholdPenalty(), opts.stakeUSD()
+
+	ranks := make([]VaultRank, len(vaults))
+	for i, v := range vaults {
+		r := VaultRank{Vault: v, APR: v.EstimateStake(stake).APRAfter, ThresholdFactor: 1}
+		if v.MarketCap > 0 {
+			r.MarketCapFactor = v.MarketCap / (v.MarketCap + reference)
+		}
+		if !v.Flags.TVLUSDThresholdReached {
+			r.ThresholdFactor = 1 - penalty
+		}
+		r.Score = r.APR * r.MarketCapFactor * r.ThresholdFactor
+		ranks[i] = r
+	}
+	sort.SliceStable(ranks, func(i, j int) bool { return ranks[i].Score > ranks[j].Score })
+	return ranks
+}
+
+func (o *RankOptions) marketCapReference() float64 {
+	if o == nil || o.MarketCapReference <= 0 {
+		return 10_000_000
+	}
+	return o.MarketCapReference
+}
+
+func (o *RankOptions) thresholdPenalty() float64 {
+	if o == nil || o.ThresholdPenalty == nil {
+		return 0.5
+	}
+	return min(max(*o.ThresholdPenalty, 0), 1)
+}
+
+func (o *RankOptions) stakeUSD() float64 {
+	if o == nil {
+		return 0
+	}
+	return o.StakeUSD
+}
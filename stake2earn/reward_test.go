@@ -0,0 +1,172 @@
+package stake2earn_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+type RewardTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestReward(t *testing.T) {
+	suite.Run(t, new(RewardTestSuite))
+}
+
+func (s *RewardTestSuite) SetupTest() {
+	s.now = time.Unix(1_700_000_000, 0)
+}
+
+func (s *RewardTestSuite) TestAPR() {
+	tests := []struct {
+		name  string
+		vault stake2earn.Vault
+		want  float64
+	}{
+		{
+			name:  "should annualize the daily reward",
+			vault: stake2earn.Vault{DailyRewardUSD: 10, TotalStakedAmountUSD: 36_500},
+			want:  10,
+		},
+		{
+			name:  "should return zero without stake",
+			vault: stake2earn.Vault{DailyRewardUSD: 10},
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			got := tt.vault.APR()
+
+			// Assert
+			s.InDelta(tt.want, got, 1e-9)
+		})
+	}
+}
+
+func (s *RewardTestSuite) TestUnlockSchedule() {
+	tests := []struct {
+		name     string
+		vault    stake2earn.Vault
+		unlockIn time.Duration
+		step     time.Duration
+		want     []float64
+	}{
+		{
+			name:     "should unlock linearly",
+			vault:    stake2earn.Vault{CurrentRewardUSD: 30},
+			unlockIn: 3 * 24 * time.Hour,
+			want:     []float64{10, 10, 10},
+		},
+		{
+			name:     "should end with a partial step",
+			vault:    stake2earn.Vault{CurrentRewardUSD: 30},
+			unlockIn: 3 * 24 * time.Hour,
+			step:     2 * 24 * time.Hour,
+			want:     []float64{20, 10},
+		},
+		{
+			name: "should wait for fee distribution to start",
+			vault: stake2earn.Vault{
+				CurrentRewardUSD:            20,
+				StartFeeDistributeTimestamp: s.now.Unix() + 86400/2,
+			},
+			unlockIn: 2 * 24 * time.Hour,
+			want:     []float64{5, 10, 5},
+		},
+		{
+			name:  "should not read the unlock period from SecondsToFullUnlock",
+			vault: stake2earn.Vault{CurrentRewardUSD: 30, SecondsToFullUnlock: 3 * 86400},
+		},
+		{
+			name:     "should be empty without pending reward",
+			vault:    stake2earn.Vault{SecondsToFullUnlock: 3 * 86400},
+			unlockIn: 3 * 24 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			points := tt.vault.UnlockSchedule(s.now, tt.unlockIn, tt.step)
+
+			// Assert
+			s.Require().Len(points, len(tt.want))
+			var cumulative float64
+			for i, p := range points {
+				cumulative += tt.want[i]
+				s.InDelta(tt.want[i], p.UnlockedUSD, 1e-9)
+				s.InDelta(cumulative, p.CumulativeUSD, 1e-9)
+			}
+		})
+	}
+}
+
+func (s *RewardTestSuite) TestEstimateStake() {
+	// Arrange
+	vault := stake2earn.Vault{DailyRewardUSD: 10, TotalStakedAmountUSD: 36_500}
+
+	// Act
+	e := vault.EstimateStake(36_500)
+
+	// Assert
+	s.InDelta(50, e.Share, 1e-9)
+	s.InDelta(5, e.DailyRewardUSD, 1e-9)
+	s.InDelta(10, e.APRBefore, 1e-9)
+	s.InDelta(5, e.APRAfter, 1e-9)
+	s.InDelta(5, e.Dilution, 1e-9)
+}
+
+func (s *RewardTestSuite) TestRankVaults() {
+	// Arrange
+	vaults := []stake2earn.Vault{
+		{VaultAddress: "small-cap", DailyRewardUSD: 100, TotalStakedAmountUSD: 36_500, MarketCap: 1_000_000, Flags: stake2earn.VaultFlags{TVLUSDThresholdReached: true}},
+		{VaultAddress: "large-cap", DailyRewardUSD: 30, TotalStakedAmountUSD: 36_500, MarketCap: 90_000_000, Flags: stake2earn.VaultFlags{TVLUSDThresholdReached: true}},
+		{VaultAddress: "below-threshold", DailyRewardUSD: 40, TotalStakedAmountUSD: 36_500, MarketCap: 90_000_000},
+		{VaultAddress: "no-cap", DailyRewardUSD: 1000, TotalStakedAmountUSD: 36_500},
+	}
+
+	s.Run("should rank by risk-adjusted yield", func() {
+		// Act
+		ranks := stake2earn.RankVaults(vaults, nil)
+
+		// Assert
+		s.Require().Len(ranks, 4)
+		s.Equal("large-cap", ranks[0].Vault.VaultAddress)
+		s.InDelta(27, ranks[0].Score, 1e-9)
+		s.Equal("below-threshold", ranks[1].Vault.VaultAddress)
+		s.InDelta(0.5, ranks[1].ThresholdFactor, 1e-9)
+		s.Equal("small-cap", ranks[2].Vault.VaultAddress)
+		s.Equal("no-cap", ranks[3].Vault.VaultAddress)
+		s.Zero(ranks[3].Score)
+	})
+
+	s.Run("should dilute by the stake size", func() {
+		// Act
+		ranks := stake2earn.RankVaults(vaults, &stake2earn.RankOptions{StakeUSD: 36_500, ThresholdPenalty: ptr(1.0)})
+
+		// Assert
+		s.InDelta(15, ranks[0].APR, 1e-9)
+		s.Zero(ranks[3].Score)
+	})
+
+	s.Run("should disable the penalty with an explicit zero", func() {
+		// Act
+		ranks := stake2earn.RankVaults(vaults, &stake2earn.RankOptions{ThresholdPenalty: ptr(0.0)})
+
+		// Assert
+		s.Equal("below-threshold", ranks[0].Vault.VaultAddress)
+		s.InDelta(1, ranks[0].ThresholdFactor, 1e-9)
+		s.InDelta(36, ranks[0].Score, 1e-9)
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,127 @@
+package meteora
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+// StakeVaultOptions are optional parameters for EnrichStakeVaults.
+type StakeVaultOptions struct {
+	// Batch configures the batched DAMM v1 pool requests.
+	Batch *dammv1.BatchOptions
+}
+
+// StakeVault is a Stake2Earn vault joined with the DAMM v1 pool it collects
+// fees from.
+type StakeVault struct {
+	// Vault is the Stake2Earn vault.
+	Vault stake2earn.Vault `json:"vault"`
+
+	// Pool is the vault's DAMM v1 pool, or nil if Err is set.
+	Pool *dammv1.Pool `json:"pool"`
+
+	// PoolTVL is the pool's TVL in USD.
+	PoolTVL float64 `json:"pool_tvl"`
+
+	// PoolVolume24h is the pool's trading volume of the last 24 hours in USD.
+	PoolVolume24h float64 `json:"pool_volume_24h"`
+
+	// PoolFees24h is the pool's fees of the last 24 hours in USD.
+	PoolFees24h float64 `json:"pool_fees_24h"`
+
+	// ImpliedStakerShare is the vault's DailyRewardUSD divided by the pool's
+	// 24h fees, capped at 1: the share of pool fees the vault reports passing
+	// to stakers. It is zero when the pool had no fees. It is reported for
+	// comparison only; the fee flow uses StakerShare.
+	ImpliedStakerShare float64 `json:"implied_staker_share"`
+
+	// StakerShare is the share of pool fees used for the fee flow, as passed
+	// to EnrichStakeVaults.
+	StakerShare float64 `json:"staker_share"`
+
+	// FeeFlowPerStakedUSD is the pool's 24h fees times StakerShare divided by
+	// the vault's staked USD: the USD earned per staked dollar per day. It is
+	// zero for a vault with nothing staked.
+	FeeFlowPerStakedUSD float64 `json:"fee_flow_per_staked_usd"`
+
+	// WeeklyFeeFlowPerStakedUSD is FeeFlowPerStakedUSD computed from the
+	// average daily fees of the last 7 days, which smooths out a single busy
+	// or quiet day.
+	WeeklyFeeFlowPerStakedUSD float64 `json:"weekly_fee_flow_per_staked_usd"`
+
+	// FeeFlowAPR is FeeFlowPerStakedUSD annualized, in percent.
+	FeeFlowAPR float64 `json:"fee_flow_apr"`
+
+	// Err is the error returned for the vault's pool, if any. Pools the API
+	// did not return are reported with dammv1.ErrPoolNotFound.
+	Err error `json:"-"`
+}
+
+// EnrichStakeVaults joins each vault with its DAMM v1 pool and computes the
+// fee flow per staked dollar from stakerShare, the fraction of the pool's
+// fees distributed to stakers. The API does not report the fee split of a
+// vault, so stakerShare must be given and must be in (0, 1]. Deriving it from
+// the vault's DailyRewardUSD would make the fee flow a restatement of that
+// reward.
+//
+// The pools are fetched with DAMMv1.GetPools in batches, so a pool that fails
+// only fails its own vaults. It returns one record per vault, in the order of
+// vaults, and an error only for an invalid stakerShare. opts may be nil.
+func (c *Client) EnrichStakeVaults(ctx context.Context, vaults []stake2earn.Vault, stakerShare float64, opts *StakeVaultOptions) ([]StakeVault, error) {
+	if !(stakerShare > 0 && stakerShare <= 1) {
+		return nil, fmt.Errorf("meteora.EnrichStakeVaults: staker share %v not in (0, 1]", stakerShare)
+	}
+	if opts == nil {
+		opts = &StakeVaultOptions{}
+	}
+
+	addresses := make([]string, len(vaults))
+	for i, v := range vaults {
+		addresses[i] = v.PoolAddress
+	}
+	pools := map[string]dammv1.PoolResult{}
+	for _, r := range c.DAMMv1.GetPools(ctx, addresses, opts.Batch) {
+		pools[r.Address] = r
+	}
+
+	out := make([]StakeVault, len(vaults))
+	for i, v := range vaults {
+		r := pools[v.PoolAddress]
+		out[i] = StakeVault{Vault: v, Pool: r.Pool}
+		switch {
+		case r.Err != nil:
+			out[i].Err = fmt.Errorf("meteora.EnrichStakeVaults: %s: %w", v.VaultAddress, r.Err)
+			continue
+		case r.Pool == nil:
+			// GetPools skips empty addresses, so a vault without a pool has no
+			// result at all.
+			out[i].Err = fmt.Errorf("meteora.EnrichStakeVaults: %s: %w", v.VaultAddress, dammv1.ErrPoolNotFound)
+			continue
+		}
+		out[i].joinPool(stakerShare)
+	}
+	return out, nil
+}
+
+func (s *StakeVault) joinPool(stakerShare float64) {
+	p := s.Pool
+	s.PoolTVL, _ = strconv.ParseFloat(p.PoolTVL, 64)
+	s.PoolVolume24h = p.TradingVolume
+	s.PoolFees24h = p.FeeVolume
+	if p.FeeVolume > 0 {
+		s.ImpliedStakerShare = min(s.Vault.DailyRewardUSD/p.FeeVolume, 1)
+	}
+
+	s.StakerShare = stakerShare
+	staked := s.Vault.TotalStakedAmountUSD
+	if staked <= 0 {
+		return
+	}
+	s.FeeFlowPerStakedUSD = p.FeeVolume * s.StakerShare / staked
+	s.WeeklyFeeFlowPerStakedUSD = p.WeeklyFeeVolume / 7 * s.StakerShare / staked
+	s.FeeFlowAPR = s.FeeFlowPerStakedUSD * 365 * 100
+}
@@ -0,0 +1,129 @@
+package meteora_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	meteora "github.com/ua1984/meteora-go"
+	"github.com/ua1984/meteora-go/dammv1"
+	"github.com/ua1984/meteora-go/meteoratest"
+	"github.com/ua1984/meteora-go/stake2earn"
+)
+
+type StakeVaultTestSuite struct {
+	suite.Suite
+	srv    *meteoratest.Server
+	client *meteora.Client
+	vaults []stake2earn.Vault
+}
+
+func TestStakeVault(t *testing.T) {
+	suite.Run(t, new(StakeVaultTestSuite))
+}
+
+func (s *StakeVaultTestSuite) SetupTest() {
+	s.srv = meteoratest.NewServer()
+	s.srv.Seed(func(d *meteoratest.Data) {
+		d.DAMMv1.Pools = []dammv1.Pool{
+			{PoolAddress: "pool1", PoolTVL: "50000", TradingVolume: 20_000, FeeVolume: 100, WeeklyFeeVolume: 1400},
+			{PoolAddress: "pool2", PoolTVL: "1000"},
+		}
+	})
+	s.client = s.srv.Client()
+	s.vaults = []stake2earn.Vault{
+		{VaultAddress: "vault1", PoolAddress: "pool1", TotalStakedAmountUSD: 1000, DailyRewardUSD: 20},
+		{VaultAddress: "vault2", PoolAddress: "pool2", TotalStakedAmountUSD: 1000, DailyRewardUSD: 5},
+		{VaultAddress: "vault3", PoolAddress: "missing"},
+	}
+}
+
+func (s *StakeVaultTestSuite) TearDownTest() {
+	s.srv.Close()
+}
+
+func (s *StakeVaultTestSuite) TestEnrichStakeVaults() {
+	// Act
+	out, err := s.client.EnrichStakeVaults(context.Background(), s.vaults, 0.5, nil)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(out, 3)
+	v := out[0]
+	s.Require().NoError(v.Err)
+	s.Equal("pool1", v.Pool.PoolAddress)
+	s.Equal(50_000.0, v.PoolTVL)
+	s.Equal(20_000.0, v.PoolVolume24h)
+	s.InDelta(0.2, v.ImpliedStakerShare, 1e-9)
+	s.InDelta(0.5, v.StakerShare, 1e-9)
+	s.InDelta(0.05, v.FeeFlowPerStakedUSD, 1e-9)
+	s.InDelta(0.1, v.WeeklyFeeFlowPerStakedUSD, 1e-9)
+	s.InDelta(1825, v.FeeFlowAPR, 1e-9)
+}
+
+func (s *StakeVaultTestSuite) TestFeeFlowDiffersFromReportedReward() {
+	// Act
+	out, err := s.client.EnrichStakeVaults(context.Background(), s.vaults[:1], 0.5, nil)
+
+	// Assert
+	s.Require().NoError(err)
+	v := out[0]
+	reported := v.Vault.DailyRewardUSD / v.Vault.TotalStakedAmountUSD
+	s.InDelta(0.02, reported, 1e-9)
+	s.NotEqual(reported, v.FeeFlowPerStakedUSD)
+	s.Greater(v.FeeFlowPerStakedUSD, reported)
+}
+
+func (s *StakeVaultTestSuite) TestEnrichStakeVaultsRequiresShare() {
+	tests := []struct {
+		name  string
+		share float64
+	}{
+		{name: "should reject a zero share", share: 0},
+		{name: "should reject a negative share", share: -0.1},
+		{name: "should reject a share above one", share: 1.5},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			out, err := s.client.EnrichStakeVaults(context.Background(), s.vaults, tt.share, nil)
+
+			// Assert
+			s.ErrorContains(err, "meteora.EnrichStakeVaults: staker share")
+			s.Nil(out)
+		})
+	}
+	s.Empty(s.srv.Requests())
+}
+
+func (s *StakeVaultTestSuite) TestEnrichStakeVaultsWithoutFees() {
+	// Act
+	out, err := s.client.EnrichStakeVaults(context.Background(), s.vaults, 0.5, nil)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NoError(out[1].Err)
+	s.Zero(out[1].ImpliedStakerShare)
+	s.Zero(out[1].FeeFlowPerStakedUSD)
+	s.ErrorIs(out[2].Err, dammv1.ErrPoolNotFound)
+	s.ErrorContains(out[2].Err, "meteora.EnrichStakeVaults: vault3")
+	s.Nil(out[2].Pool)
+	s.Len(s.srv.Requests(), 1)
+}
+
+func (s *StakeVaultTestSuite) TestEnrichStakeVaultsWithoutPoolAddress() {
+	// Arrange
+	vaults := []stake2earn.Vault{{VaultAddress: "vault4", TotalStakedAmountUSD: 1000}}
+
+	// Act
+	out, err := s.client.EnrichStakeVaults(context.Background(), vaults, 0.5, nil)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(out, 1)
+	s.ErrorIs(out[0].Err, dammv1.ErrPoolNotFound)
+	s.ErrorContains(out[0].Err, "meteora.EnrichStakeVaults: vault4")
+	s.Nil(out[0].Pool)
+	s.Zero(out[0].FeeFlowPerStakedUSD)
+}
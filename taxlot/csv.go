@@ -0,0 +1,132 @@
+package taxlot
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ua1984/meteora-go/decimal"
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+// koinlyTimeLayout is the date format accepted by the Koinly universal importer.
+const koinlyTimeLayout = "2006-01-02 15:04:05 UTC"
+
+// WriteCSV writes the ledger entries as an accounting CSV with one row per
+// entry. Amounts are written in token units and USD.
+func (l *Ledger) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{
+		"date", "type", "event_type", "token", "symbol", "quantity",
+		"value_usd", "cost_basis_usd", "gain_usd", "unmatched_quantity",
+		"acquired_at", "method", "pool_address", "position_address", "signature",
+	}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("taxlot.WriteCSV: %w", err)
+	}
+
+	for _, e := range l.Entries {
+		row := []string{
+			e.Time.Format(time.RFC3339),
+			string(e.Type),
+			string(e.EventType),
+			e.Token,
+			l.symbol(e.Token),
+			formatAmount(e.Quantity),
+			formatAmount(e.ValueUSD),
+			"", "", "", "",
+			"",
+			e.PoolAddress,
+			e.PositionAddress,
+			e.Signature,
+		}
+		if e.Type == EntryTypeDisposal {
+			row[7] = formatAmount(e.CostBasisUSD)
+			row[8] = formatAmount(e.GainUSD)
+			row[9] = formatAmount(e.UnmatchedQuantity)
+			if !e.AcquiredAt.IsZero() {
+				row[10] = e.AcquiredAt.Format(time.RFC3339)
+			}
+			row[11] = string(l.Method)
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("taxlot.WriteCSV: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("taxlot.WriteCSV: %w", err)
+	}
+
+	return nil
+}
+
+// WriteKoinly writes the ledger entries in the Koinly universal CSV format,
+// which is also accepted by CoinTracker-style importers. Deposits and
+// withdrawals are labeled "add to pool" and "remove from pool"; fee and
+// reward claims are labeled "income".
+func (l *Ledger) WriteKoinly(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{
+		"Date", "Sent Amount", "Sent Currency", "Received Amount", "Received Currency",
+		"Fee Amount", "Fee Currency", "Net Worth Amount", "Net Worth Currency",
+		"Label", "Description", "TxHash",
+	}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("taxlot.WriteKoinly: %w", err)
+	}
+
+	for _, e := range l.Entries {
+		row := make([]string, len(header))
+		row[0] = e.Time.Format(koinlyTimeLayout)
+		row[7] = formatAmount(e.ValueUSD)
+		row[8] = "USD"
+		row[10] = fmt.Sprintf("DLMM %s %s", e.EventType, e.PositionAddress)
+		row[11] = e.Signature
+
+		switch e.Type {
+		case EntryTypeAcquisition:
+			row[1] = formatAmount(e.Quantity)
+			row[2] = l.symbol(e.Token)
+			row[9] = "add to pool"
+		case EntryTypeDisposal:
+			row[3] = formatAmount(e.Quantity)
+			row[4] = l.symbol(e.Token)
+			row[9] = "remove from pool"
+		case EntryTypeIncome:
+			row[3] = formatAmount(e.Quantity)
+			row[4] = l.symbol(e.Token)
+			row[9] = "income"
+			if e.EventType == dlmm.PositionEventTypeClaimReward {
+				row[10] = fmt.Sprintf("DLMM farming reward %s", e.PositionAddress)
+			} else {
+				row[10] = fmt.Sprintf("DLMM swap fees %s", e.PositionAddress)
+			}
+		}
+
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("taxlot.WriteKoinly: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("taxlot.WriteKoinly: %w", err)
+	}
+
+	return nil
+}
+
+// symbol returns the ticker symbol of token, or the mint address when unknown.
+func (l *Ledger) symbol(token string) string {
+	if s, ok := l.Symbols[token]; ok && s != "" {
+		return s
+	}
+	return token
+}
+
+func formatAmount(v decimal.Decimal) string {
+	return v.String()
+}
@@ -0,0 +1,35 @@
+// Package taxlot builds tax lots and cost-basis reports from DLMM position events.
+//
+// The exporter walks every open and closed DLMM position of a wallet, fetches the
+// historical events of each position (add, remove, claim_fee, claim_reward) and
+// replays them in block order to produce a Ledger:
+//
+//   - add events open one lot per token at the USD value reported for the deposit.
+//   - remove events dispose of the withdrawn quantity against the open lots of the
+//     same token using the selected Method (FIFO, LIFO or average cost) and report
+//     proceeds, cost basis and realized gain.
+//   - claim_fee and claim_reward events are classified as income at the USD value
+//     reported for the claim. They do not open lots.
+//
+// Lots are tracked per token mint across all positions of the wallet. Because DLMM
+// positions convert between the two tokens as the price moves, a withdrawal can
+// exceed the quantity supplied for one token; the excess is reported as
+// UnmatchedQuantity with a zero cost basis.
+//
+// Quantities and USD values are exact decimals, so partial disposals leave no
+// rounding residue. Events that cannot be parsed are collected in
+// Ledger.Skipped with the reason, and the rest of the ledger is still built.
+//
+// # Usage
+//
+//	client := meteora.New()
+//	exporter := taxlot.NewExporter(client.DLMM)
+//	ledger, err := exporter.Export(ctx, wallet, &taxlot.ExportParams{
+//	    Method: taxlot.MethodFIFO,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	err = ledger.WriteCSV(os.Stdout)   // accounting CSV
+//	err = ledger.WriteKoinly(os.Stdout) // Koinly universal import format
+package taxlot
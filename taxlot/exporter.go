@@ -0,0 +1,151 @@
+package taxlot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+// closedPositionsPageLimit is the page size used when walking closed positions.
+// It is the maximum allowed by the API.
+const closedPositionsPageLimit = 100
+
+// Source is the subset of the DLMM API used by the Exporter.
+// It is implemented by *dlmm.Client.
+type Source interface {
+	GetClosedPositions(ctx context.Context, wallet string, params *dlmm.GetClosedPositionsParams) (*dlmm.ClosedPositionsCursorResponse, error)
+	GetOpenPositions(ctx context.Context, wallet string, params *dlmm.GetOpenPositionsParams) (*dlmm.OpenPositionsResponse, error)
+	GetPositionHistoricalEvents(ctx context.Context, address string, params *dlmm.GetPositionHistoricalEventsParams) (*dlmm.GetPositionHistoricalEventsResponse, error)
+	GetPool(ctx context.Context, address string) (*dlmm.Pool, error)
+}
+
+// ExportParams are optional parameters for the Export method.
+type ExportParams struct {
+	// Method is the lot matching method. Default: MethodFIFO.
+	Method Method
+
+	// StartTime is the Unix timestamp in seconds (inclusive) passed to
+	// GetClosedPositions. If omitted, the API uses its default range.
+	StartTime *int64
+
+	// EndTime is the Unix timestamp in seconds (inclusive) passed to
+	// GetClosedPositions. If omitted, the API uses "now" as the end.
+	EndTime *int64
+}
+
+// Exporter collects the position events of a wallet and builds a Ledger.
+type Exporter struct {
+	source Source
+}
+
+// NewExporter creates a new Exporter reading from source.
+func NewExporter(source Source) *Exporter {
+	return &Exporter{source: source}
+}
+
+// Export walks all open and closed positions of wallet, fetches their events and
+// replays them into a Ledger. Token symbols are resolved from the pools the
+// positions belong to.
+func (e *Exporter) Export(ctx context.Context, wallet string, params *ExportParams) (*Ledger, error) {
+	if params == nil {
+		params = &ExportParams{}
+	}
+	method := params.Method
+	if method == "" {
+		method = MethodFIFO
+	}
+
+	positions, symbols, err := e.positions(ctx, wallet, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []dlmm.PositionEvent
+	var pools []string
+	seenPools := map[string]bool{}
+	for _, position := range positions {
+		resp, err := e.source.GetPositionHistoricalEvents(ctx, position, nil)
+		if err != nil {
+			return nil, fmt.Errorf("taxlot.Export: position %s: %w", position, err)
+		}
+		for _, ev := range resp.Events {
+			if ev.PoolAddress != "" && !seenPools[ev.PoolAddress] {
+				seenPools[ev.PoolAddress] = true
+				pools = append(pools, ev.PoolAddress)
+			}
+		}
+		events = append(events, resp.Events...)
+	}
+
+	for _, pool := range pools {
+		p, err := e.source.GetPool(ctx, pool)
+		if err != nil {
+			return nil, fmt.Errorf("taxlot.Export: pool %s: %w", pool, err)
+		}
+		symbols[p.TokenX.Address] = p.TokenX.Symbol
+		symbols[p.TokenY.Address] = p.TokenY.Symbol
+	}
+
+	ledger, err := Build(events, method)
+	if err != nil {
+		return nil, fmt.Errorf("taxlot.Export: %w", err)
+	}
+	for mint, symbol := range symbols {
+		if mint != "" && symbol != "" {
+			ledger.Symbols[mint] = symbol
+		}
+	}
+
+	return ledger, nil
+}
+
+// positions returns the deduplicated addresses of all open and closed positions
+// of wallet, in discovery order, along with the token symbols reported for open
+// positions.
+func (e *Exporter) positions(ctx context.Context, wallet string, params *ExportParams) ([]string, map[string]string, error) {
+	var positions []string
+	seen := map[string]bool{}
+	add := func(address string) {
+		if !seen[address] {
+			seen[address] = true
+			positions = append(positions, address)
+		}
+	}
+
+	symbols := map[string]string{}
+	open, err := e.source.GetOpenPositions(ctx, wallet, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("taxlot.Export: %w", err)
+	}
+	for _, group := range open.Data {
+		symbols[group.TokenX.Address] = group.TokenX.Symbol
+		symbols[group.TokenY.Address] = group.TokenY.Symbol
+		for _, p := range group.Positions {
+			add(p.PositionAddress)
+		}
+	}
+
+	limit := closedPositionsPageLimit
+	closedParams := &dlmm.GetClosedPositionsParams{
+		StartTime: params.StartTime,
+		EndTime:   params.EndTime,
+		Limit:     &limit,
+	}
+	for {
+		page, err := e.source.GetClosedPositions(ctx, wallet, closedParams)
+		if err != nil {
+			return nil, nil, fmt.Errorf("taxlot.Export: %w", err)
+		}
+		for _, p := range page.Data {
+			add(p.PositionAddress)
+		}
+		if page.NextCursor == nil || *page.NextCursor == "" || len(page.Data) == 0 {
+			break
+		}
+		cursor := *page.NextCursor
+		closedParams.NextCursor = &cursor
+	}
+
+	return positions, symbols, nil
+}
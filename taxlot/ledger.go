@@ -0,0 +1,323 @@
+package taxlot
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ua1984/meteora-go/decimal"
+	"github.com/ua1984/meteora-go/dlmm"
+)
+
+// Method selects how disposals are matched against open lots.
+type Method string
+
+const (
+	// MethodFIFO disposes of the oldest lots first.
+	MethodFIFO Method = "fifo"
+
+	// MethodLIFO disposes of the most recent lots first.
+	MethodLIFO Method = "lifo"
+
+	// MethodAverageCost disposes at the average cost of all open lots of the token.
+	MethodAverageCost Method = "average_cost"
+)
+
+// EntryType classifies a ledger entry for accounting purposes.
+type EntryType string
+
+const (
+	// EntryTypeAcquisition is a lot opened by supplying tokens to a position.
+	EntryTypeAcquisition EntryType = "acquisition"
+
+	// EntryTypeDisposal is a withdrawal of tokens from a position.
+	EntryTypeDisposal EntryType = "disposal"
+
+	// EntryTypeIncome is a fee or reward claim.
+	EntryTypeIncome EntryType = "income"
+)
+
+// Lot is an open quantity of a token with its remaining cost basis.
+type Lot struct {
+	// Token is the mint address of the token.
+	Token string
+
+	// Quantity is the remaining quantity of the lot in token units.
+	Quantity decimal.Decimal
+
+	// CostBasisUSD is the remaining cost basis of the lot in USD.
+	CostBasisUSD decimal.Decimal
+
+	// AcquiredAt is the block time of the event that opened the lot.
+	AcquiredAt time.Time
+
+	// PoolAddress is the pool of the position the lot was supplied to.
+	PoolAddress string
+
+	// PositionAddress is the position the lot was supplied to.
+	PositionAddress string
+
+	// Signature is the transaction signature of the event that opened the lot.
+	Signature string
+}
+
+// Entry is a single accounting record for one token of a position event.
+type Entry struct {
+	// Time is the block time of the event.
+	Time time.Time
+
+	// Type is the accounting classification of the entry.
+	Type EntryType
+
+	// EventType is the DLMM event the entry was derived from.
+	EventType dlmm.PositionEventType
+
+	// Token is the mint address of the token.
+	Token string
+
+	// Quantity is the quantity of the token in token units.
+	Quantity decimal.Decimal
+
+	// ValueUSD is the fair market value in USD reported for the event: the cost
+	// of an acquisition, the proceeds of a disposal or the amount of income.
+	ValueUSD decimal.Decimal
+
+	// CostBasisUSD is the cost basis of the disposed quantity. Set for disposals only.
+	CostBasisUSD decimal.Decimal
+
+	// GainUSD is the realized gain (ValueUSD - CostBasisUSD). Set for disposals only.
+	GainUSD decimal.Decimal
+
+	// AcquiredAt is the acquisition time of the earliest lot matched by a disposal.
+	// It is zero when no lot was matched.
+	AcquiredAt time.Time
+
+	// UnmatchedQuantity is the part of a disposal that exceeded the open lots of
+	// the token and was reported with a zero cost basis.
+	UnmatchedQuantity decimal.Decimal
+
+	// PoolAddress is the pool the event belongs to.
+	PoolAddress string
+
+	// PositionAddress is the position the event belongs to.
+	PositionAddress string
+
+	// Signature is the transaction signature of the event.
+	Signature string
+}
+
+// Ledger is the result of replaying position events with a lot matching method.
+type Ledger struct {
+	// Method is the lot matching method used to build the ledger.
+	Method Method
+
+	// Entries contains the accounting records in block order.
+	Entries []Entry
+
+	// OpenLots contains the lots that were not fully disposed of, ordered by token
+	// and acquisition time.
+	OpenLots []Lot
+
+	// Symbols maps token mint addresses to ticker symbols. Writers fall back to
+	// the mint address for tokens without a symbol.
+	Symbols map[string]string
+
+	// Skipped contains the events that could not be parsed, in input order.
+	// They are left out of Entries and OpenLots.
+	Skipped []SkippedEvent
+}
+
+// SkippedEvent is a position event left out of a ledger.
+type SkippedEvent struct {
+	// Event is the event as returned by the API.
+	Event dlmm.PositionEvent
+
+	// Err is the reason the event was skipped.
+	Err error
+}
+
+// Build replays events in block order and returns the resulting ledger.
+// Events may be passed in any order and from any number of positions. An event
+// that cannot be parsed is recorded in Ledger.Skipped instead of failing the
+// ledger, so one malformed event does not hide the others. Build only returns
+// an error for an unknown method.
+func Build(events []dlmm.PositionEvent, method Method) (*Ledger, error) {
+	switch method {
+	case MethodFIFO, MethodLIFO, MethodAverageCost:
+	default:
+		return nil, fmt.Errorf("taxlot.Build: unknown method %q", method)
+	}
+
+	ledger := &Ledger{Method: method, Symbols: map[string]string{}}
+	sorted := make([]dlmm.ParsedPositionEvent, 0, len(events))
+	for _, ev := range events {
+		parsed, err := ev.Parse()
+		if err != nil {
+			ledger.Skipped = append(ledger.Skipped, SkippedEvent{
+				Event: ev,
+				Err:   fmt.Errorf("taxlot.Build: position %s: event %s: %w", ev.PositionAddress, ev.Signature, err),
+			})
+			continue
+		}
+		sorted = append(sorted, parsed)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].BlockTime.Equal(sorted[j].BlockTime) {
+			return sorted[i].BlockTime.Before(sorted[j].BlockTime)
+		}
+		if sorted[i].Slot != sorted[j].Slot {
+			return sorted[i].Slot < sorted[j].Slot
+		}
+		return sorted[i].IxIndex < sorted[j].IxIndex
+	})
+
+	b := &builder{
+		ledger: ledger,
+		lots:   map[string][]Lot{},
+	}
+	for _, ev := range sorted {
+		b.apply(ev)
+	}
+
+	tokens := make([]string, 0, len(b.lots))
+	for token := range b.lots {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	for _, token := range tokens {
+		b.ledger.OpenLots = append(b.ledger.OpenLots, b.lots[token]...)
+	}
+
+	return b.ledger, nil
+}
+
+// builder holds the open lots while events are replayed.
+type builder struct {
+	ledger *Ledger
+	lots   map[string][]Lot
+}
+
+// tokenAmount is one side of a position event.
+type tokenAmount struct {
+	token    string
+	quantity decimal.Decimal
+	usd      decimal.Decimal
+}
+
+func (b *builder) apply(ev dlmm.ParsedPositionEvent) {
+	for _, a := range eventAmounts(ev) {
+		entry := Entry{
+			Time:            ev.BlockTime,
+			EventType:       ev.EventType,
+			Token:           a.token,
+			Quantity:        a.quantity,
+			ValueUSD:        a.usd,
+			PoolAddress:     ev.PoolAddress,
+			PositionAddress: ev.PositionAddress,
+			Signature:       ev.Signature.String(),
+		}
+
+		switch ev.EventType {
+		case dlmm.PositionEventTypeAdd:
+			entry.Type = EntryTypeAcquisition
+			b.lots[a.token] = append(b.lots[a.token], Lot{
+				Token:           a.token,
+				Quantity:        a.quantity,
+				CostBasisUSD:    a.usd,
+				AcquiredAt:      ev.BlockTime,
+				PoolAddress:     ev.PoolAddress,
+				PositionAddress: ev.PositionAddress,
+				Signature:       ev.Signature.String(),
+			})
+		case dlmm.PositionEventTypeRemove:
+			entry.Type = EntryTypeDisposal
+			b.dispose(&entry)
+		case dlmm.PositionEventTypeClaimFee, dlmm.PositionEventTypeClaimReward:
+			entry.Type = EntryTypeIncome
+		}
+
+		b.ledger.Entries = append(b.ledger.Entries, entry)
+	}
+}
+
+// dispose matches entry.Quantity against the open lots of entry.Token and fills
+// in the cost basis, gain and acquisition fields of the entry.
+func (b *builder) dispose(entry *Entry) {
+	lots := b.lots[entry.Token]
+	remaining := entry.Quantity
+
+	if b.ledger.Method == MethodAverageCost {
+		openQty, openCost := decimal.Zero, decimal.Zero
+		for _, lot := range lots {
+			openQty = openQty.Add(lot.Quantity)
+			openCost = openCost.Add(lot.CostBasisUSD)
+		}
+		if openQty.Sign() > 0 {
+			matched := remaining
+			if matched.Cmp(openQty) > 0 {
+				matched = openQty
+			}
+			entry.CostBasisUSD = openCost.Mul(matched).Quo(openQty)
+			entry.AcquiredAt = lots[0].AcquiredAt
+			ratio := decimal.NewFromInt(1).Sub(matched.Quo(openQty))
+			kept := lots[:0]
+			for _, lot := range lots {
+				lot.Quantity = lot.Quantity.Mul(ratio)
+				lot.CostBasisUSD = lot.CostBasisUSD.Mul(ratio)
+				if lot.Quantity.Sign() > 0 {
+					kept = append(kept, lot)
+				}
+			}
+			lots = kept
+			remaining = remaining.Sub(matched)
+		}
+	} else {
+		for remaining.Sign() > 0 && len(lots) > 0 {
+			i := 0
+			if b.ledger.Method == MethodLIFO {
+				i = len(lots) - 1
+			}
+			lot := &lots[i]
+			if entry.AcquiredAt.IsZero() || lot.AcquiredAt.Before(entry.AcquiredAt) {
+				entry.AcquiredAt = lot.AcquiredAt
+			}
+
+			matched := remaining
+			if matched.Cmp(lot.Quantity) > 0 {
+				matched = lot.Quantity
+			}
+			cost := lot.CostBasisUSD.Mul(matched).Quo(lot.Quantity)
+			entry.CostBasisUSD = entry.CostBasisUSD.Add(cost)
+			lot.Quantity = lot.Quantity.Sub(matched)
+			lot.CostBasisUSD = lot.CostBasisUSD.Sub(cost)
+			remaining = remaining.Sub(matched)
+
+			if lot.Quantity.Sign() <= 0 {
+				lots = append(lots[:i], lots[i+1:]...)
+			}
+		}
+	}
+
+	if remaining.Sign() > 0 {
+		entry.UnmatchedQuantity = remaining
+	}
+	entry.GainUSD = entry.ValueUSD.Sub(entry.CostBasisUSD)
+
+	if len(lots) == 0 {
+		delete(b.lots, entry.Token)
+	} else {
+		b.lots[entry.Token] = lots
+	}
+}
+
+// eventAmounts returns the token X and Y sides of an event, skipping zero amounts.
+func eventAmounts(ev dlmm.ParsedPositionEvent) []tokenAmount {
+	var amounts []tokenAmount
+	if !ev.AmountX.IsZero() {
+		amounts = append(amounts, tokenAmount{token: ev.TokenX, quantity: ev.AmountX, usd: ev.AmountXUSD})
+	}
+	if !ev.AmountY.IsZero() {
+		amounts = append(amounts, tokenAmount{token: ev.TokenY, quantity: ev.AmountY, usd: ev.AmountYUSD})
+	}
+	return amounts
+}
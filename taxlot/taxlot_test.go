@@ -0,0 +1,302 @@
+package taxlot_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/ua1984/meteora-go/decimal"
+	"github.com/ua1984/meteora-go/dlmm"
+	"github.com/ua1984/meteora-go/internal/httpclient"
+	"github.com/ua1984/meteora-go/taxlot"
+)
+
+type TaxLotTestSuite struct {
+	suite.Suite
+}
+
+func TestTaxLot(t *testing.T) {
+	suite.Run(t, new(TaxLotTestSuite))
+}
+
+// signature returns a deterministic, valid base58 transaction signature for label.
+func signature(label string) string {
+	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	sum := sha512.Sum512([]byte(label))
+	n := new(big.Int).SetBytes(sum[:])
+	var out []byte
+	mod := new(big.Int)
+	for n.Sign() > 0 {
+		n.QuoRem(n, big.NewInt(58), mod)
+		out = append([]byte{alphabet[mod.Int64()]}, out...)
+	}
+	return string(out)
+}
+
+func event(sig string, blockTime int64, eventType dlmm.PositionEventType, amountX, usdX, amountY, usdY string) dlmm.PositionEvent {
+	return dlmm.PositionEvent{
+		Signature:       signature(sig),
+		BlockTime:       blockTime,
+		EventType:       string(eventType),
+		PoolAddress:     "pool1",
+		PositionAddress: "pos1",
+		TokenX:          "mintX",
+		TokenY:          "mintY",
+		AmountX:         amountX,
+		AmountXUsd:      usdX,
+		AmountY:         amountY,
+		AmountYUsd:      usdY,
+	}
+}
+
+func (s *TaxLotTestSuite) TestBuild() {
+	events := []dlmm.PositionEvent{
+		// Deliberately out of order: Build sorts by block time.
+		event("sig3", 300, dlmm.PositionEventTypeRemove, "15", "300", "", ""),
+		event("sig1", 100, dlmm.PositionEventTypeAdd, "10", "100", "", ""),
+		event("sig2", 200, dlmm.PositionEventTypeAdd, "10", "150", "", ""),
+		event("sig4", 400, dlmm.PositionEventTypeClaimFee, "0.5", "10", "1", "1"),
+	}
+
+	tests := []struct {
+		name         string
+		method       taxlot.Method
+		wantCost     string
+		wantGain     string
+		wantOpenQty  string
+		wantOpenCost string
+	}{
+		{
+			name:         "should match oldest lots first with FIFO",
+			method:       taxlot.MethodFIFO,
+			wantCost:     "175",
+			wantGain:     "125",
+			wantOpenQty:  "5",
+			wantOpenCost: "75",
+		},
+		{
+			name:         "should match newest lots first with LIFO",
+			method:       taxlot.MethodLIFO,
+			wantCost:     "200",
+			wantGain:     "100",
+			wantOpenQty:  "5",
+			wantOpenCost: "50",
+		},
+		{
+			name:         "should match at average cost",
+			method:       taxlot.MethodAverageCost,
+			wantCost:     "187.5",
+			wantGain:     "112.5",
+			wantOpenQty:  "5",
+			wantOpenCost: "62.5",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			ledger, err := taxlot.Build(events, tt.method)
+
+			// Assert
+			s.Require().NoError(err)
+			s.Require().Len(ledger.Entries, 5)
+
+			s.Equal(taxlot.EntryTypeAcquisition, ledger.Entries[0].Type)
+			s.Equal(signature("sig1"), ledger.Entries[0].Signature)
+
+			disposal := ledger.Entries[2]
+			s.Equal(taxlot.EntryTypeDisposal, disposal.Type)
+			s.Equal(tt.wantCost, disposal.CostBasisUSD.String())
+			s.Equal(tt.wantGain, disposal.GainUSD.String())
+			s.True(disposal.UnmatchedQuantity.IsZero())
+
+			s.Equal(taxlot.EntryTypeIncome, ledger.Entries[3].Type)
+			s.Equal(taxlot.EntryTypeIncome, ledger.Entries[4].Type)
+			s.Equal("mintY", ledger.Entries[4].Token)
+
+			openQty, openCost := decimal.Zero, decimal.Zero
+			for _, lot := range ledger.OpenLots {
+				openQty = openQty.Add(lot.Quantity)
+				openCost = openCost.Add(lot.CostBasisUSD)
+			}
+			s.Equal(tt.wantOpenQty, openQty.String())
+			s.Equal(tt.wantOpenCost, openCost.String())
+		})
+	}
+}
+
+func (s *TaxLotTestSuite) TestBuildUnmatchedDisposal() {
+	// Arrange
+	events := []dlmm.PositionEvent{
+		event("sig1", 100, dlmm.PositionEventTypeAdd, "1", "10", "", ""),
+		event("sig2", 200, dlmm.PositionEventTypeRemove, "3", "45", "", ""),
+	}
+
+	// Act
+	ledger, err := taxlot.Build(events, taxlot.MethodFIFO)
+
+	// Assert
+	s.Require().NoError(err)
+	disposal := ledger.Entries[1]
+	s.Equal("2", disposal.UnmatchedQuantity.String())
+	s.Equal("10", disposal.CostBasisUSD.String())
+	s.Equal("35", disposal.GainUSD.String())
+	s.Equal(time.Unix(100, 0).UTC(), disposal.AcquiredAt)
+	s.Empty(ledger.OpenLots)
+}
+
+func (s *TaxLotTestSuite) TestBuildExactFractions() {
+	// Arrange
+	events := []dlmm.PositionEvent{
+		event("sig1", 100, dlmm.PositionEventTypeAdd, "3", "10", "", ""),
+		event("sig2", 200, dlmm.PositionEventTypeRemove, "1", "5", "", ""),
+		event("sig3", 300, dlmm.PositionEventTypeRemove, "1", "5", "", ""),
+		event("sig4", 400, dlmm.PositionEventTypeRemove, "1", "5", "", ""),
+	}
+
+	// Act
+	ledger, err := taxlot.Build(events, taxlot.MethodAverageCost)
+
+	// Assert
+	s.Require().NoError(err)
+	total := decimal.Zero
+	for _, e := range ledger.Entries[1:] {
+		total = total.Add(e.CostBasisUSD)
+	}
+	s.Equal("10", total.String())
+	s.Empty(ledger.OpenLots)
+}
+
+func (s *TaxLotTestSuite) TestBuildSkipsMalformedEvents() {
+	// Arrange
+	events := []dlmm.PositionEvent{
+		event("sig1", 100, dlmm.PositionEventTypeAdd, "1", "10", "", ""),
+		event("sig2", 200, "swap", "1", "1", "", ""),
+		event("sig3", 300, dlmm.PositionEventTypeAdd, "abc", "1", "", ""),
+		event("sig4", 400, dlmm.PositionEventTypeRemove, "1", "15", "", ""),
+	}
+
+	// Act
+	ledger, err := taxlot.Build(events, taxlot.MethodFIFO)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Len(ledger.Entries, 2)
+	s.Equal("5", ledger.Entries[1].GainUSD.String())
+	s.Require().Len(ledger.Skipped, 2)
+	s.Equal(signature("sig2"), ledger.Skipped[0].Event.Signature)
+	s.ErrorContains(ledger.Skipped[0].Err, "event "+signature("sig2"))
+	s.Equal(signature("sig3"), ledger.Skipped[1].Event.Signature)
+	s.Error(ledger.Skipped[1].Err)
+}
+
+func (s *TaxLotTestSuite) TestBuildUnknownMethod() {
+	// Act
+	ledger, err := taxlot.Build(nil, taxlot.Method("hifo"))
+
+	// Assert
+	s.Error(err)
+	s.Nil(ledger)
+}
+
+func (s *TaxLotTestSuite) TestExport() {
+	// Arrange
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("/wallets/wallet1/open_positions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, dlmm.OpenPositionsResponse{Data: []dlmm.PositionsByPool{{
+			PoolAddress: "pool1",
+			TokenX:      dlmm.PositionToken{Address: "mintX", Symbol: "SOL"},
+			TokenY:      dlmm.PositionToken{Address: "mintY", Symbol: "USDC"},
+			Positions:   []dlmm.OpenPosition{{PositionAddress: "pos1"}},
+		}}})
+	})
+	mux.HandleFunc("/wallets/wallet1/closed_positions", func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("100", r.URL.Query().Get("limit"))
+		if r.URL.Query().Get("next_cursor") == "" {
+			writeJSON(w, dlmm.ClosedPositionsCursorResponse{
+				Data:       []dlmm.ClosedPosition{{PositionAddress: "pos2"}, {PositionAddress: "pos1"}},
+				NextCursor: ptr("c1"),
+			})
+			return
+		}
+		writeJSON(w, dlmm.ClosedPositionsCursorResponse{Data: []dlmm.ClosedPosition{{PositionAddress: "pos3"}}})
+	})
+	mux.HandleFunc("/positions/", func(w http.ResponseWriter, r *http.Request) {
+		position := strings.Split(r.URL.Path, "/")[2]
+		ev := event("sig-"+position, 100, dlmm.PositionEventTypeAdd, "1", "100", "", "")
+		ev.PositionAddress = position
+		writeJSON(w, dlmm.GetPositionHistoricalEventsResponse{Events: []dlmm.PositionEvent{ev}})
+	})
+	mux.HandleFunc("/pools/pool1", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, dlmm.Pool{
+			Address: "pool1",
+			TokenX:  dlmm.Token{Address: "mintX", Symbol: "SOL"},
+			TokenY:  dlmm.Token{Address: "mintY", Symbol: "USDC"},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	exporter := taxlot.NewExporter(dlmm.NewClient(httpclient.New(server.URL, nil)))
+
+	// Act
+	ledger, err := exporter.Export(context.Background(), "wallet1", nil)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(taxlot.MethodFIFO, ledger.Method)
+	s.Len(ledger.Entries, 3)
+	s.Len(ledger.OpenLots, 3)
+	s.Equal("SOL", ledger.Symbols["mintX"])
+}
+
+func (s *TaxLotTestSuite) TestWriters() {
+	// Arrange
+	ledger, err := taxlot.Build([]dlmm.PositionEvent{
+		event("sig1", 0, dlmm.PositionEventTypeAdd, "2", "20", "", ""),
+		event("sig2", 60, dlmm.PositionEventTypeRemove, "1", "15", "", ""),
+		event("sig3", 120, dlmm.PositionEventTypeClaimReward, "0.1", "1.5", "", ""),
+	}, taxlot.MethodFIFO)
+	s.Require().NoError(err)
+	ledger.Symbols["mintX"] = "SOL"
+
+	s.Run("should write accounting CSV", func() {
+		// Act
+		var buf bytes.Buffer
+		err := ledger.WriteCSV(&buf)
+
+		// Assert
+		s.NoError(err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		s.Len(lines, 4)
+		s.Equal("1970-01-01T00:01:00Z,disposal,remove,mintX,SOL,1,15,10,5,0,1970-01-01T00:00:00Z,fifo,pool1,pos1,"+signature("sig2"), lines[2])
+	})
+
+	s.Run("should write Koinly universal format", func() {
+		// Act
+		var buf bytes.Buffer
+		err := ledger.WriteKoinly(&buf)
+
+		// Assert
+		s.NoError(err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		s.Len(lines, 4)
+		s.Equal("1970-01-01 00:00:00 UTC,2,SOL,,,,,20,USD,add to pool,DLMM add pos1,"+signature("sig1"), lines[1])
+		s.Equal("1970-01-01 00:02:00 UTC,,,0.1,SOL,,,1.5,USD,income,DLMM farming reward pos1,"+signature("sig3"), lines[3])
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}